@@ -15,20 +15,27 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"fmt"
+	"io"
 	stdlog "log"
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	logging "github.com/prometheus-operator/prometheus-operator/internal/log"
 	"github.com/prometheus-operator/prometheus-operator/pkg/versionutil"
 
+	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/oklog/run"
 	"github.com/prometheus/client_golang/prometheus"
@@ -46,6 +53,9 @@ const (
 
 	statefulsetOrdinalEnvvar            = "STATEFULSET_ORDINAL_NUMBER"
 	statefulsetOrdinalFromEnvvarDefault = "POD_NAME"
+
+	httpReloadMethod   = "http"
+	signalReloadMethod = "signal"
 )
 
 func main() {
@@ -85,6 +95,15 @@ func main() {
 	reloadURL := app.Flag("reload-url", "reload URL to trigger Prometheus reload on").
 		Default("http://127.0.0.1:9090/-/reload").URL()
 
+	reloadMethod := app.Flag("reload-method", "method used to reload the configuration").
+		Default(httpReloadMethod).Enum(httpReloadMethod, signalReloadMethod)
+
+	processName := app.Flag("process-executable-name", "executable name of the process receiving the SIGHUP signal when the reload method is 'signal'").
+		Default("prometheus").String()
+
+	skipInitialReload := app.Flag("skip-initial-reload", "don't trigger a reload when the configuration applied for the first time is identical to the output file found at startup (e.g. generated by an init container) because the process has already loaded it").
+		Bool()
+
 	versionutil.RegisterIntoKingpinFlags(app)
 
 	if _, err := app.Parse(os.Args[1:]); err != nil {
@@ -135,6 +154,17 @@ func main() {
 		)
 
 		client := createHTTPClient()
+		if *reloadMethod == signalReloadMethod {
+			// The reloader only knows how to call a reload URL. Running the
+			// reload function from the client's transport keeps its retry
+			// logic and the reload metrics.
+			client.Transport = &reloadFuncRoundTripper{
+				reload: newSignalReloadFunc(*processName, "/proc", syscall.Kill),
+			}
+		}
+		if *skipInitialReload {
+			client.Transport = newSkipFirstRoundTripper(logger, client.Transport, *cfgSubstFile)
+		}
 		rel.SetHttpClient(client)
 
 		g.Add(func() error {
@@ -181,6 +211,165 @@ func createHTTPClient() http.Client {
 	}
 }
 
+// reloadFunc triggers a configuration reload of the target process.
+type reloadFunc func(context.Context) error
+
+// newSignalReloadFunc returns a reloadFunc which sends a SIGHUP signal to all
+// the processes with the given executable name, using the kill function. It
+// requires the process namespace to be shared with the target process.
+func newSignalReloadFunc(processName, procDir string, kill func(int, syscall.Signal) error) reloadFunc {
+	return func(context.Context) error {
+		pids, err := findProcesses(procDir, processName)
+		if err != nil {
+			return fmt.Errorf("failed to list processes: %w", err)
+		}
+
+		if len(pids) == 0 {
+			return fmt.Errorf("no process found with executable name %q", processName)
+		}
+
+		for _, pid := range pids {
+			if err := kill(pid, syscall.SIGHUP); err != nil {
+				return fmt.Errorf("failed to send SIGHUP to process %d: %w", pid, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// reloadFuncRoundTripper is a http.RoundTripper which runs the reload
+// function instead of performing the HTTP request. The reload error is
+// returned as is to the reloader which retries the reload.
+type reloadFuncRoundTripper struct {
+	reload reloadFunc
+}
+
+func (r *reloadFuncRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := r.reload(req.Context()); err != nil {
+		return nil, err
+	}
+
+	return okResponse(req), nil
+}
+
+// skipFirstRoundTripper is a http.RoundTripper which acknowledges the first
+// request without performing it if the generated configuration file hasn't
+// changed since the round tripper was created and delegates all the other
+// requests. It skips the reload triggered by the reloader when it applies for
+// the first time the configuration that the process has already loaded at
+// startup.
+type skipFirstRoundTripper struct {
+	logger        log.Logger
+	next          http.RoundTripper
+	cfgOutputFile string
+	// initialHash is the hash of the configuration file when the round
+	// tripper was created. It is nil if the file couldn't be read.
+	initialHash []byte
+	done        bool
+}
+
+// newSkipFirstRoundTripper returns a skipFirstRoundTripper. It must be called
+// before the reloader writes cfgOutputFile for the first time.
+func newSkipFirstRoundTripper(logger log.Logger, next http.RoundTripper, cfgOutputFile string) *skipFirstRoundTripper {
+	s := &skipFirstRoundTripper{
+		logger:        logger,
+		next:          next,
+		cfgOutputFile: cfgOutputFile,
+	}
+
+	if cfgOutputFile == "" {
+		level.Warn(logger).Log("msg", "the initial reload can't be skipped without a configuration output file")
+		return s
+	}
+
+	h, err := hashFile(cfgOutputFile)
+	if err != nil {
+		level.Warn(logger).Log("msg", "the initial reload won't be skipped", "err", err)
+		return s
+	}
+	s.initialHash = h
+
+	return s
+}
+
+func (s *skipFirstRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The reloader sends the requests sequentially.
+	if s.done || s.initialHash == nil {
+		return s.next.RoundTrip(req)
+	}
+	s.done = true
+
+	h, err := hashFile(s.cfgOutputFile)
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "not skipping the initial reload", "err", err)
+		return s.next.RoundTrip(req)
+	}
+
+	if !bytes.Equal(h, s.initialHash) {
+		level.Info(s.logger).Log("msg", "not skipping the initial reload because the configuration has changed since startup")
+		return s.next.RoundTrip(req)
+	}
+
+	level.Info(s.logger).Log("msg", "skipping the initial reload because the configuration hasn't changed since startup")
+	return okResponse(req), nil
+}
+
+// hashFile returns the SHA256 hash of the file's content.
+func hashFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.Sum256(b)
+	return h[:], nil
+}
+
+// okResponse returns a successful empty response to the given request.
+func okResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}
+}
+
+// findProcesses returns the PIDs of the processes whose executable name
+// matches the given name. procDir is the mount point of the proc filesystem.
+func findProcesses(procDir, name string) ([]int, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+
+		cmdline, err := os.ReadFile(filepath.Join(procDir, e.Name(), "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			// The process may have exited in the meantime.
+			continue
+		}
+
+		exe := strings.SplitN(string(cmdline), "\x00", 2)[0]
+		if filepath.Base(exe) == name {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+}
+
 func createOrdinalEnvvar(fromName string) error {
 	reg := regexp.MustCompile(`\d+$`)
 	val := reg.FindString(os.Getenv(fromName))
@@ -15,13 +15,21 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/go-kit/log"
 	"github.com/go-test/deep"
 )
 
@@ -77,3 +85,227 @@ func TestCreateHTTPClient(t *testing.T) {
 		}
 	})
 }
+
+func TestSkipFirstRoundTripper(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// initial is the content of the configuration file when the round
+		// tripper is created, no file is created if empty.
+		initial string
+		// applied is the content of the configuration file when the first
+		// request is sent.
+		applied  string
+		expected []int
+	}{
+		{
+			name:     "unchanged configuration",
+			initial:  "foo",
+			applied:  "foo",
+			expected: []int{0, 1, 2},
+		},
+		{
+			name:     "changed configuration",
+			initial:  "foo",
+			applied:  "bar",
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "no initial configuration",
+			applied:  "foo",
+			expected: []int{1, 2, 3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var reloads int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reloads++
+			}))
+			defer srv.Close()
+
+			cfgFile := filepath.Join(t.TempDir(), "prometheus.env.yaml")
+			if tc.initial != "" {
+				if err := os.WriteFile(cfgFile, []byte(tc.initial), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			client := http.Client{Transport: newSkipFirstRoundTripper(log.NewNopLogger(), http.DefaultTransport, cfgFile)}
+
+			if err := os.WriteFile(cfgFile, []byte(tc.applied), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, expected := range tc.expected {
+				resp, err := client.Post(srv.URL, "", nil)
+				if err != nil {
+					t.Fatalf("request %d: unexpected error: %v", i, err)
+				}
+				resp.Body.Close()
+
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("request %d: expected status code 200, got %d", i, resp.StatusCode)
+				}
+				if reloads != expected {
+					t.Fatalf("request %d: expected %d reloads, got %d", i, expected, reloads)
+				}
+			}
+		})
+	}
+}
+
+// writeProcDir creates a fake proc filesystem with the given command lines
+// indexed by PID.
+func writeProcDir(t *testing.T, cmdlines map[int]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for pid, cmdline := range cmdlines {
+		if err := os.Mkdir(filepath.Join(dir, strconv.Itoa(pid)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, strconv.Itoa(pid), "cmdline"), []byte(cmdline), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Entries which aren't processes should be ignored.
+	if err := os.Mkdir(filepath.Join(dir, "self"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestFindProcesses(t *testing.T) {
+	dir := writeProcDir(t, map[int]string{
+		10:          "/bin/prometheus\x00--config.file=/etc/prometheus/prometheus.yml\x00",
+		11:          "prometheus\x00",
+		12:          "/bin/prometheus-config-reloader\x00--reload-method=signal\x00",
+		13:          "",
+		os.Getpid(): "/bin/prometheus\x00",
+	})
+
+	for _, tc := range []struct {
+		name     string
+		process  string
+		expected []int
+	}{
+		{
+			name:     "matching processes",
+			process:  "prometheus",
+			expected: []int{10, 11},
+		},
+		{
+			name:     "exact executable name",
+			process:  "prometheus-config-reloader",
+			expected: []int{12},
+		},
+		{
+			name:    "no matching process",
+			process: "alertmanager",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pids, err := findProcesses(dir, tc.process)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(pids, tc.expected) {
+				t.Fatalf("expected PIDs %v, got %v", tc.expected, pids)
+			}
+		})
+	}
+
+	if _, err := findProcesses(filepath.Join(dir, "missing"), "prometheus"); err == nil {
+		t.Fatal("expected error for missing proc directory, got none")
+	}
+}
+
+func TestSignalReloadFunc(t *testing.T) {
+	dir := writeProcDir(t, map[int]string{
+		10: "/bin/prometheus\x00",
+		11: "/bin/prometheus\x00",
+	})
+
+	for _, tc := range []struct {
+		name     string
+		process  string
+		killErr  error
+		expected []int
+		err      bool
+	}{
+		{
+			name:     "signal all processes",
+			process:  "prometheus",
+			expected: []int{10, 11},
+		},
+		{
+			name:    "no process",
+			process: "alertmanager",
+			err:     true,
+		},
+		{
+			name:     "kill failure",
+			process:  "prometheus",
+			killErr:  syscall.EPERM,
+			expected: []int{10},
+			err:      true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var signaled []int
+			kill := func(pid int, sig syscall.Signal) error {
+				if sig != syscall.SIGHUP {
+					t.Fatalf("expected SIGHUP, got %v", sig)
+				}
+				signaled = append(signaled, pid)
+				return tc.killErr
+			}
+
+			err := newSignalReloadFunc(tc.process, dir, kill)(context.Background())
+			if tc.err && err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(signaled, tc.expected) {
+				t.Fatalf("expected signaled PIDs %v, got %v", tc.expected, signaled)
+			}
+		})
+	}
+}
+
+func TestReloadFuncRoundTripper(t *testing.T) {
+	var (
+		reloads   int
+		reloadErr error
+	)
+	client := http.Client{Transport: &reloadFuncRoundTripper{
+		reload: func(context.Context) error {
+			reloads++
+			return reloadErr
+		},
+	}}
+
+	resp, err := client.Post("http://127.0.0.1:9090/-/reload", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status code 200, got %d", resp.StatusCode)
+	}
+
+	reloadErr = errors.New("reload failed")
+	if _, err = client.Post("http://127.0.0.1:9090/-/reload", "", nil); !errors.Is(err, reloadErr) {
+		t.Fatalf("expected error %v, got %v", reloadErr, err)
+	}
+
+	if reloads != 2 {
+		t.Fatalf("expected 2 reloads, got %d", reloads)
+	}
+}
@@ -37,6 +37,7 @@ function(params) {
   '0servicemonitorCustomResourceDefinition': import 'servicemonitors-crd.json',
   '0podmonitorCustomResourceDefinition': import 'podmonitors-crd.json',
   '0probeCustomResourceDefinition': import 'probes-crd.json',
+  '0scrapeconfigCustomResourceDefinition': import 'scrapeconfigs-crd.json',
   '0prometheusruleCustomResourceDefinition': import 'prometheusrules-crd.json',
   '0thanosrulerCustomResourceDefinition': import 'thanosrulers-crd.json',
 
@@ -81,6 +82,7 @@ function(params) {
           'servicemonitors',
           'podmonitors',
           'probes',
+          'scrapeconfigs',
           'prometheusrules',
         ],
         verbs: ['*'],
@@ -100,6 +102,11 @@ function(params) {
         resources: ['pods'],
         verbs: ['list', 'delete'],
       },
+      {
+        apiGroups: [''],
+        resources: ['pods/proxy'],
+        verbs: ['get'],
+      },
       {
         apiGroups: [''],
         resources: [
@@ -276,7 +276,12 @@ func (a *Admission) validatePrometheusRules(ar v1.AdmissionReview) *v1.Admission
 		return toAdmissionResponseFailure("Rules are not valid", prometheusRuleResource, errors)
 	}
 
-	return &v1.AdmissionResponse{Allowed: true}
+	var warnings []string
+	for _, alert := range promRule.Spec.DuplicateAlertNames() {
+		warnings = append(warnings, fmt.Sprintf("alerting rule %q is defined in more than one group", alert))
+	}
+
+	return &v1.AdmissionResponse{Allowed: true, Warnings: warnings}
 }
 
 func (a *Admission) validateAlertmanagerConfig(ar v1.AdmissionReview) *v1.AdmissionResponse {
@@ -80,6 +80,21 @@ func TestAdmitGoodRuleExternalLabels(t *testing.T) {
 	}
 }
 
+func TestAdmitRuleWithDuplicateAlertNames(t *testing.T) {
+	ts := server(api().servePrometheusRulesValidate)
+	defer ts.Close()
+
+	resp := sendAdmissionReview(t, ts, goodRulesWithDuplicateAlertNames)
+
+	if !resp.Response.Allowed {
+		t.Errorf("Expected admission to be allowed but it was not")
+	}
+
+	if len(resp.Response.Warnings) != 1 || !strings.Contains(resp.Response.Warnings[0], `"Test"`) {
+		t.Errorf("Expected a warning about the duplicated alert name but got %v", resp.Response.Warnings)
+	}
+}
+
 func TestAdmitBadRule(t *testing.T) {
 	ts := server(api().servePrometheusRulesValidate)
 	defer ts.Close()
@@ -911,6 +926,73 @@ var goodRulesWithExternalLabelsInAnnotations = []byte(`
 }
 `)
 
+var goodRulesWithDuplicateAlertNames = []byte(`
+{
+  "kind": "AdmissionReview",
+  "apiVersion": "admission.k8s.io/v1",
+  "request": {
+    "uid": "87c5df7f-5090-11e9-b9b4-02425473f309",
+    "kind": {
+      "group": "monitoring.coreos.com",
+      "version": "v1",
+      "kind": "PrometheusRule"
+    },
+    "resource": {
+      "group": "monitoring.coreos.com",
+      "version": "v1",
+      "resource": "prometheusrules"
+    },
+    "namespace": "monitoring",
+    "operation": "CREATE",
+    "userInfo": {
+      "username": "kubernetes-admin",
+      "groups": [
+        "system:masters",
+        "system:authenticated"
+      ]
+    },
+    "object": {
+      "apiVersion": "monitoring.coreos.com/v1",
+      "kind": "PrometheusRule",
+      "metadata": {
+        "creationTimestamp": "2019-03-27T13:02:09Z",
+        "generation": 1,
+        "name": "test",
+        "namespace": "monitoring",
+        "uid": "87c5d31d-5090-11e9-b9b4-02425473f309"
+      },
+      "spec": {
+        "groups": [
+          {
+            "name": "test.rules",
+            "rules": [
+              {
+                "alert": "Test",
+                "expr": "vector(1)",
+                "labels": {
+                  "severity": "critical"
+                }
+              }
+            ]
+          },
+          {
+            "name": "other.rules",
+            "rules": [
+              {
+                "alert": "Test",
+                "expr": "vector(2)"
+              }
+            ]
+          }
+        ]
+      }
+    },
+    "oldObject": null,
+    "dryRun": false
+  }
+}
+`)
+
 var badRulesNoAnnotations = []byte(`
 {
   "kind": "AdmissionReview",
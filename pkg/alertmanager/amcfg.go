@@ -1261,11 +1261,7 @@ func (cb *configBuilder) convertHTTPConfig(ctx context.Context, in monitoringv1a
 		}
 
 		if credentials != "" {
-			authorizationType := in.Authorization.Type
-			if authorizationType == "" {
-				authorizationType = "Bearer"
-			}
-			out.Authorization = &authorization{Type: authorizationType, Credentials: credentials}
+			out.Authorization = &authorization{Type: in.Authorization.EffectiveType(), Credentials: credentials}
 		}
 	}
 
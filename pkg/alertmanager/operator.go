@@ -539,7 +539,7 @@ func (c *Operator) Resolve(ss *appsv1.StatefulSet) metav1.Object {
 }
 
 func statefulSetNameFromAlertmanagerName(name string) string {
-	return "alertmanager-" + name
+	return monitoringv1.AlertmanagerPrefixedName(name)
 }
 
 func statefulSetKeyToAlertmanagerKey(key string) (bool, string) {
@@ -682,7 +682,7 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		return err
 	}
 
-	sset, err := makeStatefulSet(am, c.config, newSSetInputHash, tlsAssets.ShardNames())
+	sset, err := makeStatefulSet(logger, am, c.config, newSSetInputHash, tlsAssets.ShardNames())
 	if err != nil {
 		return errors.Wrap(err, "failed to make statefulset")
 	}
@@ -782,7 +782,7 @@ receivers:
 func (c *Operator) loadConfigurationFromSecret(ctx context.Context, am *monitoringv1.Alertmanager) ([]byte, map[string][]byte, error) {
 	namespacedLogger := log.With(c.logger, "alertmanager", am.Name, "namespace", am.Namespace)
 
-	name := defaultConfigSecretName(am)
+	_, name := am.EffectiveConfigSource()
 
 	// Tentatively retrieve the secret containing the user-provided Alertmanager
 	// configuration.
@@ -796,18 +796,19 @@ func (c *Operator) loadConfigurationFromSecret(ctx context.Context, am *monitori
 		return nil, nil, err
 	}
 
-	if _, ok := secret.Data[alertmanagerConfigFile]; !ok {
+	key := am.Spec.ConfigSecretKey()
+	if _, ok := secret.Data[key]; !ok {
 		level.Info(namespacedLogger).
-			Log("msg", "key not found in the config secret, using default Alertmanager configuration", "secret", name, "key", alertmanagerConfigFile)
+			Log("msg", "key not found in the config secret, using default Alertmanager configuration", "secret", name, "key", key)
 		return defaultAlertmanagerConfiguration(), secret.Data, nil
 	}
 
-	rawAlertmanagerConfig := secret.Data[alertmanagerConfigFile]
-	delete(secret.Data, alertmanagerConfigFile)
+	rawAlertmanagerConfig := secret.Data[key]
+	delete(secret.Data, key)
 
 	if len(rawAlertmanagerConfig) == 0 {
 		level.Info(namespacedLogger).
-			Log("msg", "empty configuration in the config secret, using default Alertmanager configuration", "secret", name, "key", alertmanagerConfigFile)
+			Log("msg", "empty configuration in the config secret, using default Alertmanager configuration", "secret", name, "key", key)
 		rawAlertmanagerConfig = defaultAlertmanagerConfiguration()
 	}
 
@@ -818,15 +819,27 @@ func (c *Operator) provisionAlertmanagerConfiguration(ctx context.Context, am *m
 	namespacedLogger := log.With(c.logger, "alertmanager", am.Name, "namespace", am.Namespace)
 
 	if err := validation.ValidateAlertmanager(am); err != nil {
-		return err
+		if !monitoringv1.IsValidationWarning(err) {
+			return err
+		}
+		level.Warn(namespacedLogger).Log("msg", "alertmanager validation warning", "warning", err.Error())
+	}
+
+	if err := am.Spec.ValidateConfigSource(); err != nil {
+		if !monitoringv1.IsValidationWarning(err) {
+			return err
+		}
+		level.Warn(namespacedLogger).Log("msg", "alertmanager configuration warning", "warning", err.Error())
 	}
 
+	useCRD, secretName := am.EffectiveConfigSource()
+
 	// If no AlertmanagerConfig selectors and AlertmanagerConfiguration are
 	// configured, the user wants to manage configuration themselves.
-	if am.Spec.AlertmanagerConfigSelector == nil && am.Spec.AlertmanagerConfiguration == nil {
+	if am.Spec.AlertmanagerConfigSelector == nil && !useCRD {
 		level.Debug(namespacedLogger).
 			Log("msg", "AlertmanagerConfigSelector and AlertmanagerConfiguration not specified, using the configuration from secret as-is",
-				"secret", defaultConfigSecretName(am))
+				"secret", secretName)
 
 		amRawConfiguration, additionalData, err := c.loadConfigurationFromSecret(ctx, am)
 		if err != nil {
@@ -857,10 +870,13 @@ func (c *Operator) provisionAlertmanagerConfiguration(ctx context.Context, am *m
 		cfgBuilder     = newConfigBuilder(namespacedLogger, version, store)
 	)
 
-	if am.Spec.AlertmanagerConfiguration != nil {
+	if useCRD {
 		// Load the base configuration from the referenced AlertmanagerConfig.
 		globalAmConfig, err := c.mclient.MonitoringV1alpha1().AlertmanagerConfigs(am.Namespace).
 			Get(ctx, am.Spec.AlertmanagerConfiguration.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return errors.Errorf("AlertmanagerConfig %q referenced by alertmanagerConfiguration not found in namespace %q", am.Spec.AlertmanagerConfiguration.Name, am.Namespace)
+		}
 		if err != nil {
 			return errors.Wrap(err, "failed to get global AlertmanagerConfig")
 		}
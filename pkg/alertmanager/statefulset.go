@@ -27,6 +27,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/blang/semver/v4"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/pkg/errors"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
@@ -44,7 +46,6 @@ const (
 	alertmanagerConfigDir                = "/etc/alertmanager/config"
 	webConfigDir                         = "/etc/alertmanager/web_config"
 	alertmanagerConfigOutDir             = "/etc/alertmanager/config_out"
-	alertmanagerConfigFile               = "alertmanager.yaml"
 	alertmanagerConfigFileCompressed     = "alertmanager.yaml.gz"
 	alertmanagerConfigEnvsubstFilename   = "alertmanager.env.yaml"
 	alertmanagerStorageDir               = "/alertmanager"
@@ -57,7 +58,7 @@ var (
 	probeTimeoutSeconds int32 = 3
 )
 
-func makeStatefulSet(am *monitoringv1.Alertmanager, config Config, inputHash string, tlsAssetSecrets []string) (*appsv1.StatefulSet, error) {
+func makeStatefulSet(logger log.Logger, am *monitoringv1.Alertmanager, config Config, inputHash string, tlsAssetSecrets []string) (*appsv1.StatefulSet, error) {
 	// TODO(fabxc): is this the right point to inject defaults?
 	// Ideally we would do it before storing but that's currently not possible.
 	// Potentially an update handler on first insertion.
@@ -83,7 +84,7 @@ func makeStatefulSet(am *monitoringv1.Alertmanager, config Config, inputHash str
 		am.Spec.Resources.Requests[v1.ResourceMemory] = resource.MustParse("200Mi")
 	}
 
-	spec, err := makeStatefulSetSpec(am, config, tlsAssetSecrets)
+	spec, err := makeStatefulSetSpec(logger, am, config, tlsAssetSecrets)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +217,7 @@ func makeStatefulSetService(p *monitoringv1.Alertmanager, config Config) *v1.Ser
 	return svc
 }
 
-func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSecrets []string) (*appsv1.StatefulSetSpec, error) {
+func makeStatefulSetSpec(logger log.Logger, a *monitoringv1.Alertmanager, config Config, tlsAssetSecrets []string) (*appsv1.StatefulSetSpec, error) {
 	amVersion := operator.StringValOrDefault(a.Spec.Version, operator.DefaultAlertmanagerVersion)
 
 	amImagePath, err := operator.BuildImagePath(
@@ -241,10 +242,10 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSe
 		fmt.Sprintf("--data.retention=%s", a.Spec.Retention),
 	}
 
-	if *a.Spec.Replicas == 1 && !a.Spec.ForceEnableClusterMode {
-		amArgs = append(amArgs, "--cluster.listen-address=")
-	} else {
+	if a.Spec.WillFormCluster() {
 		amArgs = append(amArgs, "--cluster.listen-address=[$(POD_IP)]:9094")
+	} else {
+		amArgs = append(amArgs, "--cluster.listen-address=")
 	}
 
 	if a.Spec.ListenLocal {
@@ -289,6 +290,14 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSe
 		amArgs = append(amArgs, fmt.Sprintf("--cluster.peer-timeout=%s", a.Spec.ClusterPeerTimeout))
 	}
 
+	if a.Spec.ClusterLabel != nil {
+		if version.GTE(semver.MustParse("0.25.0")) {
+			amArgs = append(amArgs, fmt.Sprintf("--cluster.label=%s", *a.Spec.ClusterLabel))
+		} else {
+			level.Warn(logger).Log("msg", "ignoring 'clusterLabel' not supported by Alertmanager", "version", version, "minimum_version", "0.25.0")
+		}
+	}
+
 	isHTTPS := a.Spec.Web != nil && a.Spec.Web.TLSConfig != nil && version.GTE(semver.MustParse("0.22.0"))
 
 	livenessProbeHandler := v1.ProbeHandler{
@@ -336,12 +345,7 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSe
 	// We should try to avoid removing such immutable fields whenever possible since doing
 	// so forces us to enter the 'recreate cycle' and can potentially lead to downtime.
 	// The requirement to make a change here should be carefully evaluated.
-	podSelectorLabels := map[string]string{
-		"app.kubernetes.io/name":       "alertmanager",
-		"app.kubernetes.io/managed-by": "prometheus-operator",
-		"app.kubernetes.io/instance":   a.Name,
-		"alertmanager":                 a.Name,
-	}
+	podSelectorLabels := a.SelectorLabels()
 	if a.Spec.PodMetadata != nil {
 		if a.Spec.PodMetadata.Labels != nil {
 			for k, v := range a.Spec.PodMetadata.Labels {
@@ -641,6 +645,9 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSe
 	}
 
 	terminationGracePeriod := int64(120)
+	if a.Spec.TerminationGracePeriodSeconds != nil {
+		terminationGracePeriod = *a.Spec.TerminationGracePeriodSeconds
+	}
 	finalSelectorLabels := config.Labels.Merge(podSelectorLabels)
 	finalLabels := config.Labels.Merge(podLabels)
 
@@ -668,7 +675,7 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSe
 					Drop: []v1.Capability{"ALL"},
 				},
 			},
-			Env: []v1.EnvVar{
+			Env: append([]v1.EnvVar{
 				{
 					// Necessary for '--cluster.listen-address' flag
 					Name: "POD_IP",
@@ -678,7 +685,8 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSe
 						},
 					},
 				},
-			},
+			}, a.Spec.Env...),
+			EnvFrom:                  a.Spec.EnvFrom,
 			TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
 		},
 		operator.CreateConfigReloader(
@@ -694,6 +702,7 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSe
 			operator.LogFormat(a.Spec.LogFormat),
 			operator.LogLevel(a.Spec.LogLevel),
 			operator.WatchedDirectories(watchedDirectories),
+			operator.WatchInterval(a.Spec.ConfigReloaderWatchInterval),
 			operator.VolumeMounts(configReloaderVolumeMounts),
 			operator.Shard(-1),
 			operator.ConfigFile(path.Join(alertmanagerConfigDir, alertmanagerConfigFileCompressed)),
@@ -747,14 +756,6 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config, tlsAssetSe
 	}, nil
 }
 
-func defaultConfigSecretName(am *monitoringv1.Alertmanager) string {
-	if am.Spec.ConfigSecret == "" {
-		return prefixedName(am.Name)
-	}
-
-	return am.Spec.ConfigSecret
-}
-
 func generatedConfigSecretName(name string) string {
 	return prefixedName(name) + "-generated"
 }
@@ -768,7 +769,7 @@ func volumeName(name string) string {
 }
 
 func prefixedName(name string) string {
-	return fmt.Sprintf("alertmanager-%s", name)
+	return monitoringv1.AlertmanagerPrefixedName(name)
 }
 
 func subPathForStorage(s *monitoringv1.StorageSpec) string {
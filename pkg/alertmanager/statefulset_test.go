@@ -20,6 +20,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-kit/log"
 	"github.com/kylelemons/godebug/pretty"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
 )
 
 var (
@@ -73,7 +75,7 @@ func TestStatefulSetLabelingAndAnnotations(t *testing.T) {
 		"app.kubernetes.io/instance":   "",
 	}
 
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      labels,
 			Annotations: annotations,
@@ -105,7 +107,7 @@ func TestStatefulSetStoragePath(t *testing.T) {
 	annotations := map[string]string{
 		"testannotation": "testannotationvalue",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      labels,
 			Annotations: annotations,
@@ -135,7 +137,7 @@ func TestPodLabelsAnnotations(t *testing.T) {
 	labels := map[string]string{
 		"testlabel": "testvalue",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{},
 		Spec: monitoringv1.AlertmanagerSpec{
 			PodMetadata: &monitoringv1.EmbeddedObjectMetadata{
@@ -157,7 +159,7 @@ func TestPodLabelsShouldNotBeSelectorLabels(t *testing.T) {
 	labels := map[string]string{
 		"testlabel": "testvalue",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{},
 		Spec: monitoringv1.AlertmanagerSpec{
 			PodMetadata: &monitoringv1.EmbeddedObjectMetadata{
@@ -193,7 +195,7 @@ func TestStatefulSetPVC(t *testing.T) {
 		},
 	}
 
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      labels,
 			Annotations: annotations,
@@ -224,7 +226,7 @@ func TestStatefulEmptyDir(t *testing.T) {
 		Medium: v1.StorageMediumMemory,
 	}
 
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      labels,
 			Annotations: annotations,
@@ -262,7 +264,7 @@ func TestStatefulSetEphemeral(t *testing.T) {
 		},
 	}
 
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      labels,
 			Annotations: annotations,
@@ -283,7 +285,7 @@ func TestStatefulSetEphemeral(t *testing.T) {
 }
 
 func TestListenLocal(t *testing.T) {
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{
 			ListenLocal: true,
 		},
@@ -317,7 +319,7 @@ func TestListenLocal(t *testing.T) {
 }
 
 func TestListenTLS(t *testing.T) {
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{
 			Web: &monitoringv1.AlertmanagerWebSpec{
 				WebConfigFileFields: monitoringv1.WebConfigFileFields{
@@ -406,7 +408,7 @@ func TestMakeStatefulSetSpecSingleDoubleDashedArgs(t *testing.T) {
 		replicas := int32(3)
 		a.Spec.Replicas = &replicas
 
-		statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+		statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -427,7 +429,7 @@ func TestMakeStatefulSetSpecWebRoutePrefix(t *testing.T) {
 	a.Spec.Version = operator.DefaultAlertmanagerVersion
 	a.Spec.Replicas = &replicas
 
-	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -460,7 +462,7 @@ func TestMakeStatefulSetSpecPeersWithoutClusterDomain(t *testing.T) {
 		},
 	}
 
-	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -495,7 +497,7 @@ func TestMakeStatefulSetSpecPeersWithClusterDomain(t *testing.T) {
 	configWithClusterDomain := defaultTestConfig
 	configWithClusterDomain.ClusterDomain = "custom.cluster"
 
-	statefulSet, err := makeStatefulSetSpec(&a, configWithClusterDomain, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, configWithClusterDomain, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -521,7 +523,7 @@ func TestMakeStatefulSetSpecAdditionalPeers(t *testing.T) {
 	a.Spec.Replicas = &replicas
 	a.Spec.AdditionalPeers = []string{"example.com"}
 
-	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -558,7 +560,7 @@ func TestMakeStatefulSetSpecNotificationTemplates(t *testing.T) {
 			},
 		},
 	}
-	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -588,7 +590,7 @@ func TestMakeStatefulSetSpecNotificationTemplates(t *testing.T) {
 
 func TestAdditionalSecretsMounted(t *testing.T) {
 	secrets := []string{"secret1", "secret2"}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{},
 		Spec: monitoringv1.AlertmanagerSpec{
 			Secrets: secrets,
@@ -648,7 +650,7 @@ func TestAlertManagerDefaultBaseImageFlag(t *testing.T) {
 		"testannotation": "testannotationvalue",
 	}
 
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      labels,
 			Annotations: annotations,
@@ -666,7 +668,7 @@ func TestAlertManagerDefaultBaseImageFlag(t *testing.T) {
 
 func TestSHAAndTagAndVersion(t *testing.T) {
 	{
-		sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 			Spec: monitoringv1.AlertmanagerSpec{
 				Tag:     "my-unrelated-tag",
 				Version: "v0.15.3",
@@ -683,7 +685,7 @@ func TestSHAAndTagAndVersion(t *testing.T) {
 		}
 	}
 	{
-		sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 			Spec: monitoringv1.AlertmanagerSpec{
 				SHA:     "7384a79f4b4991bf8269e7452390249b7c70bcdd10509c8c1c6c6e30e32fb324",
 				Tag:     "my-unrelated-tag",
@@ -702,7 +704,7 @@ func TestSHAAndTagAndVersion(t *testing.T) {
 	}
 	{
 		image := "my-registry/alertmanager:latest"
-		sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 			Spec: monitoringv1.AlertmanagerSpec{
 				SHA:     "7384a79f4b4991bf8269e7452390249b7c70bcdd10509c8c1c6c6e30e32fb324",
 				Tag:     "my-unrelated-tag",
@@ -732,7 +734,7 @@ func TestRetention(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 			Spec: monitoringv1.AlertmanagerSpec{
 				Retention: test.specRetention,
 			},
@@ -758,7 +760,7 @@ func TestRetention(t *testing.T) {
 }
 
 func TestAdditionalConfigMap(t *testing.T) {
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{
 			ConfigMaps: []string{"test-cm1"},
 		},
@@ -801,7 +803,7 @@ func TestSidecarsNoResources(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -830,7 +832,7 @@ func TestSidecarsNoRequests(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -862,7 +864,7 @@ func TestSidecarsNoLimits(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -894,7 +896,7 @@ func TestSidecarsNoCPUResources(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -927,7 +929,7 @@ func TestSidecarsNoCPURequests(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -961,7 +963,7 @@ func TestSidecarsNoCPULimits(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -995,7 +997,7 @@ func TestSidecarsNoMemoryResources(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -1028,7 +1030,7 @@ func TestSidecarsNoMemoryRequests(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -1062,7 +1064,7 @@ func TestSidecarsNoMemoryLimits(t *testing.T) {
 		},
 		AlertmanagerDefaultBaseImage: "quay.io/prometheus/alertmanager",
 	}
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, testConfig, "", nil)
 	if err != nil {
@@ -1086,7 +1088,7 @@ func TestSidecarsNoMemoryLimits(t *testing.T) {
 }
 
 func TestTerminationPolicy(t *testing.T) {
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{},
 	}, defaultTestConfig, "", nil)
 	if err != nil {
@@ -1106,7 +1108,7 @@ func TestClusterListenAddressForSingleReplica(t *testing.T) {
 	a.Spec.Version = operator.DefaultAlertmanagerVersion
 	a.Spec.Replicas = &replicas
 
-	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1126,6 +1128,27 @@ func TestClusterListenAddressForSingleReplica(t *testing.T) {
 	}
 }
 
+func TestClusterListenAddressForSingleReplicaWithAdditionalPeers(t *testing.T) {
+	a := monitoringv1.Alertmanager{}
+	replicas := int32(1)
+	a.Spec.Version = operator.DefaultAlertmanagerVersion
+	a.Spec.Replicas = &replicas
+	a.Spec.AdditionalPeers = []string{"alertmanager.example.com:9094"}
+
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	amArgs := statefulSet.Template.Spec.Containers[0].Args
+
+	for _, arg := range amArgs {
+		if arg == "--cluster.listen-address=" {
+			t.Fatal("expected stateful set to not contain arg '--cluster.listen-address='")
+		}
+	}
+}
+
 func TestClusterListenAddressForSingleReplicaWithForceEnableClusterMode(t *testing.T) {
 	a := monitoringv1.Alertmanager{}
 	replicas := int32(1)
@@ -1133,7 +1156,7 @@ func TestClusterListenAddressForSingleReplicaWithForceEnableClusterMode(t *testi
 	a.Spec.Replicas = &replicas
 	a.Spec.ForceEnableClusterMode = true
 
-	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1159,7 +1182,7 @@ func TestClusterListenAddressForMultiReplica(t *testing.T) {
 	a.Spec.Version = operator.DefaultAlertmanagerVersion
 	a.Spec.Replicas = &replicas
 
-	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1186,7 +1209,7 @@ func TestExpectStatefulSetMinReadySeconds(t *testing.T) {
 	a.Spec.Replicas = &replicas
 
 	// assert defaults to zero if nil
-	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1197,7 +1220,7 @@ func TestExpectStatefulSetMinReadySeconds(t *testing.T) {
 	// assert set correctly if not nil
 	var expect uint32 = 5
 	a.Spec.MinReadySeconds = &expect
-	statefulSet, err = makeStatefulSetSpec(&a, defaultTestConfig, nil)
+	statefulSet, err = makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1248,7 +1271,7 @@ func TestPodTemplateConfig(t *testing.T) {
 		},
 	}
 
-	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+	sset, err := makeStatefulSet(log.NewNopLogger(), &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{},
 		Spec: monitoringv1.AlertmanagerSpec{
 			NodeSelector:       nodeSelector,
@@ -1290,3 +1313,107 @@ func TestPodTemplateConfig(t *testing.T) {
 		t.Fatalf("expected image pull secrets to match, want %s, got %s", imagePullSecrets, sset.Spec.Template.Spec.ImagePullSecrets)
 	}
 }
+
+func TestClusterLabel(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		version  string
+		label    *string
+		expected bool
+	}{
+		{
+			name:    "unset",
+			version: "v0.25.0",
+		},
+		{
+			name:     "set",
+			version:  "v0.25.0",
+			label:    pointer.StringPtr("eu-west-1"),
+			expected: true,
+		},
+		{
+			name:    "unsupported Alertmanager version",
+			version: "v0.24.0",
+			label:   pointer.StringPtr("eu-west-1"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := monitoringv1.Alertmanager{}
+			replicas := int32(1)
+			a.Spec.Version = tc.version
+			a.Spec.Replicas = &replicas
+			a.Spec.ClusterLabel = tc.label
+
+			statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found bool
+			for _, arg := range statefulSet.Template.Spec.Containers[0].Args {
+				if strings.HasPrefix(arg, "--cluster.label=") {
+					if arg != "--cluster.label=eu-west-1" {
+						t.Fatalf("unexpected arg %q", arg)
+					}
+					found = true
+				}
+			}
+
+			if found != tc.expected {
+				t.Fatalf("expected --cluster.label to be present: %v, got %v", tc.expected, found)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerEnv(t *testing.T) {
+	a := monitoringv1.Alertmanager{}
+	replicas := int32(1)
+	a.Spec.Version = operator.DefaultAlertmanagerVersion
+	a.Spec.Replicas = &replicas
+	a.Spec.Env = []v1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:8080"}}
+
+	statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := statefulSet.Template.Spec.Containers[0].Env
+	if len(env) != 2 || env[0].Name != "POD_IP" || env[1] != a.Spec.Env[0] {
+		t.Fatalf("expected POD_IP and HTTPS_PROXY environment variables, got %v", env)
+	}
+}
+
+func TestTerminationGracePeriodSeconds(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		period   *int64
+		expected int64
+	}{
+		{
+			name:     "default",
+			expected: 120,
+		},
+		{
+			name:     "custom",
+			period:   pointer.Int64(300),
+			expected: 300,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := monitoringv1.Alertmanager{}
+			replicas := int32(1)
+			a.Spec.Replicas = &replicas
+			a.Spec.TerminationGracePeriodSeconds = tc.period
+
+			statefulSet, err := makeStatefulSetSpec(log.NewNopLogger(), &a, defaultTestConfig, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := *statefulSet.Template.Spec.TerminationGracePeriodSeconds; got != tc.expected {
+				t.Fatalf("expected terminationGracePeriodSeconds %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
@@ -22,10 +22,13 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"github.com/prometheus/alertmanager/config"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // ValidateAlertmanager runs extra validation on the AlertManager fields which
 // can't be done at the CRD schema validation level.
+// It returns a *monitoringv1.ValidationWarning when the resource is valid but
+// likely misconfigured.
 func ValidateAlertmanager(am *monitoringv1.Alertmanager) error {
 	// TODO(slashpai): Remove this validation after v0.60 since this is handled at CRD level
 	if am.Spec.Retention != "" {
@@ -55,7 +58,32 @@ func ValidateAlertmanager(am *monitoringv1.Alertmanager) error {
 		}
 	}
 
-	return nil
+	if err := operator.ValidateImagePullSecrets(field.NewPath("spec", "imagePullSecrets"), am.Spec.ImagePullSecrets); err != nil {
+		return errors.Wrap(err, "invalid image pull secrets")
+	}
+
+	// POD_IP is set by the operator on the alertmanager container.
+	if err := operator.ValidateEnvVars(field.NewPath("spec", "env"), am.Spec.Env, "POD_IP"); err != nil {
+		return errors.Wrap(err, "invalid environment variables")
+	}
+
+	if err := operator.ValidateEnvFromSources(field.NewPath("spec", "envFrom"), am.Spec.EnvFrom); err != nil {
+		return errors.Wrap(err, "invalid environment variable sources")
+	}
+
+	var warning error
+	if err := am.Spec.Validate(); err != nil {
+		if !monitoringv1.IsValidationWarning(err) {
+			return err
+		}
+		warning = err
+	}
+
+	if err := am.Spec.AlertmanagerConfiguration.Validate(); err != nil {
+		return errors.Wrap(err, "invalid alertmanagerConfiguration")
+	}
+
+	return warning
 }
 
 // ValidateAlertmanagerConfig checks that the given resource complies with the
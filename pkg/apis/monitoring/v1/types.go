@@ -15,8 +15,18 @@
 package v1
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring"
 	v1 "k8s.io/api/core/v1"
@@ -24,6 +34,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 const (
@@ -52,6 +64,14 @@ const (
 	ProbesKind   = "Probe"
 	ProbeName    = "probes"
 	ProbeKindKey = "probe"
+
+	// PrometheusNameLabelName is the label set by the operator on the
+	// Prometheus pods and StatefulSets to identify the owning Prometheus
+	// resource.
+	PrometheusNameLabelName = "operator.prometheus.io/name"
+	// PrometheusShardLabelName is the label set by the operator on the
+	// Prometheus pods and StatefulSets to identify the shard.
+	PrometheusShardLabelName = "operator.prometheus.io/shard"
 )
 
 var resourceToKind = map[string]string{
@@ -68,6 +88,14 @@ var resourceToKind = map[string]string{
 type CommonPrometheusFields struct {
 	// PodMetadata configures Labels and Annotations which are propagated to the prometheus pods.
 	PodMetadata *EmbeddedObjectMetadata `json:"podMetadata,omitempty"`
+	// StatefulSetMetadata configures Labels and Annotations which are propagated to the
+	// StatefulSets generated by the operator. Labels managed by the operator
+	// take precedence over the user-defined labels.
+	StatefulSetMetadata *EmbeddedObjectMetadata `json:"statefulSetMetadata,omitempty"`
+	// ServiceMetadata configures Labels and Annotations which are propagated to the
+	// governing Service generated by the operator. The governing Service is
+	// shared by all Prometheus resources of the same namespace.
+	ServiceMetadata *EmbeddedObjectMetadata `json:"serviceMetadata,omitempty"`
 	// ServiceMonitors to be selected for target discovery. *Deprecated:* if
 	// neither this nor podMonitorSelector are specified, configuration is
 	// unmanaged.
@@ -86,6 +114,10 @@ type CommonPrometheusFields struct {
 	ProbeSelector *metav1.LabelSelector `json:"probeSelector,omitempty"`
 	// *Experimental* Namespaces to be selected for Probe discovery. If nil, only check own namespace.
 	ProbeNamespaceSelector *metav1.LabelSelector `json:"probeNamespaceSelector,omitempty"`
+	// *Experimental* ScrapeConfigs to be selected for target discovery.
+	ScrapeConfigSelector *metav1.LabelSelector `json:"scrapeConfigSelector,omitempty"`
+	// *Experimental* Namespaces to be selected for ScrapeConfig discovery. If nil, only check own namespace.
+	ScrapeConfigNamespaceSelector *metav1.LabelSelector `json:"scrapeConfigNamespaceSelector,omitempty"`
 	// Version of Prometheus to be deployed.
 	Version string `json:"version,omitempty"`
 	// When a Prometheus deployment is paused, no actions except for deletion
@@ -111,8 +143,15 @@ type CommonPrometheusFields struct {
 	// either but it will continue to be available from the same instances. To
 	// query globally use Thanos sidecar and Thanos querier or remote write
 	// data to a central location. Sharding is done on the content of the
-	// `__address__` target meta-label.
+	// `__address__` target meta-label unless `shardingLabels` is set.
 	Shards *int32 `json:"shards,omitempty"`
+	// EXPERIMENTAL: Labels whose values are hashed to distribute targets
+	// onto shards, instead of the `__address__` target meta-label. It applies
+	// to service monitors, pod monitors and additional scrape configs.
+	// It can only be set when `shards` is greater than 1.
+	// +listType=set
+	// +optional
+	ShardingLabels []LabelName `json:"shardingLabels,omitempty"`
 	// Name of Prometheus external label used to denote replica name.
 	// Defaults to the value of `prometheus_replica`. External label will
 	// _not_ be added when value is set to empty string (`""`).
@@ -132,9 +171,36 @@ type CommonPrometheusFields struct {
 	ScrapeInterval Duration `json:"scrapeInterval,omitempty"`
 	// Number of seconds to wait for target to respond before erroring.
 	ScrapeTimeout Duration `json:"scrapeTimeout,omitempty"`
+	// ScrapeProtocols defines the protocols to negotiate during a scrape, in
+	// order of preference. It applies to all scrape jobs unless overridden
+	// at the endpoint level.
+	// If unset, Prometheus uses its default value.
+	// It requires Prometheus >= 2.49.0.
+	// +listType=set
+	// +optional
+	ScrapeProtocols []ScrapeProtocol `json:"scrapeProtocols,omitempty"`
+	// ScrapeFallbackProtocol defines the protocol to use when a target
+	// returns a missing, blank or unrecognized Content-Type. It applies to
+	// all scrape jobs unless overridden at the endpoint level.
+	// It requires Prometheus >= 3.0.0.
+	// +optional
+	ScrapeFallbackProtocol *ScrapeProtocol `json:"scrapeFallbackProtocol,omitempty"`
+	// Whether to convert all scraped classic histograms into native
+	// histograms with custom buckets. It applies to all scrape jobs unless
+	// overridden at the endpoint level.
+	// It requires Prometheus >= 3.0.0.
+	// +optional
+	ConvertClassicHistogramsToNHCB *bool `json:"convertClassicHistogramsToNHCB,omitempty"`
 	// The labels to add to any time series or alerts when communicating with
 	// external systems (federation, remote storage, Alertmanager).
+	// Label names starting with `__` are reserved and rejected. Labels named
+	// after `prometheusExternalLabelName` or `replicaExternalLabelName`
+	// override the labels added by the operator.
 	ExternalLabels map[string]string `json:"externalLabels,omitempty"`
+	// RuntimeConfig configures the values for the Prometheus process behavior.
+	// It requires Prometheus >= 2.53.0.
+	// +optional
+	Runtime *RuntimeConfig `json:"runtime,omitempty"`
 	// Enable Prometheus to be used as a receiver for the Prometheus remote write protocol. Defaults to the value of `false`.
 	// WARNING: This is not considered an efficient way of ingesting samples.
 	// Use it with caution for specific low-volume use cases.
@@ -143,6 +209,12 @@ type CommonPrometheusFields struct {
 	// For more information see https://prometheus.io/docs/prometheus/latest/querying/api/#remote-write-receiver
 	// Only valid in Prometheus versions 2.33.0 and newer.
 	EnableRemoteWriteReceiver bool `json:"enableRemoteWriteReceiver,omitempty"`
+	// Enable Prometheus to be used as a receiver for the OTLP Metrics protocol.
+	// When not set, the OTLP receiver is enabled if the `otlp` field is defined.
+	// For Prometheus versions before 3.0.0, it enables the `otlp-write-receiver`
+	// feature flag. Starting with 3.0.0, it sets the `--web.enable-otlp-receiver` flag.
+	// Only valid in Prometheus versions 2.47.0 and newer.
+	EnableOTLPReceiver *bool `json:"enableOTLPReceiver,omitempty"`
 	// Enable access to Prometheus disabled features. By default, no features are enabled.
 	// Enabling disabled features is entirely outside the scope of what the maintainers will
 	// support and by doing so, you accept that this behaviour may break at any
@@ -171,6 +243,15 @@ type CommonPrometheusFields struct {
 	Web *PrometheusWebSpec `json:"web,omitempty"`
 	// Define resources requests and limits for single Pods.
 	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// Env defines the environment variables of the `prometheus` container.
+	// Names must be valid C identifiers.
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+	// EnvFrom defines the sources of the environment variables of the
+	// `prometheus` container. Each source must reference either a ConfigMap
+	// or a Secret.
+	// +optional
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
 	// Define which Nodes the Pods are scheduled on.
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	// ServiceAccountName is the name of the ServiceAccount to use to run the
@@ -200,6 +281,33 @@ type CommonPrometheusFields struct {
 	// ListenLocal makes the Prometheus server listen on loopback, so that it
 	// does not bind against the Pod IP.
 	ListenLocal bool `json:"listenLocal,omitempty"`
+	// Defines the strategy used by the config reloader to reload the
+	// Prometheus configuration.
+	// `HTTP` (default) calls the `/-/reload` endpoint of Prometheus.
+	// `ProcessSignal` sends a SIGHUP signal to the Prometheus process, in
+	// which case the containers of the pod share a single process namespace.
+	// +optional
+	ReloadStrategy *ReloadStrategyType `json:"reloadStrategy,omitempty"`
+	// When true, the config reloader doesn't reload Prometheus when it
+	// applies the configuration for the first time, provided that the
+	// configuration is identical to the one generated by the init container.
+	// Prometheus has already loaded this configuration at startup hence the
+	// initial reload is redundant. It can be costly for large configurations
+	// and it blocks the config reloader until Prometheus is ready.
+	// +optional
+	SkipInitConfigReload *bool `json:"skipInitConfigReload,omitempty"`
+	// ConfigReloaderWatchInterval defines how often the config reloader
+	// re-reads the configuration file and the watched directories.
+	// If unset, the config reloader uses its default value (3 minutes).
+	// +optional
+	ConfigReloaderWatchInterval *Duration `json:"configReloaderWatchInterval,omitempty"`
+	// Defines the Kubernetes service discovery role used to discover the
+	// targets of ServiceMonitor objects.
+	// If unset, the operator uses `EndpointSlice` when both Prometheus
+	// (>= v2.21.0) and the Kubernetes API support it, `Endpoints` otherwise.
+	// `EndpointSlice` falls back to `Endpoints` when it isn't supported.
+	// +optional
+	ServiceDiscoveryRole *ServiceDiscoveryRole `json:"serviceDiscoveryRole,omitempty"`
 	// Containers allows injecting additional containers or modifying operator
 	// generated containers. This can be used to allow adding an authentication
 	// proxy to a Prometheus pod or to change the behavior of an operator
@@ -233,6 +341,13 @@ type CommonPrometheusFields struct {
 	// notes to ensure that no incompatible scrape configs are going to break
 	// Prometheus after the upgrade.
 	AdditionalScrapeConfigs *v1.SecretKeySelector `json:"additionalScrapeConfigs,omitempty"`
+	// AdditionalScrapeConfigsInline allows specifying additional Prometheus
+	// scrape configurations directly in the resource, as a YAML list of
+	// scrape configurations. The operator validates the configurations before
+	// appending them to the generated configuration.
+	// It is mutually exclusive with `additionalScrapeConfigs`.
+	// +optional
+	AdditionalScrapeConfigsInline *string `json:"additionalScrapeConfigsInline,omitempty"`
 	// APIServerConfig allows specifying a host and auth methods to access apiserver.
 	// If left empty, Prometheus is assumed to run inside of the cluster
 	// and will discover API servers automatically and use the pod's CA certificate
@@ -316,6 +431,21 @@ type CommonPrometheusFields struct {
 	// This is an alpha field and requires enabling StatefulSetMinReadySeconds feature gate.
 	// +optional
 	MinReadySeconds *uint32 `json:"minReadySeconds,omitempty"`
+	// Optional duration in seconds the pod needs to terminate gracefully.
+	// Prometheus may need time to flush the head block on shutdown.
+	// Defaults to 600 seconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// When true, Prometheus drains the remote write queues before the pod
+	// terminates: the operator adds a preStop hook triggering the graceful
+	// shutdown of Prometheus via the `/-/quit` lifecycle endpoint and lets the
+	// remote write queues flush for up to 5 minutes (bounded by the
+	// termination grace period). Unless `terminationGracePeriodSeconds` is
+	// set, the default grace period is extended by 5 minutes.
+	// It has no effect when no remote write endpoint is configured.
+	// +optional
+	RemoteWriteDrainOnShutdown *bool `json:"remoteWriteDrainOnShutdown,omitempty"`
 	// Pods' hostAliases configuration
 	// +listType=map
 	// +listMapKey=ip
@@ -340,8 +470,648 @@ type CommonPrometheusFields struct {
 	// Make sure to understand the security implications if you want to enable it.
 	// When hostNetwork is enabled, this will set dnsPolicy to ClusterFirstWithHostNet automatically.
 	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// List of scrape classes to expose to scraping objects such as
+	// ServiceMonitors, PodMonitors and Probes.
+	//
+	// This is an experimental feature, it may change in any upcoming release
+	// in a breaking way.
+	//
+	// +listType=map
+	// +listMapKey=name
+	ScrapeClasses []ScrapeClass `json:"scrapeClasses,omitempty"`
+}
+
+// ScrapeClass defines settings which are shared by the scrape configurations
+// of the ServiceMonitors, PodMonitors and Probes referencing it.
+// +k8s:openapi-gen=true
+type ScrapeClass struct {
+	// Name of the scrape class.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+	// Default indicates that the scrape class applies to all scrape objects
+	// that don't configure an explicit scrape class name.
+	//
+	// Only one scrape class can be set as the default.
+	//
+	// +optional
+	Default *bool `json:"default,omitempty"`
+	// DefaultBasicAuth configures the basic authentication applied to the
+	// targets of the scrape objects using the scrape class. The secrets must
+	// be in the same namespace as the Prometheus object.
+	//
+	// The authentication settings of the scrape objects (e.g. `basicAuth`,
+	// `oauth2`, `authorization`, `bearerTokenSecret` or `bearerTokenFile`)
+	// take precedence over the scrape class settings.
+	//
+	// +optional
+	DefaultBasicAuth *BasicAuth `json:"defaultBasicAuth,omitempty"`
+	// AttachMetadata configures the metadata attached to the targets
+	// discovered by the ServiceMonitors and PodMonitors using the scrape
+	// class.
+	//
+	// The `attachMetadata` field of the scrape objects takes precedence over
+	// the scrape class setting.
+	//
+	// +optional
+	AttachMetadata *AttachMetadata `json:"attachMetadata,omitempty"`
+	// FallbackScrapeProtocol defines the protocol to use when the targets of
+	// the scrape objects using the scrape class return a missing, blank or
+	// unrecognized Content-Type.
+	//
+	// The `scrapeFallbackProtocol` field of the endpoints takes precedence
+	// over the scrape class setting which takes precedence over the
+	// Prometheus setting.
+	//
+	// It requires Prometheus >= 3.0.0.
+	//
+	// +optional
+	FallbackScrapeProtocol *ScrapeProtocol `json:"fallbackScrapeProtocol,omitempty"`
+}
+
+// Validate semantically validates the given ScrapeClass.
+func (sc *ScrapeClass) Validate() error {
+	if sc.Name == "" {
+		return &ScrapeClassValidationError{"name must be set"}
+	}
+
+	if err := sc.DefaultBasicAuth.Validate(); err != nil {
+		return &ScrapeClassValidationError{fmt.Sprintf("scrape class %q: defaultBasicAuth: %v", sc.Name, err)}
+	}
+
+	if sc.FallbackScrapeProtocol != nil {
+		if err := sc.FallbackScrapeProtocol.Validate(); err != nil {
+			return &ScrapeClassValidationError{fmt.Sprintf("scrape class %q: fallbackScrapeProtocol: %v", sc.Name, err)}
+		}
+	}
+
+	return nil
+}
+
+// EffectiveListenPort returns the port on which the Prometheus web server
+// listens. It is `web.listenPort` if set, otherwise 9090.
+func (cpf *CommonPrometheusFields) EffectiveListenPort() int32 {
+	if cpf.Web != nil && cpf.Web.ListenPort != nil {
+		return *cpf.Web.ListenPort
+	}
+
+	return 9090
+}
+
+// EffectiveListenAddress returns the address on which the Prometheus web
+// server listens. It is `127.0.0.1:<port>` when `listenLocal` is true, in
+// which case the port isn't exposed by the pod and the probes run inside the
+// container, otherwise `0.0.0.0:<port>`. The port is given by
+// EffectiveListenPort(); `portName` only names the container port.
+//
+// The Thanos sidecar reaches Prometheus over the loopback interface
+// regardless of this setting. Its own gRPC and HTTP endpoints are controlled
+// by `thanos.grpcListenLocal` and `thanos.httpListenLocal`.
+func (cpf *CommonPrometheusFields) EffectiveListenAddress() string {
+	host := "0.0.0.0"
+	if cpf.ListenLocal {
+		host = "127.0.0.1"
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(cpf.EffectiveListenPort())))
+}
+
+// ContainerPorts returns the ports exposed by the Prometheus container. It
+// is empty when `listenLocal` is true.
+func (cpf *CommonPrometheusFields) ContainerPorts() []v1.ContainerPort {
+	if cpf.ListenLocal {
+		return nil
+	}
+
+	portName := cpf.PortName
+	if portName == "" {
+		portName = "web"
+	}
+
+	return []v1.ContainerPort{
+		{
+			Name:          portName,
+			ContainerPort: cpf.EffectiveListenPort(),
+			Protocol:      v1.ProtocolTCP,
+		},
+	}
+}
+
+// ShardingRelabelConfigs returns the relabel configs which keep only the
+// targets assigned to the given shard. The targets are distributed by hashing
+// the values of the `shardingLabels` labels (`__address__` by default).
+// It returns nil when there is at most one shard since all targets are kept.
+func (cpf *CommonPrometheusFields) ShardingRelabelConfigs(shardIndex, totalShards int32) []*RelabelConfig {
+	if totalShards <= 1 {
+		return nil
+	}
+
+	sourceLabels := cpf.ShardingLabels
+	if len(sourceLabels) == 0 {
+		sourceLabels = []LabelName{"__address__"}
+	}
+
+	return []*RelabelConfig{
+		{
+			SourceLabels: sourceLabels,
+			TargetLabel:  "__tmp_hash",
+			Modulus:      uint64(totalShards),
+			Action:       "hashmod",
+		},
+		{
+			SourceLabels: []LabelName{"__tmp_hash"},
+			Regex:        strconv.Itoa(int(shardIndex)),
+			Action:       "keep",
+		},
+	}
+}
+
+// Validate semantically validates the given CommonPrometheusFields.
+func (cpf *CommonPrometheusFields) Validate() error {
+	if cpf.TerminationGracePeriodSeconds != nil && *cpf.TerminationGracePeriodSeconds < 0 {
+		return &CommonPrometheusFieldsValidationError{fmt.Sprintf("terminationGracePeriodSeconds %d must not be negative", *cpf.TerminationGracePeriodSeconds)}
+	}
+
+	if len(cpf.ShardingLabels) > 0 {
+		if cpf.Shards == nil || *cpf.Shards <= 1 {
+			return &CommonPrometheusFieldsValidationError{"shardingLabels requires shards to be greater than 1"}
+		}
+
+		for _, l := range cpf.ShardingLabels {
+			if !labelNameRe.MatchString(string(l)) {
+				return &CommonPrometheusFieldsValidationError{fmt.Sprintf("invalid label name %q in shardingLabels", l)}
+			}
+		}
+	}
+
+	if err := validateConfigReloaderWatchInterval(cpf.ConfigReloaderWatchInterval); err != nil {
+		return &CommonPrometheusFieldsValidationError{err.Error()}
+	}
+
+	if cpf.AdditionalScrapeConfigs != nil && cpf.AdditionalScrapeConfigsInline != nil {
+		return &CommonPrometheusFieldsValidationError{"additionalScrapeConfigs and additionalScrapeConfigsInline are mutually exclusive"}
+	}
+
+	if cpf.ServiceDiscoveryRole != nil {
+		switch *cpf.ServiceDiscoveryRole {
+		case EndpointsRole, EndpointSliceRole:
+		default:
+			return &CommonPrometheusFieldsValidationError{fmt.Sprintf("invalid serviceDiscoveryRole %q, expected one of %q or %q", *cpf.ServiceDiscoveryRole, EndpointsRole, EndpointSliceRole)}
+		}
+	}
+
+	if cpf.ScrapeTimeout == "" {
+		return nil
+	}
+
+	// The operator defaults the global scrape interval to 30s.
+	scrapeInterval := cpf.ScrapeInterval
+	if scrapeInterval == "" {
+		scrapeInterval = "30s"
+	}
+
+	si, err := scrapeInterval.Parse()
+	if err != nil {
+		return &CommonPrometheusFieldsValidationError{fmt.Sprintf("scrapeInterval: %v", err)}
+	}
+
+	st, err := cpf.ScrapeTimeout.Parse()
+	if err != nil {
+		return &CommonPrometheusFieldsValidationError{fmt.Sprintf("scrapeTimeout: %v", err)}
+	}
+
+	if st > si {
+		return &CommonPrometheusFieldsValidationError{fmt.Sprintf("scrapeTimeout %q greater than scrapeInterval %q", cpf.ScrapeTimeout, scrapeInterval)}
+	}
+
+	return nil
+}
+
+// ValidateExternalLabels checks that the external label names are valid and
+// not reserved (i.e. starting with `__`).
+// It returns a *ValidationWarning when an external label collides with the
+// replica or Prometheus external labels added by the operator since it
+// overrides them (e.g. breaking the deduplication of the replicas in Thanos).
+func (cpf *CommonPrometheusFields) ValidateExternalLabels() error {
+	names := make([]string, 0, len(cpf.ExternalLabels))
+	for n := range cpf.ExternalLabels {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	operatorLabels := map[string]string{
+		"prometheusExternalLabelName": "prometheus",
+		"replicaExternalLabelName":    "prometheus_replica",
+	}
+	if cpf.PrometheusExternalLabelName != nil {
+		operatorLabels["prometheusExternalLabelName"] = *cpf.PrometheusExternalLabelName
+	}
+	if cpf.ReplicaExternalLabelName != nil {
+		operatorLabels["replicaExternalLabelName"] = *cpf.ReplicaExternalLabelName
+	}
+
+	var warnings []string
+	for _, n := range names {
+		if strings.HasPrefix(n, "__") {
+			return &ExternalLabelsValidationError{fmt.Sprintf("external label name %q is reserved: names starting with \"__\" are for internal use", n)}
+		}
+
+		if !labelNameRe.MatchString(n) {
+			return &ExternalLabelsValidationError{fmt.Sprintf("invalid external label name %q", n)}
+		}
+
+		for _, field := range []string{"prometheusExternalLabelName", "replicaExternalLabelName"} {
+			if operatorLabels[field] != "" && operatorLabels[field] == n {
+				warnings = append(warnings, fmt.Sprintf("external label %q overrides the label defined by %s", n, field))
+			}
+		}
+	}
+
+	if len(warnings) > 0 {
+		return NewValidationWarning(warnings...)
+	}
+
+	return nil
+}
+
+// ExternalLabelsValidationError is returned by
+// CommonPrometheusFields.ValidateExternalLabels() on invalid external labels.
+// +k8s:openapi-gen=false
+type ExternalLabelsValidationError struct {
+	err string
+}
+
+func (e *ExternalLabelsValidationError) Error() string {
+	return e.err
 }
 
+// CommonPrometheusFieldsValidationError is returned by
+// CommonPrometheusFields.Validate() on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type CommonPrometheusFieldsValidationError struct {
+	err string
+}
+
+func (e *CommonPrometheusFieldsValidationError) Error() string {
+	return e.err
+}
+
+// ValidateReloadStrategy checks that the `ProcessSignal` reload strategy
+// isn't combined with container overrides which depend on the HTTP reload
+// endpoint or which prevent the config reloader from finding the Prometheus
+// process.
+// It also checks that `skipInitConfigReload` isn't combined with an
+// override of the init container's command since Prometheus might then start
+// without the generated configuration.
+func (cpf *CommonPrometheusFields) ValidateReloadStrategy() error {
+	if cpf.SkipInitConfigReload != nil && *cpf.SkipInitConfigReload {
+		for _, c := range cpf.InitContainers {
+			if c.Name == "init-config-reloader" && len(c.Command) > 0 {
+				return &ReloadStrategyValidationError{fmt.Sprintf("skipInitConfigReload can't be used when the command of the %q init container is overridden", c.Name)}
+			}
+		}
+	}
+
+	if cpf.ReloadStrategy == nil || *cpf.ReloadStrategy != ProcessSignalReloadStrategyType {
+		return nil
+	}
+
+	for _, c := range cpf.Containers {
+		switch c.Name {
+		case "prometheus":
+			if len(c.Command) > 0 && path.Base(c.Command[0]) != "prometheus" {
+				return &ReloadStrategyValidationError{fmt.Sprintf("reload strategy %q requires the executable of the %q container to be named %q, got %q", ProcessSignalReloadStrategyType, c.Name, "prometheus", c.Command[0])}
+			}
+		case "config-reloader":
+			for _, arg := range c.Args {
+				if strings.HasPrefix(arg, "--reload-url") {
+					return &ReloadStrategyValidationError{fmt.Sprintf("reload strategy %q can't be used with the %q argument of the %q container", ProcessSignalReloadStrategyType, arg, c.Name)}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ReloadStrategyValidationError is returned by
+// CommonPrometheusFields.ValidateReloadStrategy() on semantically invalid
+// configurations.
+// +k8s:openapi-gen=false
+type ReloadStrategyValidationError struct {
+	err string
+}
+
+func (e *ReloadStrategyValidationError) Error() string {
+	return e.err
+}
+
+// ValidateScrapeClasses checks that the scrape classes are valid, that their
+// names are unique and that at most one of them is the default class.
+func (cpf *CommonPrometheusFields) ValidateScrapeClasses() error {
+	var (
+		names        = make(map[string]struct{}, len(cpf.ScrapeClasses))
+		defaultClass string
+	)
+
+	for i := range cpf.ScrapeClasses {
+		sc := &cpf.ScrapeClasses[i]
+		if err := sc.Validate(); err != nil {
+			return err
+		}
+
+		if _, found := names[sc.Name]; found {
+			return &ScrapeClassValidationError{fmt.Sprintf("duplicate scrape class name %q", sc.Name)}
+		}
+		names[sc.Name] = struct{}{}
+
+		if sc.Default != nil && *sc.Default {
+			if defaultClass != "" {
+				return &ScrapeClassValidationError{fmt.Sprintf("scrape classes %q and %q are both set as default", defaultClass, sc.Name)}
+			}
+			defaultClass = sc.Name
+		}
+	}
+
+	return nil
+}
+
+// ResolveScrapeClass returns the scrape class with the given name or the
+// default scrape class if name is nil. It returns nil if name is nil and
+// there's no default scrape class, and an error if no scrape class matches
+// the name.
+func (cpf *CommonPrometheusFields) ResolveScrapeClass(name *string) (*ScrapeClass, error) {
+	for i := range cpf.ScrapeClasses {
+		sc := &cpf.ScrapeClasses[i]
+		if name == nil {
+			if sc.Default != nil && *sc.Default {
+				return sc, nil
+			}
+			continue
+		}
+
+		if sc.Name == *name {
+			return sc, nil
+		}
+	}
+
+	if name != nil {
+		return nil, fmt.Errorf("scrape class %q not found", *name)
+	}
+
+	return nil, nil
+}
+
+// ScrapeClassValidationError is returned by ScrapeClass.Validate() and
+// CommonPrometheusFields.ValidateScrapeClasses() on semantically invalid
+// configurations.
+// +k8s:openapi-gen=false
+type ScrapeClassValidationError struct {
+	err string
+}
+
+func (e *ScrapeClassValidationError) Error() string {
+	return e.err
+}
+
+func validateRemoteWriteURL(fldPath *field.Path, s string) field.ErrorList {
+	u, err := url.Parse(s)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, s, err.Error())}
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, s, "must be an absolute http or https URL")}
+	}
+
+	return nil
+}
+
+// remoteWriteFieldVersions lists the remote write fields which require a
+// minimum Prometheus version.
+var remoteWriteFieldVersions = []struct {
+	field          string
+	minimumVersion string
+	isSet          func(*RemoteWriteSpec) bool
+}{
+	{"name", "2.15.0", func(rw *RemoteWriteSpec) bool { return rw.Name != "" }},
+	{"headers", "2.25.0", func(rw *RemoteWriteSpec) bool { return len(rw.Headers) > 0 }},
+	{"followRedirects", "2.26.0", func(rw *RemoteWriteSpec) bool { return rw.FollowRedirects != nil }},
+	{"sendExemplars", "2.27.0", func(rw *RemoteWriteSpec) bool { return rw.SendExemplars != nil }},
+	{"oauth2", "2.27.0", func(rw *RemoteWriteSpec) bool { return rw.OAuth2 != nil }},
+	{"enableHTTP2", "2.35.0", func(rw *RemoteWriteSpec) bool { return rw.EnableHTTP2 != nil }},
+	{"queueConfig.retryOnRateLimit", "2.26.0", func(rw *RemoteWriteSpec) bool {
+		return rw.QueueConfig != nil && rw.QueueConfig.RetryOnRateLimit
+	}},
+	{"queueConfig.sampleAgeLimit", "2.50.0", func(rw *RemoteWriteSpec) bool {
+		return rw.QueueConfig != nil && rw.QueueConfig.SampleAgeLimit != nil
+	}},
+	{"messageVersion", "2.54.0", func(rw *RemoteWriteSpec) bool {
+		return rw.MessageVersion != nil && *rw.MessageVersion == "V2.0"
+	}},
+}
+
+// ValidateExemplars returns a *ValidationWarning listing the enabled remote
+// write configurations with `sendExemplars` set to true when the
+// `exemplar-storage` feature isn't enabled: Prometheus doesn't store the
+// scraped exemplars hence none is sent.
+func (cpf *CommonPrometheusFields) ValidateExemplars() error {
+	for _, f := range cpf.EnableFeatures {
+		if f == "exemplar-storage" {
+			return nil
+		}
+	}
+
+	var warnings []string
+	for i, rw := range cpf.RemoteWrite {
+		if rw.Enabled != nil && !*rw.Enabled {
+			continue
+		}
+
+		if rw.SendExemplars == nil || !*rw.SendExemplars {
+			continue
+		}
+
+		id := fmt.Sprintf("remoteWrite[%d]", i)
+		if rw.Name != "" {
+			id = fmt.Sprintf("%s (%q)", id, rw.Name)
+		}
+		warnings = append(warnings, fmt.Sprintf("%s: sendExemplars has no effect without the %q feature enabled", id, "exemplar-storage"))
+	}
+
+	if len(warnings) > 0 {
+		return NewValidationWarning(warnings...)
+	}
+
+	return nil
+}
+
+// ValidateRemoteWrites validates all the remote write configurations and
+// returns every problem found, with the path of the offending field.
+// When there are several entries or when one of them is disabled, every
+// entry must have a unique name so that they can be told apart regardless
+// of their position.
+// Version-gated fields are only checked when the version is set.
+func (cpf *CommonPrometheusFields) ValidateRemoteWrites() field.ErrorList {
+	var (
+		errs         field.ErrorList
+		names        = map[string]struct{}{}
+		requireNames = len(cpf.RemoteWrite) > 1
+	)
+
+	for _, rw := range cpf.RemoteWrite {
+		if rw.Enabled != nil && !*rw.Enabled {
+			requireNames = true
+		}
+	}
+
+	for i := range cpf.RemoteWrite {
+		var (
+			rw      = &cpf.RemoteWrite[i]
+			fldPath = field.NewPath("spec", "remoteWrite").Index(i)
+		)
+
+		switch {
+		case rw.URL != "" && len(rw.URLs) > 0:
+			errs = append(errs, field.Forbidden(fldPath, "url and urls are mutually exclusive"))
+		case rw.URL != "":
+			errs = append(errs, validateRemoteWriteURL(fldPath.Child("url"), rw.URL)...)
+		case len(rw.URLs) > 0:
+			urls := make(map[string]struct{}, len(rw.URLs))
+			for j, u := range rw.URLs {
+				if _, found := urls[u]; found {
+					errs = append(errs, field.Duplicate(fldPath.Child("urls").Index(j), u))
+					continue
+				}
+				urls[u] = struct{}{}
+
+				errs = append(errs, validateRemoteWriteURL(fldPath.Child("urls").Index(j), u)...)
+			}
+		default:
+			errs = append(errs, field.Required(fldPath.Child("url"), "one of url or urls must be set"))
+		}
+
+		switch {
+		case rw.Name != "":
+			if _, found := names[rw.Name]; found {
+				errs = append(errs, field.Duplicate(fldPath.Child("name"), rw.Name))
+			}
+			names[rw.Name] = struct{}{}
+		case requireNames:
+			errs = append(errs, field.Required(fldPath.Child("name"), "name must be set when there are several remote write entries or when one of them is disabled"))
+		}
+
+		var auths []string
+		for _, a := range []struct {
+			name  string
+			isSet bool
+		}{
+			{"basicAuth", rw.BasicAuth != nil},
+			{"oauth2", rw.OAuth2 != nil},
+			{"authorization", rw.Authorization != nil},
+			{"sigv4", rw.Sigv4 != nil},
+		} {
+			if a.isSet {
+				auths = append(auths, a.name)
+			}
+		}
+		if len(auths) > 1 {
+			errs = append(errs, field.Forbidden(fldPath, fmt.Sprintf("%s can't be set at the same time, at most one of them must be defined", strings.Join(auths, " and "))))
+		}
+
+		if err := rw.BasicAuth.Validate(); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("basicAuth"), rw.BasicAuth, err.Error()))
+		}
+
+		if rw.OAuth2 != nil {
+			if err := rw.OAuth2.Validate(); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Child("oauth2"), rw.OAuth2, err.Error()))
+			}
+		}
+
+		if rw.Authorization != nil {
+			if err := rw.Authorization.Validate(); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Child("authorization"), rw.Authorization, err.Error()))
+			}
+		}
+
+		if rw.TLSConfig != nil {
+			if err := rw.TLSConfig.Validate(); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Child("tlsConfig"), rw.TLSConfig, err.Error()))
+			}
+		}
+
+		if rw.RemoteTimeout != "" {
+			if err := rw.RemoteTimeout.Validate(); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Child("remoteTimeout"), rw.RemoteTimeout, err.Error()))
+			}
+		}
+
+		if rw.MessageVersion != nil && *rw.MessageVersion != "V1.0" && *rw.MessageVersion != "V2.0" {
+			errs = append(errs, field.NotSupported(fldPath.Child("messageVersion"), *rw.MessageVersion, []string{"V1.0", "V2.0"}))
+		}
+
+		if err := rw.QueueConfig.Validate(); err != nil && !IsValidationWarning(err) {
+			errs = append(errs, field.Invalid(fldPath.Child("queueConfig"), rw.QueueConfig, err.Error()))
+		}
+
+		for j := range rw.WriteRelabelConfigs {
+			if err := rw.WriteRelabelConfigs[j].Validate(); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Child("writeRelabelConfigs").Index(j), rw.WriteRelabelConfigs[j], err.Error()))
+			}
+		}
+
+		if cpf.Version == "" {
+			continue
+		}
+
+		for _, fv := range remoteWriteFieldVersions {
+			if !fv.isSet(rw) {
+				continue
+			}
+
+			older, err := isVersionOlderThan(cpf.Version, fv.minimumVersion)
+			if err != nil {
+				errs = append(errs, field.Invalid(field.NewPath("spec", "version"), cpf.Version, err.Error()))
+				return errs
+			}
+
+			if older {
+				errs = append(errs, field.Forbidden(fldPath.Child(fv.field), fmt.Sprintf("requires Prometheus >= v%s (current version: %s)", fv.minimumVersion, cpf.Version)))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ServiceDiscoveryRole is the Kubernetes service discovery role used to
+// discover the targets of ServiceMonitor objects.
+// +kubebuilder:validation:Enum=Endpoints;EndpointSlice
+type ServiceDiscoveryRole string
+
+const (
+	// EndpointsRole discovers the targets from Endpoints objects.
+	EndpointsRole ServiceDiscoveryRole = "Endpoints"
+	// EndpointSliceRole discovers the targets from EndpointSlice objects.
+	EndpointSliceRole ServiceDiscoveryRole = "EndpointSlice"
+)
+
+// ReloadStrategyType defines how the config reloader triggers a reload of
+// the Prometheus configuration.
+// +kubebuilder:validation:Enum=HTTP;ProcessSignal
+type ReloadStrategyType string
+
+const (
+	// HTTPReloadStrategyType reloads the configuration using the `/-/reload`
+	// HTTP endpoint.
+	HTTPReloadStrategyType ReloadStrategyType = "HTTP"
+	// ProcessSignalReloadStrategyType reloads the configuration by sending a
+	// SIGHUP signal to the Prometheus process.
+	ProcessSignalReloadStrategyType ReloadStrategyType = "ProcessSignal"
+)
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:categories="prometheus-operator",shortName="prom"
@@ -378,17 +1148,427 @@ type PrometheusList struct {
 	Items []*Prometheus `json:"items"`
 }
 
-// ByteSize is a valid memory size type based on powers-of-2, so 1KB is 1024B.
-// Supported units: B, KB, KiB, MB, MiB, GB, GiB, TB, TiB, PB, PiB, EB, EiB Ex: `512MB`.
-// +kubebuilder:validation:Pattern:="(^0|([0-9]*[.])?[0-9]+((K|M|G|T|E|P)i?)?B)$"
-type ByteSize string
+// SelectorLabels returns the labels used by the operator to select the pods
+// of the Prometheus resource. The labels match the pods of all shards, use
+// ShardSelectorLabels() to select the pods of a given shard.
+func (p *Prometheus) SelectorLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "prometheus",
+		"app.kubernetes.io/managed-by": "prometheus-operator",
+		"app.kubernetes.io/instance":   p.Name,
+		"prometheus":                   p.Name,
+		PrometheusNameLabelName:        p.Name,
+	}
+}
 
-// Duration is a valid time duration that can be parsed by Prometheus model.ParseDuration() function.
-// Supported units: y, w, d, h, m, s, ms
+// ShardSelectorLabels returns the labels used by the operator to select the
+// pods of the given shard of the Prometheus resource.
+func (p *Prometheus) ShardSelectorLabels(shard int32) map[string]string {
+	labels := p.SelectorLabels()
+	labels[PrometheusShardLabelName] = strconv.Itoa(int(shard))
+	return labels
+}
+
+// ImageDefaults holds the images and versions used by the operator when a
+// resource doesn't specify them.
+// +k8s:openapi-gen=false
+type ImageDefaults struct {
+	PrometheusBaseImage   string
+	AlertmanagerBaseImage string
+	AlertmanagerVersion   string
+	ThanosBaseImage       string
+	ThanosVersion         string
+	ConfigReloaderImage   string
+}
+
+// ImageReferences returns the references of all the container images
+// deployed by the operator for the Prometheus resource: Prometheus, the
+// config reloader and the Thanos sidecar if enabled. Images of the
+// additional containers defined in the spec aren't included.
+func (p *Prometheus) ImageReferences(defaults ImageDefaults) ([]string, error) {
+	image, err := buildImagePath(
+		stringPtrValue(p.Spec.Image),
+		stringValOrDefault(p.Spec.BaseImage, defaults.PrometheusBaseImage),
+		p.Spec.Version,
+		p.Spec.Tag,
+		p.Spec.SHA,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus: %w", err)
+	}
+
+	images := []string{image}
+	if defaults.ConfigReloaderImage != "" {
+		images = append(images, defaults.ConfigReloaderImage)
+	}
+
+	if p.Spec.Thanos != nil {
+		image, err := buildImagePath(
+			stringPtrValue(p.Spec.Thanos.Image),
+			stringValOrDefault(stringPtrValue(p.Spec.Thanos.BaseImage), defaults.ThanosBaseImage),
+			stringValOrDefault(stringPtrValue(p.Spec.Thanos.Version), defaults.ThanosVersion),
+			stringPtrValue(p.Spec.Thanos.Tag),
+			stringPtrValue(p.Spec.Thanos.SHA),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("thanos: %w", err)
+		}
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+// buildImagePath returns the image reference following the precedence rules
+// of the operator: `image` takes precedence over `baseImage`, and `sha`
+// takes precedence over `tag` which takes precedence over `version`. A
+// base image which is already tagged or pinned by digest is used as is.
+//
+// It mirrors operator.BuildImagePath() which can't be imported from the API
+// module, including the normalization of the image name when `tag` is used.
+func buildImagePath(specImage, baseImage, version, tag, sha string) (string, error) {
+	if strings.TrimSpace(specImage) != "" {
+		return specImage, nil
+	}
+
+	if strings.TrimSpace(baseImage) == "" {
+		return "", errors.New("missing base image")
+	}
+
+	name := baseImage[strings.LastIndex(baseImage, "/")+1:]
+	if strings.Contains(name, "@") || strings.Contains(name, ":") {
+		return baseImage, nil
+	}
+
+	switch {
+	case sha != "":
+		return fmt.Sprintf("%s@sha256:%s", baseImage, sha), nil
+	case tag != "":
+		return normalizeImageName(baseImage) + ":" + tag, nil
+	case version != "":
+		return baseImage + ":" + version, nil
+	}
+
+	return baseImage, nil
+}
+
+// normalizeImageName returns the fully-qualified form of an image name, the
+// same way as the Docker reference library: names without registry are
+// hosted on `docker.io` and single-component names belong to the `library`
+// namespace.
+func normalizeImageName(name string) string {
+	domain, remainder := "docker.io", name
+	if i := strings.Index(name, "/"); i != -1 {
+		if d := name[:i]; d == "localhost" || strings.ContainsAny(d, ".:") {
+			domain, remainder = d, name[i+1:]
+		}
+	}
+
+	if domain == "index.docker.io" {
+		domain = "docker.io"
+	}
+
+	if domain == "docker.io" && !strings.Contains(remainder, "/") {
+		remainder = "library/" + remainder
+	}
+
+	return domain + "/" + remainder
+}
+
+func stringPtrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func stringValOrDefault(s, def string) string {
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
+
+// ByteSize is a valid memory size type based on powers-of-2, so 1KB is 1024B.
+// Supported units: B, KB, KiB, MB, MiB, GB, GiB, TB, TiB, PB, PiB, EB, EiB Ex: `512MB`.
+// +kubebuilder:validation:Pattern:="(^0|([0-9]*[.])?[0-9]+((K|M|G|T|E|P)i?)?B)$"
+type ByteSize string
+
+var byteSizeRe = regexp.MustCompile(`^(0|([0-9]*[.])?[0-9]+((K|M|G|T|E|P)i?)?B)$`)
+
+// Validate returns an error if the size can't be parsed by Prometheus.
+func (bs ByteSize) Validate() error {
+	if bs == "" || !byteSizeRe.MatchString(string(bs)) {
+		return fmt.Errorf("invalid size %q", bs)
+	}
+
+	return nil
+}
+
+// byteSizeUnits maps the unit prefixes to their powers-of-2 multiplier.
+var byteSizeUnits = map[byte]float64{
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+	'P': 1 << 50,
+	'E': 1 << 60,
+}
+
+// Bytes returns the number of bytes represented by the size. Units are
+// powers of 2 regardless of the `i` suffix, e.g. both `1KB` and `1KiB` are
+// 1024 bytes.
+func (bs ByteSize) Bytes() (int64, error) {
+	if err := bs.Validate(); err != nil {
+		return 0, err
+	}
+
+	if bs == "0" {
+		return 0, nil
+	}
+
+	s := strings.TrimSuffix(string(bs), "B")
+	s = strings.TrimSuffix(s, "i")
+
+	mult := float64(1)
+	if m, found := byteSizeUnits[s[len(s)-1]]; found {
+		mult = m
+		s = s[:len(s)-1]
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", bs, err)
+	}
+
+	b := f * mult
+	if b >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q: value out of range", bs)
+	}
+
+	return int64(b), nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface. Leading zeros are
+// removed from valid sizes (e.g. `010MB` is marshaled as `10MB`).
+func (bs ByteSize) MarshalYAML() (interface{}, error) {
+	if bs.Validate() != nil {
+		return string(bs), nil
+	}
+
+	return trimLeadingZeros(string(bs)), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface. Leading zeros are
+// removed from valid sizes, invalid sizes are kept as-is and reported by
+// Validate().
+func (bs *ByteSize) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	*bs = ByteSize(s)
+	if bs.Validate() == nil {
+		*bs = ByteSize(trimLeadingZeros(s))
+	}
+
+	return nil
+}
+
+var leadingZerosRe = regexp.MustCompile(`(^|[^0-9.])0+([0-9])`)
+
+// trimLeadingZeros removes the leading zeros of the numbers in the string.
+func trimLeadingZeros(s string) string {
+	return leadingZerosRe.ReplaceAllString(s, "${1}${2}")
+}
+
+// Duration is a valid time duration that can be parsed by Prometheus model.ParseDuration() function.
+// Supported units: y, w, d, h, m, s, ms
 // Examples: `30s`, `1m`, `1h20m15s`, `15d`
 // +kubebuilder:validation:Pattern:="^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$"
 type Duration string
 
+var durationRe = regexp.MustCompile(`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`)
+
+// Validate returns an error if the duration can't be parsed by Prometheus.
+func (d Duration) Validate() error {
+	if d == "" || !durationRe.MatchString(string(d)) {
+		return fmt.Errorf("invalid duration %q", d)
+	}
+
+	return nil
+}
+
+// Parse converts the duration to a time.Duration, using the same units as
+// Prometheus model.ParseDuration().
+func (d Duration) Parse() (time.Duration, error) {
+	if err := d.Validate(); err != nil {
+		return 0, err
+	}
+
+	var (
+		m     = durationRe.FindStringSubmatch(string(d))
+		dur   time.Duration
+		units = []struct {
+			group int
+			unit  time.Duration
+		}{
+			{3, 365 * 24 * time.Hour}, // y
+			{5, 7 * 24 * time.Hour},   // w
+			{7, 24 * time.Hour},       // d
+			{9, time.Hour},            // h
+			{11, time.Minute},         // m
+			{13, time.Second},         // s
+			{15, time.Millisecond},    // ms
+		}
+	)
+
+	for _, u := range units {
+		if m[u.group] == "" {
+			continue
+		}
+
+		n, err := strconv.ParseInt(m[u.group], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", d, err)
+		}
+		dur += time.Duration(n) * u.unit
+	}
+
+	return dur, nil
+}
+
+// LessThan returns true if the duration is strictly shorter than the other
+// duration. It returns an error if any of them can't be parsed.
+func (d Duration) LessThan(other Duration) (bool, error) {
+	a, err := d.Parse()
+	if err != nil {
+		return false, err
+	}
+
+	b, err := other.Parse()
+	if err != nil {
+		return false, err
+	}
+
+	return a < b, nil
+}
+
+// Normalize returns the canonical form of the duration, the same as the
+// Prometheus model.Duration.String() function: the value is expressed with
+// the largest units first (e.g. `60s` becomes `1m` and `14d` becomes `2w`)
+// and a zero duration is `0s`.
+func (d Duration) Normalize() (Duration, error) {
+	dur, err := d.Parse()
+	if err != nil {
+		return "", err
+	}
+
+	if dur == 0 {
+		return "0s", nil
+	}
+
+	var (
+		ms    = int64(dur / time.Millisecond)
+		b     strings.Builder
+		units = []struct {
+			name string
+			ms   int64
+		}{
+			{"y", 1000 * 60 * 60 * 24 * 365},
+			{"w", 1000 * 60 * 60 * 24 * 7},
+			{"d", 1000 * 60 * 60 * 24},
+			{"h", 1000 * 60 * 60},
+			{"m", 1000 * 60},
+			{"s", 1000},
+			{"ms", 1},
+		}
+	)
+
+	for _, u := range units {
+		if n := ms / u.ms; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, u.name)
+			ms -= n * u.ms
+		}
+	}
+
+	return Duration(b.String()), nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface. Leading zeros are
+// removed from valid durations (e.g. `05m` is marshaled as `5m`).
+func (d Duration) MarshalYAML() (interface{}, error) {
+	if d.Validate() != nil {
+		return string(d), nil
+	}
+
+	return trimLeadingZeros(string(d)), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface. Leading zeros are
+// removed from valid durations, invalid durations are kept as-is and
+// reported by Validate().
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	*d = Duration(s)
+	if d.Validate() == nil {
+		*d = Duration(trimLeadingZeros(s))
+	}
+
+	return nil
+}
+
+// isVersionOlderThan returns true if the version (e.g. `v2.25.0`) is older
+// than the minimum version. Pre-release and build metadata are ignored.
+func isVersionOlderThan(version, minimum string) (bool, error) {
+	parse := func(v string) ([3]int, error) {
+		var parsed [3]int
+
+		v = strings.TrimPrefix(v, "v")
+		if i := strings.IndexAny(v, "-+"); i >= 0 {
+			v = v[:i]
+		}
+
+		parts := strings.Split(v, ".")
+		if len(parts) > 3 {
+			return parsed, fmt.Errorf("invalid version %q", v)
+		}
+
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 {
+				return parsed, fmt.Errorf("invalid version %q", v)
+			}
+			parsed[i] = n
+		}
+
+		return parsed, nil
+	}
+
+	v, err := parse(version)
+	if err != nil {
+		return false, err
+	}
+
+	m, err := parse(minimum)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range v {
+		if v[i] != m[i] {
+			return v[i] < m[i], nil
+		}
+	}
+
+	return false, nil
+}
+
 // GoDuration is a valid time duration that can be parsed by Go's time.ParseDuration() function.
 // Supported units: h, m, s, ms
 // Examples: `45ms`, `30s`, `1m`, `1h20m15s`
@@ -433,6 +1613,12 @@ type PrometheusSpec struct {
 	RetentionSize ByteSize `json:"retentionSize,omitempty"`
 	// Disable prometheus compaction.
 	DisableCompaction bool `json:"disableCompaction,omitempty"`
+	// Number of goroutines used to replay the WAL at startup.
+	// If unset, Prometheus uses its default value.
+	// It requires Prometheus >= v2.45.0.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WALReplayConcurrency *int32 `json:"walReplayConcurrency,omitempty"`
 	// /--rules.*/ command-line arguments.
 	Rules Rules `json:"rules,omitempty"`
 	// PrometheusRulesExcludedFromEnforce - list of prometheus rules to be excluded from enforcing
@@ -499,6 +1685,10 @@ type PrometheusSpec struct {
 	QueryLogFile string `json:"queryLogFile,omitempty"`
 	// AllowOverlappingBlocks enables vertical compaction and vertical query merge in Prometheus.
 	// This is still experimental in Prometheus so it may change in any upcoming release.
+	// Starting with Prometheus v2.39.0, overlapping blocks are
+	// always allowed (out-of-order ingestion configured by
+	// `tsdb.outOfOrderTimeWindow` depends on it) and the field is ignored.
+	// It is rejected for Prometheus v3 which removed the flag.
 	AllowOverlappingBlocks bool `json:"allowOverlappingBlocks,omitempty"`
 	// Exemplars related settings that are runtime reloadable.
 	// It requires to enable the exemplar storage feature to be effective.
@@ -506,6 +1696,12 @@ type PrometheusSpec struct {
 	// Interval between consecutive evaluations. Default: `30s`
 	// +kubebuilder:default:="30s"
 	EvaluationInterval Duration `json:"evaluationInterval,omitempty"`
+	// Defines the offset the rule evaluation timestamp of all rule groups is
+	// shifted by into the past. The `queryOffset` field of a rule group
+	// overrides this value.
+	// It requires Prometheus >= v2.53.0.
+	// +optional
+	RuleQueryOffset *Duration `json:"ruleQueryOffset,omitempty"`
 	// Enable access to prometheus web admin API. Defaults to the value of `false`.
 	// WARNING: Enabling the admin APIs enables mutating endpoints, to delete data,
 	// shutdown Prometheus, and more. Enabling this should be done with care and the
@@ -516,6 +1712,380 @@ type PrometheusSpec struct {
 	// Defines the runtime reloadable configuration of the timeseries database
 	// (TSDB).
 	TSDB TSDBSpec `json:"tsdb,omitempty"`
+	// Settings related to the OTLP receiver feature.
+	// When defined and `enableOTLPReceiver` isn't set, the operator enables
+	// the OTLP receiver of Prometheus (requires Prometheus >= v2.47.0). The
+	// `otlp` configuration section is only generated for Prometheus >= v2.55.0.
+	OTLP *OTLPConfig `json:"otlp,omitempty"`
+	// TracingConfig configures tracing in Prometheus. This is an experimental
+	// feature, it may change in any upcoming release in a breaking way.
+	// Only valid in Prometheus versions 2.34.0 and newer.
+	TracingConfig *PrometheusTracingConfig `json:"tracingConfig,omitempty"`
+}
+
+// Validate semantically validates the given PrometheusSpec.
+// It returns a *ValidationWarning when `ruleQueryOffset` is greater than the
+// evaluation interval since rules would then evaluate on stale data.
+func (s *PrometheusSpec) Validate() error {
+	var warnings []string
+
+	if s.Retention != "" {
+		if err := s.Retention.Validate(); err != nil {
+			return &PrometheusSpecValidationError{fmt.Sprintf("retention: %v", err)}
+		}
+	}
+
+	if s.RetentionSize != "" {
+		if err := s.RetentionSize.Validate(); err != nil {
+			return &PrometheusSpecValidationError{fmt.Sprintf("retentionSize: %v", err)}
+		}
+	}
+
+	if s.AllowOverlappingBlocks {
+		if err := s.validateAllowOverlappingBlocks(); err != nil {
+			if !IsValidationWarning(err) {
+				return err
+			}
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	if s.TSDB.OutOfOrderTimeWindow != "" {
+		if err := s.TSDB.OutOfOrderTimeWindow.Validate(); err != nil {
+			return &PrometheusSpecValidationError{fmt.Sprintf("tsdb.outOfOrderTimeWindow: %v", err)}
+		}
+
+		if s.Version != "" {
+			older, err := isVersionOlderThan(s.Version, "2.39.0")
+			if err != nil {
+				return &PrometheusSpecValidationError{fmt.Sprintf("version: %v", err)}
+			}
+
+			if older {
+				warnings = append(warnings, fmt.Sprintf("tsdb.outOfOrderTimeWindow is ignored by Prometheus %s: out-of-order ingestion requires Prometheus >= v2.39.0", s.Version))
+			}
+		}
+	}
+
+	if s.DisableCompaction && s.Retention == "" && s.RetentionSize == "" {
+		warnings = append(warnings, "disableCompaction is true and neither retention nor retentionSize is set: uncompacted blocks are kept for the default retention of 24h")
+	}
+
+	if s.RemoteWriteDrainOnShutdown != nil && *s.RemoteWriteDrainOnShutdown && len(s.RemoteWrite) == 0 {
+		warnings = append(warnings, "remoteWriteDrainOnShutdown has no effect without remoteWrite")
+	}
+
+	if s.RuleQueryOffset != nil {
+		offset, err := s.RuleQueryOffset.Parse()
+		if err != nil {
+			return &PrometheusSpecValidationError{fmt.Sprintf("ruleQueryOffset: %v", err)}
+		}
+
+		evaluationInterval := s.EvaluationInterval
+		if evaluationInterval == "" {
+			evaluationInterval = "30s"
+		}
+
+		interval, err := evaluationInterval.Parse()
+		if err != nil {
+			return &PrometheusSpecValidationError{fmt.Sprintf("evaluationInterval: %v", err)}
+		}
+
+		if offset > interval {
+			warnings = append(warnings, fmt.Sprintf("ruleQueryOffset %q is greater than evaluationInterval %q", *s.RuleQueryOffset, evaluationInterval))
+		}
+	}
+
+	if len(warnings) > 0 {
+		return NewValidationWarning(warnings...)
+	}
+
+	return nil
+}
+
+// validateAllowOverlappingBlocks checks that the Prometheus version still
+// supports the `storage.tsdb.allow-overlapping-blocks` flag. The flag is a
+// no-op since v2.39.0 and it has been removed in v3.0.0.
+func (s *PrometheusSpec) validateAllowOverlappingBlocks() error {
+	if s.Version == "" {
+		return NewValidationWarning("allowOverlappingBlocks is ignored by the default Prometheus version which always allows overlapping blocks")
+	}
+
+	olderThanV3, err := isVersionOlderThan(s.Version, "3.0.0")
+	if err != nil {
+		return &PrometheusSpecValidationError{fmt.Sprintf("version: %v", err)}
+	}
+
+	if !olderThanV3 {
+		return &PrometheusSpecValidationError{fmt.Sprintf("allowOverlappingBlocks isn't supported by Prometheus %s: overlapping blocks are always allowed since v2.39.0", s.Version)}
+	}
+
+	older, err := isVersionOlderThan(s.Version, "2.39.0")
+	if err != nil {
+		return &PrometheusSpecValidationError{fmt.Sprintf("version: %v", err)}
+	}
+
+	if !older {
+		return NewValidationWarning(fmt.Sprintf("allowOverlappingBlocks is ignored by Prometheus %s which always allows overlapping blocks", s.Version))
+	}
+
+	return nil
+}
+
+// FieldVersionRequirement associates a field of the Prometheus spec with the
+// minimum Prometheus version supporting it.
+// +k8s:openapi-gen=false
+type FieldVersionRequirement struct {
+	// Path of the field in the Prometheus spec.
+	Field string `json:"field"`
+	// Minimum Prometheus version supporting the field.
+	MinimumVersion string `json:"minimumVersion"`
+}
+
+func (r FieldVersionRequirement) String() string {
+	return fmt.Sprintf("%s requires Prometheus >= v%s", r.Field, r.MinimumVersion)
+}
+
+// prometheusFieldVersions lists the fields of the Prometheus spec which are
+// ignored by the operator when the Prometheus version doesn't support them.
+var prometheusFieldVersions = []struct {
+	FieldVersionRequirement
+	isSet func(*PrometheusSpec) bool
+}{
+	{FieldVersionRequirement{"walCompression", "2.11.0"}, func(s *PrometheusSpec) bool { return s.WALCompression != nil }},
+	{FieldVersionRequirement{"queryLogFile", "2.16.0"}, func(s *PrometheusSpec) bool { return s.QueryLogFile != "" }},
+	{FieldVersionRequirement{"enforcedLabelLimit", "2.27.0"}, func(s *PrometheusSpec) bool { return s.EnforcedLabelLimit != nil }},
+	{FieldVersionRequirement{"enforcedLabelNameLengthLimit", "2.27.0"}, func(s *PrometheusSpec) bool { return s.EnforcedLabelNameLengthLimit != nil }},
+	{FieldVersionRequirement{"enforcedLabelValueLengthLimit", "2.27.0"}, func(s *PrometheusSpec) bool { return s.EnforcedLabelValueLengthLimit != nil }},
+	{FieldVersionRequirement{"enforcedBodySizeLimit", "2.28.0"}, func(s *PrometheusSpec) bool { return s.EnforcedBodySizeLimit != "" }},
+	{FieldVersionRequirement{"enableRemoteWriteReceiver", "2.33.0"}, func(s *PrometheusSpec) bool { return s.EnableRemoteWriteReceiver }},
+	{FieldVersionRequirement{"tracingConfig", "2.34.0"}, func(s *PrometheusSpec) bool { return s.TracingConfig != nil }},
+	{FieldVersionRequirement{"tsdb.outOfOrderTimeWindow", "2.39.0"}, func(s *PrometheusSpec) bool { return s.TSDB.OutOfOrderTimeWindow != "" }},
+	{FieldVersionRequirement{"walReplayConcurrency", "2.45.0"}, func(s *PrometheusSpec) bool { return s.WALReplayConcurrency != nil }},
+	{FieldVersionRequirement{"enableOTLPReceiver", "2.47.0"}, func(s *PrometheusSpec) bool { return s.EnableOTLPReceiver != nil && *s.EnableOTLPReceiver }},
+	{FieldVersionRequirement{"scrapeProtocols", "2.49.0"}, func(s *PrometheusSpec) bool { return len(s.ScrapeProtocols) > 0 }},
+	{FieldVersionRequirement{"ruleQueryOffset", "2.53.0"}, func(s *PrometheusSpec) bool { return s.RuleQueryOffset != nil }},
+	{FieldVersionRequirement{"runtime", "2.53.0"}, func(s *PrometheusSpec) bool { return s.Runtime != nil }},
+	{FieldVersionRequirement{"scrapeFallbackProtocol", "3.0.0"}, func(s *PrometheusSpec) bool { return s.ScrapeFallbackProtocol != nil }},
+	{FieldVersionRequirement{"convertClassicHistogramsToNHCB", "3.0.0"}, func(s *PrometheusSpec) bool { return s.ConvertClassicHistogramsToNHCB != nil }},
+}
+
+// UnsupportedFields returns the fields which are set in the spec but not
+// supported by the given Prometheus version (e.g. `v2.45.0`).
+func (s *PrometheusSpec) UnsupportedFields(version string) ([]FieldVersionRequirement, error) {
+	var unsupported []FieldVersionRequirement
+
+	for _, fv := range prometheusFieldVersions {
+		if !fv.isSet(s) {
+			continue
+		}
+
+		older, err := isVersionOlderThan(version, fv.MinimumVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		if older {
+			unsupported = append(unsupported, fv.FieldVersionRequirement)
+		}
+	}
+
+	return unsupported, nil
+}
+
+// PrometheusSpecValidationError is returned by PrometheusSpec.Validate() on
+// semantically invalid configurations.
+// +k8s:openapi-gen=false
+type PrometheusSpecValidationError struct {
+	err string
+}
+
+func (e *PrometheusSpecValidationError) Error() string {
+	return e.err
+}
+
+// ValidateSecurityContext checks that the pod security context and the
+// volumes are consistent with the read-only root filesystem of the
+// `prometheus` container.
+// It returns a *ValidationWarning describing the inconsistencies since
+// they don't prevent the resource from being reconciled.
+func (s *PrometheusSpec) ValidateSecurityContext() error {
+	var warnings []string
+
+	if s.QueryLogFile != "" && !strings.HasPrefix(s.QueryLogFile, "/dev/") && path.Dir(s.QueryLogFile) != "." && s.hasReadOnlyRootFilesystem() {
+		dir := path.Dir(s.QueryLogFile)
+		var mount *v1.VolumeMount
+		for i, vm := range s.VolumeMounts {
+			if dir == vm.MountPath || strings.HasPrefix(dir, strings.TrimSuffix(vm.MountPath, "/")+"/") {
+				mount = &s.VolumeMounts[i]
+			}
+		}
+
+		switch {
+		case mount == nil:
+			warnings = append(warnings, fmt.Sprintf("queryLogFile %q isn't in a mounted volume and the root filesystem is read-only: mount a writable volume in %q or use a file name without directory", s.QueryLogFile, dir))
+		case mount.ReadOnly:
+			warnings = append(warnings, fmt.Sprintf("queryLogFile %q is in the read-only volume %q: mount the volume as writable", s.QueryLogFile, mount.Name))
+		}
+	}
+
+	sc := s.SecurityContext
+	usesPVC := s.Storage != nil && s.Storage.EmptyDir == nil && s.Storage.Ephemeral == nil
+	if usesPVC && sc != nil && sc.FSGroup == nil && sc.RunAsUser != nil && *sc.RunAsUser != 0 {
+		warnings = append(warnings, fmt.Sprintf("securityContext.fsGroup is unset: the storage volume may not be writable by user %d, set securityContext.fsGroup", *sc.RunAsUser))
+	}
+
+	if len(warnings) > 0 {
+		return NewValidationWarning(warnings...)
+	}
+
+	return nil
+}
+
+// hasReadOnlyRootFilesystem returns false if the `prometheus` container is
+// overridden to run with a writable root filesystem.
+func (s *PrometheusSpec) hasReadOnlyRootFilesystem() bool {
+	for _, c := range s.Containers {
+		if c.Name == "prometheus" && c.SecurityContext != nil && c.SecurityContext.ReadOnlyRootFilesystem != nil {
+			return *c.SecurityContext.ReadOnlyRootFilesystem
+		}
+	}
+
+	return true
+}
+
+// RuntimeConfig configures the values for the Prometheus process behavior.
+// +k8s:openapi-gen=true
+type RuntimeConfig struct {
+	// The Go garbage collection target percentage. Lowering this number may
+	// increase the CPU usage but reduces the memory footprint.
+	// If unset, Prometheus uses its default value.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	GoGC *int32 `json:"goGC,omitempty"`
+}
+
+// Validate semantically validates the given RuntimeConfig.
+func (rc *RuntimeConfig) Validate() error {
+	if rc == nil {
+		return nil
+	}
+
+	if rc.GoGC != nil && (*rc.GoGC < 1 || *rc.GoGC > 100) {
+		return &RuntimeConfigValidationError{fmt.Sprintf("invalid goGC %d: must be between 1 and 100", *rc.GoGC)}
+	}
+
+	return nil
+}
+
+// RuntimeConfigValidationError is returned by RuntimeConfig.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type RuntimeConfigValidationError struct {
+	err string
+}
+
+func (e *RuntimeConfigValidationError) Error() string {
+	return e.err
+}
+
+// PrometheusTracingConfig configures the export of the traces emitted by
+// Prometheus.
+// +k8s:openapi-gen=true
+type PrometheusTracingConfig struct {
+	// Client used to export the traces. Options are "http" or "grpc".
+	// +kubebuilder:validation:Enum=http;grpc
+	// +optional
+	ClientType *string `json:"clientType,omitempty"`
+	// Endpoint to send the traces to. Should be provided in format <host>:<port>.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Endpoint string `json:"endpoint"`
+	// Sets the probability a given trace will be sampled. Must be a float from 0 through 1.
+	// +optional
+	SamplingFraction *string `json:"samplingFraction,omitempty"`
+	// If disabled, the client will use a secure connection.
+	// +optional
+	Insecure *bool `json:"insecure,omitempty"`
+	// Key-value pairs to be used as headers associated with gRPC or HTTP requests.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Compression key for supported compression types. The only supported value is "gzip".
+	// +kubebuilder:validation:Enum=gzip
+	// +optional
+	Compression *string `json:"compression,omitempty"`
+	// Maximum time the exporter will wait for each batch export.
+	// +optional
+	Timeout *Duration `json:"timeout,omitempty"`
+	// TLS Config to use when sending traces.
+	// +optional
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// Validate semantically validates the given PrometheusTracingConfig.
+func (tc *PrometheusTracingConfig) Validate() error {
+	if tc == nil {
+		return nil
+	}
+
+	if tc.Endpoint == "" {
+		return &PrometheusTracingConfigValidationError{"endpoint must be set"}
+	}
+
+	if tc.ClientType != nil {
+		switch *tc.ClientType {
+		case "http", "grpc":
+		default:
+			return &PrometheusTracingConfigValidationError{fmt.Sprintf("invalid clientType %q, expected one of \"http\" or \"grpc\"", *tc.ClientType)}
+		}
+	}
+
+	if tc.SamplingFraction != nil {
+		f, err := strconv.ParseFloat(*tc.SamplingFraction, 64)
+		if err != nil {
+			return &PrometheusTracingConfigValidationError{fmt.Sprintf("invalid samplingFraction %q: %v", *tc.SamplingFraction, err)}
+		}
+		if f < 0 || f > 1 {
+			return &PrometheusTracingConfigValidationError{fmt.Sprintf("invalid samplingFraction %q: must be between 0 and 1", *tc.SamplingFraction)}
+		}
+	}
+
+	if tc.Compression != nil && *tc.Compression != "gzip" {
+		return &PrometheusTracingConfigValidationError{fmt.Sprintf("invalid compression %q, expected \"gzip\"", *tc.Compression)}
+	}
+
+	if tc.TLSConfig != nil {
+		if err := tc.TLSConfig.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrometheusTracingConfigValidationError is returned by PrometheusTracingConfig.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type PrometheusTracingConfigValidationError struct {
+	err string
+}
+
+func (e *PrometheusTracingConfigValidationError) Error() string {
+	return e.err
+}
+
+// OTLPConfig is the configuration for writing to the OTLP endpoint.
+//
+// +k8s:openapi-gen=true
+type OTLPConfig struct {
+	// List of OpenTelemetry Attributes that should be promoted to metric labels, defaults to none.
+	// Attribute names are converted to label names the same way as Prometheus
+	// does (e.g. `service.name` becomes `service_name`).
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	PromoteResourceAttributes []string `json:"promoteResourceAttributes,omitempty"`
 }
 
 type TSDBSpec struct {
@@ -524,7 +2094,9 @@ type TSDBSpec struct {
 	// An out-of-order/out-of-bounds sample is ingested into the TSDB as long as
 	// the timestamp of the sample is >= (TSDB.MaxTime - outOfOrderTimeWindow).
 	// Out of order ingestion is an experimental feature and requires
-	// Prometheus >= v2.39.0.
+	// Prometheus >= v2.39.0. It relies on overlapping blocks which are always
+	// allowed by these versions so `allowOverlappingBlocks` doesn't need to
+	// be set.
 	OutOfOrderTimeWindow Duration `json:"outOfOrderTimeWindow,omitempty"`
 }
 
@@ -623,6 +2195,11 @@ type PrometheusStatus struct {
 	AvailableReplicas int32 `json:"availableReplicas"`
 	// Total number of unavailable pods targeted by this Prometheus deployment.
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
+	// Number of shards desired for this Prometheus deployment (1 when
+	// sharding isn't used).
+	ShardsDesired int32 `json:"shardsDesired"`
+	// Number of shards for which the operator observed a StatefulSet.
+	ShardsObserved int32 `json:"shardsObserved"`
 	// The current state of the Prometheus deployment.
 	// +listType=map
 	// +listMapKey=type
@@ -633,6 +2210,28 @@ type PrometheusStatus struct {
 	// +listMapKey=shardID
 	// +optional
 	ShardStatuses []ShardStatus `json:"shardStatuses,omitempty"`
+	// Status of the Thanos sidecars uploading blocks to object storage.
+	// It is only reported when the Thanos sidecar is configured with object
+	// storage and its HTTP endpoint isn't bound to the loopback interface.
+	// +optional
+	ThanosSidecar *ThanosSidecarStatus `json:"thanosSidecar,omitempty"`
+}
+
+// ThanosSidecarStatus summarizes the object storage uploads of the Thanos
+// sidecars, as reported by the sidecar metrics of the ready pods.
+// +k8s:openapi-gen=true
+type ThanosSidecarStatus struct {
+	// True if all the sidecars which attempted an upload successfully uploaded
+	// at least one block to object storage since their last upload failure,
+	// false if any of them only reported upload failures or failed after its
+	// last successful upload.
+	// Unset if none of the sidecars attempted an upload yet.
+	// +optional
+	ObjectStorageConnected *bool `json:"objectStorageConnected,omitempty"`
+	// Time of the most recent successful block upload to object storage
+	// across all sidecars.
+	// +optional
+	LastUploadTime *metav1.Time `json:"lastUploadTime,omitempty"`
 }
 
 // PrometheusCondition represents the state of the resources associated with the Prometheus resource.
@@ -674,6 +2273,8 @@ const (
 	// the underlying resources with the Prometheus object spec.
 	// The possible status values for this condition type are:
 	// - True: the reconciliation was successful.
+	// - Degraded: the reconciliation was successful but some fields of the
+	// spec are ignored (e.g. not supported by the Prometheus version).
 	// - False: the reconciliation failed.
 	// - Unknown: the operator couldn't determine the condition status.
 	PrometheusReconciled PrometheusConditionType = "Reconciled"
@@ -709,6 +2310,12 @@ type ShardStatus struct {
 type AlertingSpec struct {
 	// AlertmanagerEndpoints Prometheus should fire alerts against.
 	Alertmanagers []AlertmanagerEndpoints `json:"alertmanagers"`
+	// Relabel configs applied to the alerts before they are sent to the
+	// Alertmanagers. They are applied after the relabeling dropping the
+	// replica label and before the relabel configs from
+	// `additionalAlertRelabelConfigs`.
+	// +optional
+	AlertRelabelConfigs []RelabelConfig `json:"alertRelabelConfigs,omitempty"`
 }
 
 // StorageSpec defines the configured storage for a group Prometheus servers.
@@ -777,6 +2384,41 @@ type EmbeddedObjectMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,12,rep,name=annotations"`
 }
 
+// Validate semantically validates the given EmbeddedObjectMetadata.
+func (m *EmbeddedObjectMetadata) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	for k, v := range m.Labels {
+		if errs := validation.IsQualifiedName(k); len(errs) != 0 {
+			return &EmbeddedObjectMetadataValidationError{fmt.Sprintf("invalid label key %q: %s", k, strings.Join(errs, ", "))}
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) != 0 {
+			return &EmbeddedObjectMetadataValidationError{fmt.Sprintf("invalid value for label %q: %s", k, strings.Join(errs, ", "))}
+		}
+	}
+
+	for k := range m.Annotations {
+		if errs := validation.IsQualifiedName(strings.ToLower(k)); len(errs) != 0 {
+			return &EmbeddedObjectMetadataValidationError{fmt.Sprintf("invalid annotation key %q: %s", k, strings.Join(errs, ", "))}
+		}
+	}
+
+	return nil
+}
+
+// EmbeddedObjectMetadataValidationError is returned by EmbeddedObjectMetadata.Validate()
+// on semantically invalid metadata.
+// +k8s:openapi-gen=false
+type EmbeddedObjectMetadataValidationError struct {
+	err string
+}
+
+func (e *EmbeddedObjectMetadataValidationError) Error() string {
+	return e.err
+}
+
 // QuerySpec defines the query command line flags when starting Prometheus.
 // +k8s:openapi-gen=true
 type QuerySpec struct {
@@ -796,6 +2438,22 @@ type PrometheusWebSpec struct {
 	WebConfigFileFields `json:",inline"`
 	// The prometheus web page title
 	PageTitle *string `json:"pageTitle,omitempty"`
+	// The port on which the Prometheus web server listens.
+	// This defaults to 9090. The governing service keeps exposing port 9090
+	// and forwards the traffic to the named container port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	ListenPort *int32 `json:"listenPort,omitempty"`
+}
+
+// Validate semantically validates the given PrometheusWebSpec.
+func (w *PrometheusWebSpec) Validate() error {
+	if w == nil || w.ListenPort == nil {
+		return nil
+	}
+
+	return ValidatePort(intstr.FromInt(int(*w.ListenPort)))
 }
 
 // AlertmanagerWebSpec defines the web command line flags when starting Alertmanager.
@@ -813,6 +2471,30 @@ type WebConfigFileFields struct {
 	HTTPConfig *WebHTTPConfig `json:"httpConfig,omitempty"`
 }
 
+// Validate semantically validates the given WebConfigFileFields.
+func (c *WebConfigFileFields) Validate() error {
+	if err := c.TLSConfig.Validate(); err != nil {
+		return err
+	}
+
+	if c.HTTPConfig != nil && c.HTTPConfig.HTTP2 != nil && *c.HTTPConfig.HTTP2 && c.TLSConfig == nil {
+		return &WebConfigFileFieldsValidationError{"invalid web http config: http2 is only supported with TLS, tlsConfig must be defined"}
+	}
+
+	return nil
+}
+
+// WebConfigFileFieldsValidationError is returned by
+// WebConfigFileFields.Validate() on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type WebConfigFileFieldsValidationError struct {
+	err string
+}
+
+func (e *WebConfigFileFieldsValidationError) Error() string {
+	return e.err
+}
+
 // WebHTTPConfig defines HTTP parameters for web server.
 // +k8s:openapi-gen=true
 type WebHTTPConfig struct {
@@ -867,8 +2549,10 @@ type WebTLSConfig struct {
 	// Contains the CA certificate for client certificate authentication to the server.
 	ClientCA SecretOrConfigMap `json:"client_ca,omitempty"`
 	// Minimum TLS version that is acceptable. Defaults to TLS12.
+	// Valid values are TLS10, TLS11, TLS12 and TLS13.
 	MinVersion string `json:"minVersion,omitempty"`
 	// Maximum TLS version that is acceptable. Defaults to TLS13.
+	// Valid values are TLS10, TLS11, TLS12 and TLS13.
 	MaxVersion string `json:"maxVersion,omitempty"`
 	// List of supported cipher suites for TLS versions up to TLS 1.2. If empty,
 	// Go default cipher suites are used. Available cipher suites are documented
@@ -919,9 +2603,70 @@ func (c *WebTLSConfig) Validate() error {
 		return &WebTLSConfigError{"invalid web tls config: key must be defined"}
 	}
 
+	var minVersion, maxVersion uint16
+	if c.MinVersion != "" {
+		v, found := webTLSVersions[c.MinVersion]
+		if !found {
+			return &WebTLSConfigError{fmt.Sprintf("invalid web tls config: unknown minVersion %q", c.MinVersion)}
+		}
+		minVersion = v
+	}
+
+	if c.MaxVersion != "" {
+		v, found := webTLSVersions[c.MaxVersion]
+		if !found {
+			return &WebTLSConfigError{fmt.Sprintf("invalid web tls config: unknown maxVersion %q", c.MaxVersion)}
+		}
+		maxVersion = v
+	}
+
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return &WebTLSConfigError{fmt.Sprintf("invalid web tls config: minVersion %q is greater than maxVersion %q", c.MinVersion, c.MaxVersion)}
+	}
+
+	for _, cs := range c.CipherSuites {
+		if !isKnownCipherSuite(cs) {
+			return &WebTLSConfigError{fmt.Sprintf("invalid web tls config: unknown cipher suite %q", cs)}
+		}
+	}
+
+	for _, curve := range c.CurvePreferences {
+		if _, found := webTLSCurves[curve]; !found {
+			return &WebTLSConfigError{fmt.Sprintf("invalid web tls config: unknown curve %q", curve)}
+		}
+	}
+
 	return nil
 }
 
+// webTLSVersions maps the TLS versions supported by the web server and the
+// client TLS configurations to their protocol values.
+var webTLSVersions = map[string]uint16{
+	"TLS10": tls.VersionTLS10,
+	"TLS11": tls.VersionTLS11,
+	"TLS12": tls.VersionTLS12,
+	"TLS13": tls.VersionTLS13,
+}
+
+// webTLSCurves is the set of curves supported by the web server
+// configuration.
+var webTLSCurves = map[string]struct{}{
+	"CurveP256": {},
+	"CurveP384": {},
+	"CurveP521": {},
+	"X25519":    {},
+}
+
+func isKnownCipherSuite(name string) bool {
+	for _, cs := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if cs.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ThanosSpec defines parameters for a Prometheus server within a Thanos deployment.
 // +k8s:openapi-gen=true
 type ThanosSpec struct {
@@ -988,6 +2733,10 @@ type ThanosSpec struct {
 	MinTime string `json:"minTime,omitempty"`
 	// ReadyTimeout is the maximum time Thanos sidecar will wait for Prometheus to start. Eg 10m
 	ReadyTimeout Duration `json:"readyTimeout,omitempty"`
+	// How often to retrieve the Prometheus configuration.
+	// It requires Thanos >= v0.29.0 and is ignored for older versions.
+	// +optional
+	GetConfigInterval *Duration `json:"getConfigInterval,omitempty"`
 	// VolumeMounts allows configuration of additional VolumeMounts on the output StatefulSet definition.
 	// VolumeMounts specified will be appended to other VolumeMounts in the thanos-sidecar container.
 	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
@@ -1000,22 +2749,95 @@ type ThanosSpec struct {
 	AdditionalArgs []Argument `json:"additionalArgs,omitempty"`
 }
 
+// Validate semantically validates the given ThanosSpec.
+func (t *ThanosSpec) Validate() error {
+	if t == nil {
+		return nil
+	}
+
+	if t.MinTime != "" {
+		if err := validateTimeOrDuration(t.MinTime); err != nil {
+			return &ThanosSpecValidationError{fmt.Sprintf("invalid minTime %q: %s", t.MinTime, err)}
+		}
+	}
+
+	if t.GetConfigInterval != nil {
+		if err := t.GetConfigInterval.Validate(); err != nil {
+			return &ThanosSpecValidationError{fmt.Sprintf("invalid getConfigInterval: %s", err)}
+		}
+	}
+
+	return nil
+}
+
+// ThanosSpecValidationError is returned by ThanosSpec.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type ThanosSpecValidationError struct {
+	err string
+}
+
+func (e *ThanosSpecValidationError) Error() string {
+	return e.err
+}
+
+// validateTimeOrDuration checks that the value is either a RFC3339 timestamp
+// or a duration relative to the current time such as `-1d`.
+func validateTimeOrDuration(s string) error {
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return nil
+	}
+
+	if err := Duration(strings.TrimPrefix(s, "-")).Validate(); err != nil {
+		return errors.New("must be a RFC3339 timestamp or a relative duration")
+	}
+
+	return nil
+}
+
 // RemoteWriteSpec defines the configuration to write samples from Prometheus
 // to a remote endpoint.
 // +k8s:openapi-gen=true
 type RemoteWriteSpec struct {
 	// The URL of the endpoint to send samples to.
-	URL string `json:"url"`
+	// Exactly one of `url` and `urls` must be set.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// The URLs of the endpoints to send samples to. The operator generates
+	// one remote write queue per URL sharing the same settings, the name of
+	// each queue (if specified) is suffixed by the index of the URL (e.g.
+	// `<name>-0`).
+	// Exactly one of `url` and `urls` must be set.
+	// +listType=set
+	// +optional
+	URLs []string `json:"urls,omitempty"`
 	// The name of the remote write queue, it must be unique if specified. The
 	// name is used in metrics and logging in order to differentiate queues.
 	// Only valid in Prometheus versions 2.15.0 and newer.
 	Name string `json:"name,omitempty"`
+	// Whether the remote write configuration is enabled. When false, the
+	// operator omits it from the generated configuration. The name must be
+	// set to identify the entry when it is disabled.
+	// Defaults to true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
 	// Enables sending of exemplars over remote write. Note that
 	// exemplar-storage itself must be enabled using the enableFeature option
-	// for exemplars to be scraped in the first place.  Only valid in
+	// for exemplars to be scraped in the first place, otherwise the
+	// Prometheus object reports a Degraded condition.  Only valid in
 	// Prometheus versions 2.27.0 and newer.
 	SendExemplars *bool `json:"sendExemplars,omitempty"`
+	// The Remote Write message's version to use when writing to the endpoint.
+	// `V1.0` corresponds to the `prometheus.WriteRequest` protobuf message
+	// introduced in Remote Write 1.0. `V2.0` corresponds to the
+	// `io.prometheus.write.v2.Request` protobuf message introduced in Remote
+	// Write 2.0.
+	// When unset, Prometheus uses its default value (`V1.0`).
+	// Only valid in Prometheus versions 2.54.0 and newer.
+	// +kubebuilder:validation:Enum=V1.0;V2.0
+	MessageVersion *string `json:"messageVersion,omitempty"`
 	// Timeout for requests to the remote write endpoint.
+	// Defaults to 30s when empty.
 	RemoteTimeout Duration `json:"remoteTimeout,omitempty"`
 	// Custom HTTP headers to be sent along with each remote write request.
 	// Be aware that headers that are set by Prometheus itself can't be overwritten.
@@ -1039,12 +2861,62 @@ type RemoteWriteSpec struct {
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
 	// Optional ProxyURL.
 	ProxyURL string `json:"proxyUrl,omitempty"`
+	// Comma-separated list of IP addresses, CIDR notations and domain names
+	// that should be excluded from proxying. IP addresses and domain names can
+	// contain port numbers. It requires proxyUrl to be set.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +optional
+	NoProxy *string `json:"noProxy,omitempty"`
+	// Whether to use the proxy configuration defined by the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables. It can't be set together
+	// with proxyUrl or noProxy.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +optional
+	ProxyFromEnvironment *bool `json:"proxyFromEnvironment,omitempty"`
+	// Headers to send to the proxy during CONNECT requests. The values are
+	// read from secrets in the namespace of the object.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +mapType:=atomic
+	// +optional
+	ProxyConnectHeader map[string][]v1.SecretKeySelector `json:"proxyConnectHeader,omitempty"`
+	// Configure whether HTTP requests follow HTTP 3xx redirects.
+	// Only valid in Prometheus versions 2.26.0 and newer.
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+	// Whether to enable HTTP2.
+	// Only valid in Prometheus versions 2.35.0 and newer.
+	EnableHTTP2 *bool `json:"enableHTTP2,omitempty"`
 	// QueueConfig allows tuning of the remote write queue parameters.
 	QueueConfig *QueueConfig `json:"queueConfig,omitempty"`
 	// MetadataConfig configures the sending of series metadata to the remote storage.
 	MetadataConfig *MetadataConfig `json:"metadataConfig,omitempty"`
 }
 
+// EffectiveRemoteTimeout returns the timeout for requests to the remote
+// write endpoint, that is the Prometheus default (30s) when RemoteTimeout is
+// empty.
+func (s *RemoteWriteSpec) EffectiveRemoteTimeout() Duration {
+	if s.RemoteTimeout == "" {
+		return "30s"
+	}
+
+	return s.RemoteTimeout
+}
+
+// ProxyConfig returns the proxy settings of the remote write endpoint.
+func (s *RemoteWriteSpec) ProxyConfig() ProxyConfig {
+	var proxyURL *string
+	if s.ProxyURL != "" {
+		proxyURL = &s.ProxyURL
+	}
+
+	return ProxyConfig{
+		ProxyURL:             proxyURL,
+		NoProxy:              s.NoProxy,
+		ProxyFromEnvironment: s.ProxyFromEnvironment,
+		ProxyConnectHeader:   s.ProxyConnectHeader,
+	}
+}
+
 // QueueConfig allows the tuning of remote write's queue_config parameters.
 // This object is referenced in the RemoteWriteSpec object.
 // +k8s:openapi-gen=true
@@ -1066,8 +2938,78 @@ type QueueConfig struct {
 	// MaxBackoff is the maximum retry delay.
 	MaxBackoff string `json:"maxBackoff,omitempty"`
 	// Retry upon receiving a 429 status code from the remote-write storage.
+	// It maps to the `retry_on_http_429` field of the Prometheus queue
+	// configuration and requires Prometheus >= v2.26.0, the reconciliation
+	// fails if it is enabled with an older version.
 	// This is experimental feature and might change in the future.
 	RetryOnRateLimit bool `json:"retryOnRateLimit,omitempty"`
+	// SampleAgeLimit drops the samples older than the limit from the queue
+	// instead of sending them, e.g. after a long outage of the remote
+	// storage. It maps to the `sample_age_limit` field of the Prometheus queue
+	// configuration and is ignored by Prometheus versions older than v2.50.0.
+	// +optional
+	SampleAgeLimit *Duration `json:"sampleAgeLimit,omitempty"`
+}
+
+// Validate semantically validates the given QueueConfig.
+// It returns a *ValidationWarning when the capacity is lower than the
+// maximum number of samples per send since it limits the remote write
+// throughput.
+func (q *QueueConfig) Validate() error {
+	if q == nil {
+		return nil
+	}
+
+	if q.Capacity < 0 {
+		return &QueueConfigValidationError{fmt.Sprintf("capacity %d must not be negative", q.Capacity)}
+	}
+
+	if q.MaxSamplesPerSend < 0 {
+		return &QueueConfigValidationError{fmt.Sprintf("maxSamplesPerSend %d must not be negative", q.MaxSamplesPerSend)}
+	}
+
+	if q.SampleAgeLimit != nil {
+		if err := q.SampleAgeLimit.Validate(); err != nil {
+			return &QueueConfigValidationError{fmt.Sprintf("sampleAgeLimit: %v", err)}
+		}
+	}
+
+	if q.Capacity > 0 && q.MaxSamplesPerSend > 0 && q.Capacity < q.MaxSamplesPerSend {
+		return NewValidationWarning(fmt.Sprintf("capacity %d is lower than maxSamplesPerSend %d, a capacity of %d (3 x maxSamplesPerSend) is recommended", q.Capacity, q.MaxSamplesPerSend, 3*q.MaxSamplesPerSend))
+	}
+
+	return nil
+}
+
+// QueueConfigValidationError is returned by QueueConfig.Validate() on
+// semantically invalid configurations.
+// +k8s:openapi-gen=false
+type QueueConfigValidationError struct {
+	err string
+}
+
+func (e *QueueConfigValidationError) Error() string {
+	return e.err
+}
+
+// EffectiveRetryOnRateLimit returns whether the queue retries upon receiving
+// a 429 status code for the given Prometheus version. It returns an error if
+// RetryOnRateLimit is enabled and the version doesn't support it.
+func (q *QueueConfig) EffectiveRetryOnRateLimit(version string) (bool, error) {
+	if q == nil || !q.RetryOnRateLimit {
+		return false, nil
+	}
+
+	older, err := isVersionOlderThan(version, "2.26.0")
+	if err != nil {
+		return false, err
+	}
+
+	if older {
+		return false, fmt.Errorf("retryOnRateLimit requires Prometheus >= v2.26.0, got %q", version)
+	}
+
+	return true, nil
 }
 
 // Sigv4 optionally configures AWS's Signature Verification 4 signing process to
@@ -1100,6 +3042,7 @@ type RemoteReadSpec struct {
 	// in a selector to query the remote read endpoint.
 	RequiredMatchers map[string]string `json:"requiredMatchers,omitempty"`
 	// Timeout for requests to the remote read endpoint.
+	// Defaults to 1m when empty.
 	RemoteTimeout Duration `json:"remoteTimeout,omitempty"`
 	// Custom HTTP headers to be sent along with each remote read request.
 	// Be aware that headers that are set by Prometheus itself can't be overwritten.
@@ -1122,6 +3065,55 @@ type RemoteReadSpec struct {
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
 	// Optional ProxyURL.
 	ProxyURL string `json:"proxyUrl,omitempty"`
+	// Comma-separated list of IP addresses, CIDR notations and domain names
+	// that should be excluded from proxying. IP addresses and domain names can
+	// contain port numbers. It requires proxyUrl to be set.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +optional
+	NoProxy *string `json:"noProxy,omitempty"`
+	// Whether to use the proxy configuration defined by the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables. It can't be set together
+	// with proxyUrl or noProxy.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +optional
+	ProxyFromEnvironment *bool `json:"proxyFromEnvironment,omitempty"`
+	// Headers to send to the proxy during CONNECT requests. The values are
+	// read from secrets in the namespace of the object.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +mapType:=atomic
+	// +optional
+	ProxyConnectHeader map[string][]v1.SecretKeySelector `json:"proxyConnectHeader,omitempty"`
+	// Configure whether HTTP requests follow HTTP 3xx redirects.
+	// Only valid in Prometheus versions 2.26.0 and newer.
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+	// Whether to enable HTTP2.
+	// Only valid in Prometheus versions 2.35.0 and newer.
+	EnableHTTP2 *bool `json:"enableHTTP2,omitempty"`
+}
+
+// EffectiveRemoteTimeout returns the timeout for requests to the remote read
+// endpoint, that is the Prometheus default (1m) when RemoteTimeout is empty.
+func (s *RemoteReadSpec) EffectiveRemoteTimeout() Duration {
+	if s.RemoteTimeout == "" {
+		return "1m"
+	}
+
+	return s.RemoteTimeout
+}
+
+// ProxyConfig returns the proxy settings of the remote read endpoint.
+func (s *RemoteReadSpec) ProxyConfig() ProxyConfig {
+	var proxyURL *string
+	if s.ProxyURL != "" {
+		proxyURL = &s.ProxyURL
+	}
+
+	return ProxyConfig{
+		ProxyURL:             proxyURL,
+		NoProxy:              s.NoProxy,
+		ProxyFromEnvironment: s.ProxyFromEnvironment,
+		ProxyConnectHeader:   s.ProxyConnectHeader,
+	}
 }
 
 // LabelName is a valid Prometheus label name which may only contain ASCII letters, numbers, as well as underscores.
@@ -1143,6 +3135,8 @@ type RelabelConfig struct {
 	//It is mandatory for replace actions. Regex capture groups are available.
 	TargetLabel string `json:"targetLabel,omitempty"`
 	//Regular expression against which the extracted value is matched. Default is '(.*)'
+	//The expression is anchored at both ends by Prometheus, so it has to match the
+	//whole value. Use the `(?i)` flag for case-insensitive matching.
 	Regex string `json:"regex,omitempty"`
 	// Modulus to take of the hash of the source label values.
 	Modulus uint64 `json:"modulus,omitempty"`
@@ -1156,6 +3150,69 @@ type RelabelConfig struct {
 	Action string `json:"action,omitempty"`
 }
 
+// Validate semantically validates the given RelabelConfig.
+// The regular expression is compiled with the same anchoring as Prometheus
+// uses.
+func (c *RelabelConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Action != "" && !isValidRelabelAction(c.Action) {
+		return &RelabelConfigValidationError{
+			err: fmt.Sprintf("invalid relabel action %q, expected one of %s", c.Action, strings.Join(relabelActions, ", ")),
+		}
+	}
+
+	if _, err := regexp.Compile("^(?:" + c.Regex + ")$"); err != nil {
+		action := c.Action
+		if action == "" {
+			action = "replace"
+		}
+
+		return &RelabelConfigValidationError{
+			err: fmt.Sprintf("invalid regex %q for %s relabel action: %s", c.Regex, action, err),
+		}
+	}
+
+	return nil
+}
+
+// relabelActions lists the valid relabel actions, it must be kept in sync
+// with the enum of the RelabelConfig.Action field.
+var relabelActions = []string{
+	"replace", "Replace",
+	"keep", "Keep",
+	"drop", "Drop",
+	"hashmod", "HashMod",
+	"labelmap", "LabelMap",
+	"labeldrop", "LabelDrop",
+	"labelkeep", "LabelKeep",
+	"lowercase", "Lowercase",
+	"uppercase", "Uppercase",
+}
+
+func isValidRelabelAction(action string) bool {
+	for _, a := range relabelActions {
+		if action == a {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RelabelConfigValidationError is returned by RelabelConfig.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type RelabelConfigValidationError struct {
+	err string
+}
+
+func (e *RelabelConfigValidationError) Error() string {
+	return e.err
+}
+
 // APIServerConfig defines a host and auth methods to access apiserver.
 // More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#kubernetes_sd_config
 // +k8s:openapi-gen=true
@@ -1173,6 +3230,8 @@ type APIServerConfig struct {
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
 	// Authorization section for accessing apiserver
 	Authorization *Authorization `json:"authorization,omitempty"`
+	// Optional proxy configuration for accessing apiserver.
+	ProxyConfig `json:",inline"`
 }
 
 // AlertmanagerEndpoints defines a selection of a single Endpoints object
@@ -1194,15 +3253,148 @@ type AlertmanagerEndpoints struct {
 	// BearerTokenFile to read from filesystem to use when authenticating to
 	// Alertmanager.
 	BearerTokenFile string `json:"bearerTokenFile,omitempty"`
+	// Secret containing the bearer token to use when authenticating to
+	// Alertmanager. The secret needs to be in the same namespace as the
+	// Prometheus object and accessible by the Prometheus Operator.
+	// Cannot be set at the same time as `bearerTokenFile` or `authorization`.
+	// +optional
+	BearerTokenSecret *v1.SecretKeySelector `json:"bearerTokenSecret,omitempty"`
 	// Authorization section for this alertmanager endpoint
 	Authorization *SafeAuthorization `json:"authorization,omitempty"`
 	// Version of the Alertmanager API that Prometheus uses to send alerts. It
-	// can be "v1" or "v2".
+	// can be "v1" or "v2". When empty, the operator doesn't set the field and
+	// Prometheus uses its default value ("v2" for Prometheus >= 2.11.0).
+	// The v1 API of Alertmanager is deprecated.
+	//+kubebuilder:validation:Enum="";v1;v2
 	APIVersion string `json:"apiVersion,omitempty"`
 	// Timeout is a per-target Alertmanager timeout when pushing alerts.
 	Timeout *Duration `json:"timeout,omitempty"`
 }
 
+// Validate semantically validates the given AlertmanagerEndpoints.
+// It returns a *ValidationWarning when the deprecated v1 API is used.
+func (am *AlertmanagerEndpoints) Validate() error {
+	if PortAsString(am.Port) == "" {
+		return &AlertmanagerEndpointsValidationError{"port must be set"}
+	}
+
+	if err := ValidatePort(am.Port); err != nil {
+		return &AlertmanagerEndpointsValidationError{err.Error()}
+	}
+
+	switch am.Scheme {
+	case "", "http", "https":
+	default:
+		return &AlertmanagerEndpointsValidationError{fmt.Sprintf("invalid scheme %q, expected one of \"http\" or \"https\"", am.Scheme)}
+	}
+
+	if am.BearerTokenSecret != nil {
+		if am.BearerTokenFile != "" {
+			return &AlertmanagerEndpointsValidationError{"bearerTokenFile and bearerTokenSecret are mutually exclusive"}
+		}
+
+		if am.Authorization != nil {
+			return &AlertmanagerEndpointsValidationError{"authorization and bearerTokenSecret are mutually exclusive"}
+		}
+
+		if am.BearerTokenSecret.Name == "" || am.BearerTokenSecret.Key == "" {
+			return &AlertmanagerEndpointsValidationError{"bearerTokenSecret must reference a secret name and key"}
+		}
+	}
+
+	switch am.APIVersion {
+	case "", "v2":
+	case "v1":
+		return NewValidationWarning("apiVersion \"v1\" is deprecated, use \"v2\" instead")
+	default:
+		return &AlertmanagerEndpointsValidationError{fmt.Sprintf("invalid apiVersion %q, expected one of \"v1\" or \"v2\"", am.APIVersion)}
+	}
+
+	return nil
+}
+
+// AlertmanagerEndpointsValidationError is returned by AlertmanagerEndpoints.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type AlertmanagerEndpointsValidationError struct {
+	err string
+}
+
+func (e *AlertmanagerEndpointsValidationError) Error() string {
+	return e.err
+}
+
+// PortAsString returns the port name or the port number as a string. It
+// returns an empty string if the port isn't set (the zero port number is
+// considered as unset).
+func PortAsString(p intstr.IntOrString) string {
+	if p.Type == intstr.String {
+		return p.StrVal
+	}
+
+	if p.IntVal == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(int(p.IntVal))
+}
+
+// ScrapeProtocol represents a protocol used by Prometheus for scraping metrics.
+// +kubebuilder:validation:Enum=PrometheusProto;OpenMetricsText0.0.1;OpenMetricsText1.0.0;PrometheusText0.0.4
+type ScrapeProtocol string
+
+const (
+	PrometheusProto      ScrapeProtocol = "PrometheusProto"
+	PrometheusText0_0_4  ScrapeProtocol = "PrometheusText0.0.4"
+	OpenMetricsText0_0_1 ScrapeProtocol = "OpenMetricsText0.0.1"
+	OpenMetricsText1_0_0 ScrapeProtocol = "OpenMetricsText1.0.0"
+)
+
+// Validate checks that the scrape protocol is recognized.
+func (sp ScrapeProtocol) Validate() error {
+	switch sp {
+	case PrometheusProto, PrometheusText0_0_4, OpenMetricsText0_0_1, OpenMetricsText1_0_0:
+		return nil
+	}
+
+	return fmt.Errorf("unknown scrape protocol %q", sp)
+}
+
+// ValidateScrapeProtocols checks that the protocols are recognized and
+// listed only once.
+func ValidateScrapeProtocols(protocols []ScrapeProtocol) error {
+	seen := make(map[ScrapeProtocol]struct{}, len(protocols))
+	for _, p := range protocols {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+
+		if _, found := seen[p]; found {
+			return fmt.Errorf("duplicate scrape protocol %q", p)
+		}
+		seen[p] = struct{}{}
+	}
+
+	return nil
+}
+
+// ValidatePort checks that the port is either a number between 1 and 65535
+// or a valid IANA service name.
+func ValidatePort(p intstr.IntOrString) error {
+	var errs []string
+	if p.Type == intstr.String {
+		errs = validation.IsValidPortName(p.StrVal)
+	} else {
+		errs = validation.IsValidPortNum(int(p.IntVal))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid port %q: %s", p.String(), strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:categories="prometheus-operator",shortName="smon"
@@ -1227,6 +3419,10 @@ type ServiceMonitorSpec struct {
 	//
 	// If the value of this field is empty or if the label doesn't exist for the given Service, the `job` label of the metrics defaults to the name of the Kubernetes Service.
 	JobLabel string `json:"jobLabel,omitempty"`
+	// The scrape class to apply. When not set, the default scrape class of
+	// the Prometheus object (if any) is used.
+	// +optional
+	ScrapeClassName *string `json:"scrapeClassName,omitempty"`
 	// TargetLabels transfers labels from the Kubernetes `Service` onto the created metrics.
 	TargetLabels []string `json:"targetLabels,omitempty"`
 	// PodTargetLabels transfers labels on the Kubernetes `Pod` onto the created metrics.
@@ -1250,6 +3446,66 @@ type ServiceMonitorSpec struct {
 	// Per-scrape limit on length of labels value that will be accepted for a sample.
 	// Only valid in Prometheus versions 2.27.0 and newer.
 	LabelValueLengthLimit uint64 `json:"labelValueLengthLimit,omitempty"`
+	// Attaches node metadata to discovered targets.
+	// Only valid in Prometheus versions 2.37.0 and newer.
+	AttachMetadata *AttachMetadata `json:"attachMetadata,omitempty"`
+}
+
+// Validate semantically validates the given ServiceMonitorSpec.
+func (sms *ServiceMonitorSpec) Validate() error {
+	if err := sms.NamespaceSelector.Validate(); err != nil && !IsValidationWarning(err) {
+		return fmt.Errorf("namespaceSelector: %w", err)
+	}
+
+	for i := range sms.Endpoints {
+		if err := sms.Endpoints[i].Validate(); err != nil {
+			return fmt.Errorf("endpoints[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ProjectedToken defines a projected service account token mounted in the
+// Prometheus pods.
+// +k8s:openapi-gen=true
+type ProjectedToken struct {
+	// Intended audience of the token.
+	// +kubebuilder:validation:MinLength=1
+	Audience string `json:"audience"`
+	// Requested duration of validity of the token in seconds. The kubelet
+	// rotates the token before it expires. Defaults to 3600 seconds.
+	// +kubebuilder:validation:Minimum=600
+	// +optional
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+}
+
+// Validate semantically validates the given ProjectedToken.
+func (pt *ProjectedToken) Validate() error {
+	if pt == nil {
+		return nil
+	}
+
+	if pt.Audience == "" {
+		return &ProjectedTokenValidationError{"audience must be defined"}
+	}
+
+	if pt.ExpirationSeconds != nil && *pt.ExpirationSeconds < 600 {
+		return &ProjectedTokenValidationError{fmt.Sprintf("expirationSeconds %d must be greater than or equal to 600", *pt.ExpirationSeconds)}
+	}
+
+	return nil
+}
+
+// ProjectedTokenValidationError is returned by ProjectedToken.Validate() on
+// semantically invalid configurations.
+// +k8s:openapi-gen=false
+type ProjectedTokenValidationError struct {
+	err string
+}
+
+func (e *ProjectedTokenValidationError) Error() string {
+	return e.err
 }
 
 // Endpoint defines a scrapeable endpoint serving Prometheus metrics.
@@ -1272,6 +3528,12 @@ type Endpoint struct {
 	// Timeout after which the scrape is ended
 	// If not specified, the Prometheus global scrape timeout is used unless it is less than `Interval` in which the latter is used.
 	ScrapeTimeout Duration `json:"scrapeTimeout,omitempty"`
+	// BodySizeLimit defines the maximum size of uncompressed response body
+	// that will be accepted by Prometheus for this target. Example: 100MB.
+	// If EnforcedBodySizeLimit is set on the Prometheus object, the smaller
+	// of the two values applies.
+	// Only valid in Prometheus versions 2.28.0 and newer.
+	BodySizeLimit *ByteSize `json:"bodySizeLimit,omitempty"`
 	// TLS configuration to use when scraping the endpoint
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
 	// File to read bearer token for scraping targets.
@@ -1280,6 +3542,13 @@ type Endpoint struct {
 	// needs to be in the same namespace as the service monitor and accessible by
 	// the Prometheus Operator.
 	BearerTokenSecret v1.SecretKeySelector `json:"bearerTokenSecret,omitempty"`
+	// Projected service account token to use as bearer token for scraping
+	// targets. The operator mounts a token with the requested audience in
+	// the Prometheus pods and references it via `bearer_token_file`.
+	// Cannot be set at the same time as `bearerTokenFile`, `bearerTokenSecret`,
+	// `basicAuth`, `oauth2` or `authorization`.
+	// +optional
+	BearerTokenProjected *ProjectedToken `json:"bearerTokenProjected,omitempty"`
 	// Authorization section for this endpoint
 	Authorization *SafeAuthorization `json:"authorization,omitempty"`
 	// HonorLabels chooses the metric's labels on collisions with target labels.
@@ -1298,12 +3567,155 @@ type Endpoint struct {
 	// The original scrape job's name is available via the `__tmp_prometheus_job_name` label.
 	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
 	RelabelConfigs []*RelabelConfig `json:"relabelings,omitempty"`
-	// ProxyURL eg http://proxyserver:2195 Directs scrapes to proxy through this endpoint.
-	ProxyURL *string `json:"proxyUrl,omitempty"`
+	// Optional proxy configuration. The proxy URL directs scrapes through
+	// the given endpoint, e.g. http://proxyserver:2195.
+	ProxyConfig `json:",inline"`
 	// FollowRedirects configures whether scrape requests follow HTTP 3xx redirects.
 	FollowRedirects *bool `json:"followRedirects,omitempty"`
 	// Whether to enable HTTP2.
 	EnableHttp2 *bool `json:"enableHttp2,omitempty"`
+	// ScrapeProtocols defines the protocols to negotiate during a scrape, in
+	// order of preference. It overrides the value defined at the Prometheus
+	// level.
+	// It requires Prometheus >= 2.49.0.
+	// +listType=set
+	// +optional
+	ScrapeProtocols []ScrapeProtocol `json:"scrapeProtocols,omitempty"`
+	// ScrapeFallbackProtocol defines the protocol to use when the target
+	// returns a missing, blank or unrecognized Content-Type. It overrides the
+	// value defined at the Prometheus level.
+	// It requires Prometheus >= 3.0.0.
+	// +optional
+	ScrapeFallbackProtocol *ScrapeProtocol `json:"scrapeFallbackProtocol,omitempty"`
+	// Whether to convert the scraped classic histograms into native
+	// histograms with custom buckets. It overrides the value defined at the
+	// Prometheus level.
+	// It requires Prometheus >= 3.0.0.
+	// +optional
+	ConvertClassicHistogramsToNHCB *bool `json:"convertClassicHistogramsToNHCB,omitempty"`
+}
+
+// Validate semantically validates the given Endpoint.
+func (e *Endpoint) Validate() error {
+	hasTargetPort := e.TargetPort != nil && PortAsString(*e.TargetPort) != ""
+
+	if e.Port != "" && hasTargetPort {
+		return &EndpointValidationError{"port and targetPort are mutually exclusive"}
+	}
+
+	if hasTargetPort {
+		if err := ValidatePort(*e.TargetPort); err != nil {
+			return &EndpointValidationError{fmt.Sprintf("targetPort: %v", err)}
+		}
+	}
+
+	if e.Port == "" && !hasTargetPort && !e.relabelsAddress() {
+		return &EndpointValidationError{"one of port or targetPort must be set unless relabelings define __address__"}
+	}
+
+	switch e.Scheme {
+	case "", "http", "https":
+	default:
+		return &EndpointValidationError{fmt.Sprintf("invalid scheme %q, expected one of \"http\" or \"https\"", e.Scheme)}
+	}
+
+	if e.Interval != "" {
+		if _, err := e.Interval.Parse(); err != nil {
+			return &EndpointValidationError{fmt.Sprintf("interval: %v", err)}
+		}
+	}
+
+	if e.ScrapeTimeout != "" {
+		if _, err := e.ScrapeTimeout.Parse(); err != nil {
+			return &EndpointValidationError{fmt.Sprintf("scrapeTimeout: %v", err)}
+		}
+	}
+
+	if e.Interval != "" && e.ScrapeTimeout != "" {
+		if shorter, _ := e.Interval.LessThan(e.ScrapeTimeout); shorter {
+			return &EndpointValidationError{fmt.Sprintf("scrapeTimeout %q greater than interval %q", e.ScrapeTimeout, e.Interval)}
+		}
+	}
+
+	return nil
+}
+
+// relabelsAddress returns true if one of the relabel configs writes the
+// __address__ label.
+func (e *Endpoint) relabelsAddress() bool {
+	for _, rc := range e.RelabelConfigs {
+		if rc == nil || rc.TargetLabel != "__address__" {
+			continue
+		}
+
+		switch strings.ToLower(rc.Action) {
+		case "", "replace", "hashmod", "lowercase", "uppercase":
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResolveAuth returns the name of the authentication method used to scrape
+// the endpoint (e.g. `basicAuth`) or an empty string if none is set.
+// It returns an *AuthConflictError if more than one method is set.
+func (e *Endpoint) ResolveAuth() (string, error) {
+	return resolveAuth([]authMethod{
+		{"bearerTokenFile", e.BearerTokenFile != ""},
+		{"bearerTokenSecret", e.BearerTokenSecret.Name != ""},
+		{"bearerTokenProjected", e.BearerTokenProjected != nil},
+		{"basicAuth", e.BasicAuth != nil},
+		{"oauth2", e.OAuth2 != nil},
+		{"authorization", e.Authorization != nil},
+	})
+}
+
+type authMethod struct {
+	name  string
+	isSet bool
+}
+
+// resolveAuth returns the name of the only authentication method which is
+// set, an empty string if none is set and an error if more than one is set.
+func resolveAuth(methods []authMethod) (string, error) {
+	var set []string
+	for _, m := range methods {
+		if m.isSet {
+			set = append(set, m.name)
+		}
+	}
+
+	switch len(set) {
+	case 0:
+		return "", nil
+	case 1:
+		return set[0], nil
+	}
+
+	return "", &AuthConflictError{fmt.Sprintf("%s can't be set at the same time, at most one authentication method must be defined", strings.Join(set, ", "))}
+}
+
+// AuthConflictError is returned by the ResolveAuth() methods when more than
+// one authentication method is set.
+// +k8s:openapi-gen=false
+type AuthConflictError struct {
+	err string
+}
+
+func (e *AuthConflictError) Error() string {
+	return e.err
+}
+
+// EndpointValidationError is returned by Endpoint.Validate() on semantically
+// invalid configurations.
+// +k8s:openapi-gen=false
+type EndpointValidationError struct {
+	err string
+}
+
+func (e *EndpointValidationError) Error() string {
+	return e.err
 }
 
 // +genclient
@@ -1323,6 +3735,10 @@ type PodMonitor struct {
 type PodMonitorSpec struct {
 	// The label to use to retrieve the job name from.
 	JobLabel string `json:"jobLabel,omitempty"`
+	// The scrape class to apply. When not set, the default scrape class of
+	// the Prometheus object (if any) is used.
+	// +optional
+	ScrapeClassName *string `json:"scrapeClassName,omitempty"`
 	// PodTargetLabels transfers labels on the Kubernetes Pod onto the target.
 	PodTargetLabels []string `json:"podTargetLabels,omitempty"`
 	// A list of endpoints allowed as part of this PodMonitor.
@@ -1374,6 +3790,12 @@ type PodMetricsEndpoint struct {
 	// Timeout after which the scrape is ended
 	// If not specified, the Prometheus global scrape interval is used.
 	ScrapeTimeout Duration `json:"scrapeTimeout,omitempty"`
+	// BodySizeLimit defines the maximum size of uncompressed response body
+	// that will be accepted by Prometheus for this target. Example: 100MB.
+	// If EnforcedBodySizeLimit is set on the Prometheus object, the smaller
+	// of the two values applies.
+	// Only valid in Prometheus versions 2.28.0 and newer.
+	BodySizeLimit *ByteSize `json:"bodySizeLimit,omitempty"`
 	// TLS configuration to use when scraping the endpoint.
 	TLSConfig *PodMetricsEndpointTLSConfig `json:"tlsConfig,omitempty"`
 	// Secret to mount to read bearer token for scraping targets. The secret
@@ -1398,17 +3820,49 @@ type PodMetricsEndpoint struct {
 	// The original scrape job's name is available via the `__tmp_prometheus_job_name` label.
 	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
 	RelabelConfigs []*RelabelConfig `json:"relabelings,omitempty"`
-	// ProxyURL eg http://proxyserver:2195 Directs scrapes to proxy through this endpoint.
-	ProxyURL *string `json:"proxyUrl,omitempty"`
+	// Optional proxy configuration. The proxy URL directs scrapes through
+	// the given endpoint, e.g. http://proxyserver:2195.
+	ProxyConfig `json:",inline"`
 	// FollowRedirects configures whether scrape requests follow HTTP 3xx redirects.
 	FollowRedirects *bool `json:"followRedirects,omitempty"`
 	// Whether to enable HTTP2.
 	EnableHttp2 *bool `json:"enableHttp2,omitempty"`
+	// ScrapeProtocols defines the protocols to negotiate during a scrape, in
+	// order of preference. It overrides the value defined at the Prometheus
+	// level.
+	// It requires Prometheus >= 2.49.0.
+	// +listType=set
+	// +optional
+	ScrapeProtocols []ScrapeProtocol `json:"scrapeProtocols,omitempty"`
+	// ScrapeFallbackProtocol defines the protocol to use when the target
+	// returns a missing, blank or unrecognized Content-Type. It overrides the
+	// value defined at the Prometheus level.
+	// It requires Prometheus >= 3.0.0.
+	// +optional
+	ScrapeFallbackProtocol *ScrapeProtocol `json:"scrapeFallbackProtocol,omitempty"`
+	// Whether to convert the scraped classic histograms into native
+	// histograms with custom buckets. It overrides the value defined at the
+	// Prometheus level.
+	// It requires Prometheus >= 3.0.0.
+	// +optional
+	ConvertClassicHistogramsToNHCB *bool `json:"convertClassicHistogramsToNHCB,omitempty"`
 	// Drop pods that are not running. (Failed, Succeeded). Enabled by default.
 	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle/#pod-phase
 	FilterRunning *bool `json:"filterRunning,omitempty"`
 }
 
+// ResolveAuth returns the name of the authentication method used to scrape
+// the endpoint (e.g. `basicAuth`) or an empty string if none is set.
+// It returns an *AuthConflictError if more than one method is set.
+func (e *PodMetricsEndpoint) ResolveAuth() (string, error) {
+	return resolveAuth([]authMethod{
+		{"bearerTokenSecret", e.BearerTokenSecret.Name != ""},
+		{"basicAuth", e.BasicAuth != nil},
+		{"oauth2", e.OAuth2 != nil},
+		{"authorization", e.Authorization != nil},
+	})
+}
+
 // PodMetricsEndpointTLSConfig specifies TLS configuration parameters.
 // +k8s:openapi-gen=true
 type PodMetricsEndpointTLSConfig struct {
@@ -1439,6 +3893,10 @@ type ProbeSpec struct {
 	// Example module configuring in the blackbox exporter:
 	// https://github.com/prometheus/blackbox_exporter/blob/master/example.yml
 	Module string `json:"module,omitempty"`
+	// The scrape class to apply. When not set, the default scrape class of
+	// the Prometheus object (if any) is used.
+	// +optional
+	ScrapeClassName *string `json:"scrapeClassName,omitempty"`
 	// Targets defines a set of static or dynamically discovered targets to probe.
 	Targets ProbeTargets `json:"targets,omitempty"`
 	// Interval at which targets are probed using the configured prober.
@@ -1475,6 +3933,24 @@ type ProbeSpec struct {
 	// Per-scrape limit on length of labels value that will be accepted for a sample.
 	// Only valid in Prometheus versions 2.27.0 and newer.
 	LabelValueLengthLimit uint64 `json:"labelValueLengthLimit,omitempty"`
+	// BodySizeLimit defines the maximum size of uncompressed response body
+	// that will be accepted by Prometheus for the probed targets. Example: 100MB.
+	// If EnforcedBodySizeLimit is set on the Prometheus object, the smaller
+	// of the two values applies.
+	// Only valid in Prometheus versions 2.28.0 and newer.
+	BodySizeLimit *ByteSize `json:"bodySizeLimit,omitempty"`
+}
+
+// ResolveAuth returns the name of the authentication method used to scrape
+// the probed targets (e.g. `basicAuth`) or an empty string if none is set.
+// It returns an *AuthConflictError if more than one method is set.
+func (s *ProbeSpec) ResolveAuth() (string, error) {
+	return resolveAuth([]authMethod{
+		{"bearerTokenSecret", s.BearerTokenSecret.Name != ""},
+		{"basicAuth", s.BasicAuth != nil},
+		{"oauth2", s.OAuth2 != nil},
+		{"authorization", s.Authorization != nil},
+	})
 }
 
 // ProbeTargets defines how to discover the probed targets.
@@ -1494,11 +3970,34 @@ type ProbeTargets struct {
 }
 
 // Validate semantically validates the given ProbeTargets.
+// When both `staticConfig` and `ingress` are defined, it returns a
+// *ValidationWarning since `ingress` is ignored.
 func (it *ProbeTargets) Validate() error {
 	if it.StaticConfig == nil && it.Ingress == nil {
 		return &ProbeTargetsValidationError{"at least one of .spec.targets.staticConfig and .spec.targets.ingress is required"}
 	}
 
+	if err := it.StaticConfig.Validate(); err != nil {
+		return err
+	}
+
+	var warnings []string
+	if err := it.Ingress.Validate(); err != nil {
+		var w *ValidationWarning
+		if !errors.As(err, &w) {
+			return err
+		}
+		warnings = append(warnings, w.Warnings()...)
+	}
+
+	if it.StaticConfig != nil && it.Ingress != nil {
+		warnings = append(warnings, ".spec.targets.staticConfig and .spec.targets.ingress are both defined, .spec.targets.ingress is ignored")
+	}
+
+	if len(warnings) > 0 {
+		return NewValidationWarning(warnings...)
+	}
+
 	return nil
 }
 
@@ -1526,22 +4025,147 @@ type ProbeTargetStaticConfig struct {
 	RelabelConfigs []*RelabelConfig `json:"relabelingConfigs,omitempty"`
 }
 
-// ProbeTargetIngress defines the set of Ingress objects considered for probing.
-// The operator configures a target for each host/path combination of each ingress object.
-// +k8s:openapi-gen=true
-type ProbeTargetIngress struct {
-	// Selector to select the Ingress objects.
-	Selector metav1.LabelSelector `json:"selector,omitempty"`
-	// From which namespaces to select Ingress objects.
-	NamespaceSelector NamespaceSelector `json:"namespaceSelector,omitempty"`
-	// RelabelConfigs to apply to the label set of the target before it gets
-	// scraped.
-	// The original ingress address is available via the
-	// `__tmp_prometheus_ingress_address` label. It can be used to customize the
-	// probed URL.
-	// The original scrape job's name is available via the `__tmp_prometheus_job_name` label.
-	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
-	RelabelConfigs []*RelabelConfig `json:"relabelingConfigs,omitempty"`
+// Validate semantically validates the given ProbeTargetStaticConfig.
+// It returns a *ProbeTargetsValidationError listing all the problems found.
+func (sc *ProbeTargetStaticConfig) Validate() error {
+	if sc == nil {
+		return nil
+	}
+
+	var errs []string
+	if len(sc.Targets) == 0 {
+		errs = append(errs, ".spec.targets.staticConfig.static must contain at least one target")
+	}
+
+	for i, t := range sc.Targets {
+		if strings.TrimSpace(t) == "" {
+			errs = append(errs, fmt.Sprintf(".spec.targets.staticConfig.static[%d] must not be empty", i))
+			continue
+		}
+
+		if strings.ContainsAny(t, " \t\n") {
+			errs = append(errs, fmt.Sprintf(".spec.targets.staticConfig.static[%d] %q must not contain whitespaces", i, t))
+		}
+	}
+
+	keys := make([]string, 0, len(sc.Labels))
+	for k := range sc.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !labelNameRe.MatchString(k) {
+			errs = append(errs, fmt.Sprintf(".spec.targets.staticConfig.labels: invalid label name %q", k))
+		}
+	}
+
+	for i, rc := range sc.RelabelConfigs {
+		if rc == nil {
+			errs = append(errs, fmt.Sprintf(".spec.targets.staticConfig.relabelingConfigs[%d] must not be null", i))
+			continue
+		}
+
+		if err := rc.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf(".spec.targets.staticConfig.relabelingConfigs[%d]: %v", i, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ProbeTargetsValidationError{strings.Join(errs, "; ")}
+	}
+
+	return nil
+}
+
+// ProbeTargetIngress defines the set of Ingress objects considered for probing.
+// The operator configures a target for each host/path combination of each ingress object.
+// +k8s:openapi-gen=true
+type ProbeTargetIngress struct {
+	// Selector to select the Ingress objects.
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+	// From which namespaces to select Ingress objects.
+	NamespaceSelector NamespaceSelector `json:"namespaceSelector,omitempty"`
+	// RelabelConfigs to apply to the label set of the target before it gets
+	// scraped.
+	// The original ingress address is available via the
+	// `__tmp_ingress_address` label. It can be used to customize the
+	// probed URL.
+	// The original scrape job's name is available via the `__tmp_prometheus_job_name` label.
+	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
+	RelabelConfigs []*RelabelConfig `json:"relabelingConfigs,omitempty"`
+}
+
+// Validate semantically validates the given ProbeTargetIngress.
+func (i *ProbeTargetIngress) Validate() error {
+	if i == nil {
+		return nil
+	}
+
+	for n, rc := range i.RelabelConfigs {
+		if rc == nil {
+			return &ProbeTargetsValidationError{fmt.Sprintf(".spec.targets.ingress.relabelingConfigs[%d] must not be null", n)}
+		}
+
+		if err := rc.Validate(); err != nil {
+			return &ProbeTargetsValidationError{fmt.Sprintf(".spec.targets.ingress.relabelingConfigs[%d]: %v", n, err)}
+		}
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(&i.Selector); err != nil {
+		return &ProbeTargetsValidationError{fmt.Sprintf("invalid .spec.targets.ingress.selector: %v", err)}
+	}
+
+	if err := i.NamespaceSelector.Validate(); err != nil {
+		if IsValidationWarning(err) {
+			return NewValidationWarning(fmt.Sprintf(".spec.targets.ingress.namespaceSelector: %v", err))
+		}
+		return &ProbeTargetsValidationError{fmt.Sprintf(".spec.targets.ingress.namespaceSelector: %v", err)}
+	}
+
+	return nil
+}
+
+// DefaultRelabelings returns the relabeling configurations applied by the
+// operator to the targets discovered from the Ingress objects. They are
+// applied before the user-defined relabeling configurations which means that
+// the latter can refer to the labels set here:
+// * `__param_target` holds the probed URL built from the ingress scheme,
+// address and path.
+// * `namespace` and `ingress` identify the Ingress object.
+// * `__tmp_ingress_address` holds the original ingress address.
+// * `instance` is set to the probed URL.
+func (i *ProbeTargetIngress) DefaultRelabelings() []*RelabelConfig {
+	return []*RelabelConfig{
+		{
+			SourceLabels: []LabelName{"__meta_kubernetes_ingress_scheme", "__address__", "__meta_kubernetes_ingress_path"},
+			Separator:    ";",
+			Regex:        "(.+);(.+);(.+)",
+			TargetLabel:  "__param_target",
+			Replacement:  "${1}://${2}${3}",
+			Action:       "replace",
+		},
+		{
+			SourceLabels: []LabelName{"__meta_kubernetes_namespace"},
+			TargetLabel:  "namespace",
+		},
+		{
+			SourceLabels: []LabelName{"__meta_kubernetes_ingress_name"},
+			TargetLabel:  "ingress",
+		},
+		{
+			SourceLabels: []LabelName{"__address__"},
+			Separator:    ";",
+			Regex:        "(.*)",
+			TargetLabel:  "__tmp_ingress_address",
+			Replacement:  "$1",
+			Action:       "replace",
+		},
+		{
+			SourceLabels: []LabelName{"__param_target"},
+			TargetLabel:  "instance",
+		},
+	}
 }
 
 // ProberSpec contains specification parameters for the Prober used for probing.
@@ -1551,6 +4175,7 @@ type ProberSpec struct {
 	URL string `json:"url"`
 	// HTTP scheme to use for scraping.
 	// Defaults to `http`.
+	// +kubebuilder:validation:Enum="";http;https
 	Scheme string `json:"scheme,omitempty"`
 	// Path to collect metrics from.
 	// Defaults to `/probe`.
@@ -1560,6 +4185,62 @@ type ProberSpec struct {
 	ProxyURL string `json:"proxyUrl,omitempty"`
 }
 
+// Validate semantically validates the given ProberSpec.
+func (p *ProberSpec) Validate() error {
+	if p.URL == "" {
+		return &ProberSpecValidationError{"url must be set"}
+	}
+
+	if strings.Contains(p.URL, "://") {
+		return &ProberSpecValidationError{fmt.Sprintf("url %q must not contain a scheme, use the scheme field instead", p.URL)}
+	}
+
+	scheme := p.Scheme
+	switch scheme {
+	case "":
+		scheme = "http"
+	case "http", "https":
+	default:
+		return &ProberSpecValidationError{fmt.Sprintf("invalid scheme %q, expected one of \"http\" or \"https\"", p.Scheme)}
+	}
+
+	u, err := url.Parse(scheme + "://" + p.URL)
+	if err != nil {
+		return &ProberSpecValidationError{fmt.Sprintf("invalid url %q: %v", p.URL, err)}
+	}
+
+	if u.Hostname() == "" || u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return &ProberSpecValidationError{fmt.Sprintf("url %q should be of the format `hostname` or `hostname:port`", p.URL)}
+	}
+
+	if strings.HasSuffix(u.Host, ":") {
+		return &ProberSpecValidationError{fmt.Sprintf("url %q has an empty port", p.URL)}
+	}
+
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return &ProberSpecValidationError{fmt.Sprintf("url %q has an invalid port %q", p.URL, port)}
+		}
+	}
+
+	if p.Path != "" && !strings.HasPrefix(p.Path, "/") {
+		return &ProberSpecValidationError{fmt.Sprintf("path %q must start with '/'", p.Path)}
+	}
+
+	return nil
+}
+
+// ProberSpecValidationError is returned by ProberSpec.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type ProberSpecValidationError struct {
+	err string
+}
+
+func (e *ProberSpecValidationError) Error() string {
+	return e.err
+}
+
 // OAuth2 allows an endpoint to authenticate with OAuth2.
 // More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#oauth2
 // +k8s:openapi-gen=true
@@ -1575,6 +4256,13 @@ type OAuth2 struct {
 	Scopes []string `json:"scopes,omitempty"`
 	// Parameters to append to the token URL
 	EndpointParams map[string]string `json:"endpointParams,omitempty"`
+	// Optional proxy configuration used to fetch the token.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	ProxyConfig `json:",inline"`
+	// TLS configuration used by the client to fetch the token.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +optional
+	TLSConfig *SafeTLSConfig `json:"tlsConfig,omitempty"`
 }
 
 type OAuth2ValidationError struct {
@@ -1600,9 +4288,109 @@ func (o *OAuth2) Validate() error {
 		}
 	}
 
+	if err := o.ProxyConfig.Validate(); err != nil {
+		return &OAuth2ValidationError{
+			err: fmt.Sprintf("invalid OAuth2 proxy configuration: %s", err.Error()),
+		}
+	}
+
+	if o.TLSConfig != nil {
+		if err := o.TLSConfig.Validate(); err != nil {
+			return &OAuth2ValidationError{
+				err: fmt.Sprintf("invalid OAuth2 TLS configuration: %s", err.Error()),
+			}
+		}
+	}
+
+	return nil
+}
+
+// ProxyConfig defines the HTTP proxy settings of a client.
+// +k8s:openapi-gen=true
+type ProxyConfig struct {
+	// URL of the proxy server, e.g. http://proxyserver:2195.
+	// +optional
+	ProxyURL *string `json:"proxyUrl,omitempty"`
+	// Comma-separated list of IP addresses, CIDR notations and domain names
+	// that should be excluded from proxying. IP addresses and domain names can
+	// contain port numbers. It requires proxyUrl to be set.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +optional
+	NoProxy *string `json:"noProxy,omitempty"`
+	// Whether to use the proxy configuration defined by the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables. It can't be set together
+	// with proxyUrl or noProxy.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +optional
+	ProxyFromEnvironment *bool `json:"proxyFromEnvironment,omitempty"`
+	// Headers to send to the proxy during CONNECT requests. The values are
+	// read from secrets in the namespace of the object.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +mapType:=atomic
+	// +optional
+	ProxyConnectHeader map[string][]v1.SecretKeySelector `json:"proxyConnectHeader,omitempty"`
+}
+
+// Validate semantically validates the given ProxyConfig.
+func (pc *ProxyConfig) Validate() error {
+	if pc == nil {
+		return nil
+	}
+
+	hasProxyURL := pc.ProxyURL != nil && *pc.ProxyURL != ""
+	fromEnvironment := pc.ProxyFromEnvironment != nil && *pc.ProxyFromEnvironment
+	hasNoProxy := pc.NoProxy != nil && *pc.NoProxy != ""
+
+	if hasProxyURL {
+		if _, err := url.Parse(*pc.ProxyURL); err != nil {
+			return &ProxyConfigValidationError{fmt.Sprintf("invalid proxyUrl %q: %v", *pc.ProxyURL, err)}
+		}
+	}
+
+	if fromEnvironment && hasProxyURL {
+		return &ProxyConfigValidationError{"proxyUrl can't be set when proxyFromEnvironment is true"}
+	}
+
+	if fromEnvironment && hasNoProxy {
+		return &ProxyConfigValidationError{"noProxy can't be set when proxyFromEnvironment is true"}
+	}
+
+	if hasNoProxy && !hasProxyURL {
+		return &ProxyConfigValidationError{"noProxy requires proxyUrl to be set"}
+	}
+
+	if len(pc.ProxyConnectHeader) > 0 && !hasProxyURL && !fromEnvironment {
+		return &ProxyConfigValidationError{"proxyConnectHeader requires proxyUrl or proxyFromEnvironment to be set"}
+	}
+
+	headers := make([]string, 0, len(pc.ProxyConnectHeader))
+	for k := range pc.ProxyConnectHeader {
+		headers = append(headers, k)
+	}
+	sort.Strings(headers)
+
+	for _, k := range headers {
+		for _, sel := range pc.ProxyConnectHeader[k] {
+			if sel.Name == "" || sel.Key == "" {
+				return &ProxyConfigValidationError{fmt.Sprintf("proxyConnectHeader %q must reference a secret name and key", k)}
+			}
+		}
+	}
+
 	return nil
 }
 
+// ProxyConfigValidationError is returned by ProxyConfig.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type ProxyConfigValidationError struct {
+	err string
+}
+
+func (e *ProxyConfigValidationError) Error() string {
+	return e.err
+}
+
 // BasicAuth allow an endpoint to authenticate over basic authentication
 // More info: https://prometheus.io/docs/operating/configuration/#endpoints
 // +k8s:openapi-gen=true
@@ -1615,6 +4403,34 @@ type BasicAuth struct {
 	Password v1.SecretKeySelector `json:"password,omitempty"`
 }
 
+// Validate semantically validates the given BasicAuth.
+func (ba *BasicAuth) Validate() error {
+	if ba == nil {
+		return nil
+	}
+
+	if ba.Username.Name == "" || ba.Username.Key == "" {
+		return &BasicAuthValidationError{"username must reference a secret name and key"}
+	}
+
+	if ba.Password.Name == "" || ba.Password.Key == "" {
+		return &BasicAuthValidationError{"password must reference a secret name and key"}
+	}
+
+	return nil
+}
+
+// BasicAuthValidationError is returned by BasicAuth.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type BasicAuthValidationError struct {
+	err string
+}
+
+func (e *BasicAuthValidationError) Error() string {
+	return e.err
+}
+
 // SecretOrConfigMap allows to specify data as a Secret or ConfigMap. Fields are mutually exclusive.
 type SecretOrConfigMap struct {
 	// Secret containing data to use for the targets.
@@ -1656,8 +4472,27 @@ type SafeTLSConfig struct {
 	ServerName string `json:"serverName,omitempty"`
 	// Disable target certificate validation.
 	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// Minimum acceptable TLS version.
+	// Only valid in Prometheus versions 2.35.0 and newer.
+	// +optional
+	MinVersion *TLSVersion `json:"minVersion,omitempty"`
+	// Maximum acceptable TLS version.
+	// Only valid in Prometheus versions 2.41.0 and newer.
+	// +optional
+	MaxVersion *TLSVersion `json:"maxVersion,omitempty"`
 }
 
+// TLSVersion is a TLS protocol version.
+// +kubebuilder:validation:Enum=TLS10;TLS11;TLS12;TLS13
+type TLSVersion string
+
+const (
+	TLSVersion10 TLSVersion = "TLS10"
+	TLSVersion11 TLSVersion = "TLS11"
+	TLSVersion12 TLSVersion = "TLS12"
+	TLSVersion13 TLSVersion = "TLS13"
+)
+
 // Validate semantically validates the given SafeTLSConfig.
 func (c *SafeTLSConfig) Validate() error {
 	if c.CA != (SecretOrConfigMap{}) {
@@ -1680,6 +4515,33 @@ func (c *SafeTLSConfig) Validate() error {
 		return &TLSConfigValidationError{"client key specified without client cert"}
 	}
 
+	return c.validateVersions()
+}
+
+// validateVersions checks that the TLS versions are known and that the
+// minimum version isn't greater than the maximum version.
+func (c *SafeTLSConfig) validateVersions() error {
+	var minVersion, maxVersion uint16
+	if c.MinVersion != nil {
+		v, found := webTLSVersions[string(*c.MinVersion)]
+		if !found {
+			return &TLSConfigValidationError{fmt.Sprintf("unknown minVersion %q", *c.MinVersion)}
+		}
+		minVersion = v
+	}
+
+	if c.MaxVersion != nil {
+		v, found := webTLSVersions[string(*c.MaxVersion)]
+		if !found {
+			return &TLSConfigValidationError{fmt.Sprintf("unknown maxVersion %q", *c.MaxVersion)}
+		}
+		maxVersion = v
+	}
+
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return &TLSConfigValidationError{fmt.Sprintf("minVersion %q is greater than maxVersion %q", *c.MinVersion, *c.MaxVersion)}
+	}
+
 	return nil
 }
 
@@ -1741,7 +4603,7 @@ func (c *TLSConfig) Validate() error {
 		return &TLSConfigValidationError{"tls config can not specify client key without client cert"}
 	}
 
-	return nil
+	return c.validateVersions()
 }
 
 // ServiceMonitorList is a list of ServiceMonitors.
@@ -1766,47 +4628,339 @@ type PodMonitorList struct {
 	Items []*PodMonitor `json:"items"`
 }
 
-// ProbeList is a list of Probes.
-// +k8s:openapi-gen=true
-type ProbeList struct {
-	metav1.TypeMeta `json:",inline"`
-	// Standard list metadata
-	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
-	metav1.ListMeta `json:"metadata,omitempty"`
-	// List of Probes
-	Items []*Probe `json:"items"`
+// ProbeList is a list of Probes.
+// +k8s:openapi-gen=true
+type ProbeList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of Probes
+	Items []*Probe `json:"items"`
+}
+
+// PrometheusRuleList is a list of PrometheusRules.
+// +k8s:openapi-gen=true
+type PrometheusRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of Rules
+	Items []*PrometheusRule `json:"items"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories="prometheus-operator",shortName="promrule"
+
+// PrometheusRule defines recording and alerting rules for a Prometheus instance
+type PrometheusRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of desired alerting rule definitions for Prometheus.
+	Spec PrometheusRuleSpec `json:"spec"`
+}
+
+// PrometheusRuleSpec contains specification parameters for a Rule.
+// +k8s:openapi-gen=true
+type PrometheusRuleSpec struct {
+	// Content of Prometheus rule file
+	// +listType=map
+	// +listMapKey=name
+	Groups []RuleGroup `json:"groups,omitempty"`
+	// Unit tests of the rules in the `promtool test rules` format.
+	// The tests aren't written to the rule files loaded by Prometheus.
+	// +optional
+	Tests []RuleTestGroup `json:"tests,omitempty"`
+}
+
+// RuleTestGroup is a group of unit tests sharing the same input series, as
+// defined by `promtool test rules`.
+// See https://prometheus.io/docs/prometheus/latest/configuration/unit_testing_rules/
+// +k8s:openapi-gen=true
+type RuleTestGroup struct {
+	// Name of the test group.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Interval between the samples of the input series. Defaults to 1m.
+	// +optional
+	Interval *Duration `json:"interval,omitempty"`
+	// Series data used as input for the tests.
+	// +optional
+	InputSeries []RuleTestSeries `json:"input_series,omitempty"`
+	// Unit tests of the alerting rules.
+	// +optional
+	AlertRuleTests []AlertRuleTestCase `json:"alert_rule_test,omitempty"`
+	// Unit tests of PromQL expressions.
+	// +optional
+	PromQLExprTests []PromQLExprTestCase `json:"promql_expr_test,omitempty"`
+	// External labels accessible to the alert templates.
+	// +optional
+	ExternalLabels map[string]string `json:"external_labels,omitempty"`
+	// External URL accessible to the alert templates.
+	// +optional
+	ExternalURL string `json:"external_url,omitempty"`
+}
+
+// RuleTestSeries defines an input series of a rule unit test.
+// +k8s:openapi-gen=true
+type RuleTestSeries struct {
+	// Series in the usual series notation, e.g. `up{job="node"}`.
+	// +kubebuilder:validation:MinLength=1
+	Series string `json:"series"`
+	// Values of the series using the expanding notation, e.g. `1+1x10`.
+	// +kubebuilder:validation:MinLength=1
+	Values string `json:"values"`
+}
+
+// AlertRuleTestCase checks the alerts firing at a given time.
+// +k8s:openapi-gen=true
+type AlertRuleTestCase struct {
+	// Time elapsed since the beginning of the test at which the alerts are
+	// checked.
+	EvalTime Duration `json:"eval_time"`
+	// Name of the alert to check.
+	// +kubebuilder:validation:MinLength=1
+	AlertName string `json:"alertname"`
+	// Alerts expected to fire at the given time. An empty list means that
+	// no alert is expected.
+	// +optional
+	ExpAlerts []ExpectedAlert `json:"exp_alerts,omitempty"`
+}
+
+// ExpectedAlert defines an alert expected by a rule unit test.
+// +k8s:openapi-gen=true
+type ExpectedAlert struct {
+	// Expected labels of the alert, including the labels of the series.
+	// +optional
+	ExpLabels map[string]string `json:"exp_labels,omitempty"`
+	// Expected annotations of the alert.
+	// +optional
+	ExpAnnotations map[string]string `json:"exp_annotations,omitempty"`
+}
+
+// PromQLExprTestCase checks the result of a PromQL expression at a given time.
+// +k8s:openapi-gen=true
+type PromQLExprTestCase struct {
+	// PromQL expression to evaluate.
+	// +kubebuilder:validation:MinLength=1
+	Expr string `json:"expr"`
+	// Time elapsed since the beginning of the test at which the expression
+	// is evaluated.
+	EvalTime Duration `json:"eval_time"`
+	// Samples expected to be returned by the expression.
+	// +optional
+	ExpSamples []ExpectedSample `json:"exp_samples,omitempty"`
+}
+
+// ExpectedSample defines a sample expected by a PromQL unit test.
+// +k8s:openapi-gen=true
+type ExpectedSample struct {
+	// Labels of the sample in the usual series notation, e.g. `up{job="node"}`.
+	// +optional
+	Labels string `json:"labels,omitempty"`
+	// Expected value of the sample as a floating-point number, e.g. `0.5`.
+	Value string `json:"value"`
+}
+
+// Validate returns the list of structural errors of the test group.
+// The series notation and the PromQL expressions aren't parsed.
+func (tg *RuleTestGroup) Validate() field.ErrorList {
+	return tg.validate(nil)
+}
+
+func (tg *RuleTestGroup) validate(fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if tg.Interval != nil {
+		if err := tg.Interval.Validate(); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("interval"), *tg.Interval, err.Error()))
+		}
+	}
+
+	for i, s := range tg.InputSeries {
+		p := fldPath.Child("input_series").Index(i)
+		if strings.TrimSpace(s.Series) == "" {
+			errs = append(errs, field.Required(p.Child("series"), "series must be set"))
+		}
+		if strings.TrimSpace(s.Values) == "" {
+			errs = append(errs, field.Required(p.Child("values"), "values must be set"))
+		}
+	}
+
+	for i, tc := range tg.AlertRuleTests {
+		p := fldPath.Child("alert_rule_test").Index(i)
+		if err := tc.EvalTime.Validate(); err != nil {
+			errs = append(errs, field.Invalid(p.Child("eval_time"), tc.EvalTime, err.Error()))
+		}
+		if tc.AlertName == "" {
+			errs = append(errs, field.Required(p.Child("alertname"), "alert name must be set"))
+		}
+		for j, a := range tc.ExpAlerts {
+			errs = append(errs, validateLabelNames(p.Child("exp_alerts").Index(j).Child("exp_labels"), a.ExpLabels)...)
+		}
+	}
+
+	for i, tc := range tg.PromQLExprTests {
+		p := fldPath.Child("promql_expr_test").Index(i)
+		if strings.TrimSpace(tc.Expr) == "" {
+			errs = append(errs, field.Required(p.Child("expr"), "expression must be set"))
+		}
+		if err := tc.EvalTime.Validate(); err != nil {
+			errs = append(errs, field.Invalid(p.Child("eval_time"), tc.EvalTime, err.Error()))
+		}
+		for j, sample := range tc.ExpSamples {
+			if _, err := strconv.ParseFloat(sample.Value, 64); err != nil {
+				errs = append(errs, field.Invalid(p.Child("exp_samples").Index(j).Child("value"), sample.Value, "must be a floating-point number"))
+			}
+		}
+	}
+
+	errs = append(errs, validateLabelNames(fldPath.Child("external_labels"), tg.ExternalLabels)...)
+
+	return errs
+}
+
+// Validate semantically validates the given PrometheusRuleSpec. It checks
+// that the group names are unique and that the rules and the unit tests are
+// valid.
+// It returns a *PrometheusRuleValidationError listing all the offending
+// groups, rules and tests.
+func (spec *PrometheusRuleSpec) Validate() error {
+	var (
+		errs   field.ErrorList
+		groups = make(map[string]struct{}, len(spec.Groups))
+	)
+
+	for i, g := range spec.Groups {
+		fldPath := field.NewPath("groups").Index(i)
+
+		if g.Name == "" {
+			errs = append(errs, field.Required(fldPath.Child("name"), "group name must be set"))
+		} else if _, found := groups[g.Name]; found {
+			errs = append(errs, field.Duplicate(fldPath.Child("name"), g.Name))
+		}
+		groups[g.Name] = struct{}{}
+
+		if g.Interval != "" {
+			if err := g.Interval.Validate(); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Child("interval"), g.Interval, err.Error()))
+			}
+		}
+
+		if g.Limit != nil && *g.Limit < 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("limit"), *g.Limit, "must be greater than or equal to 0"))
+		}
+
+		if g.QueryOffset != nil {
+			if err := g.QueryOffset.Validate(); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Child("query_offset"), *g.QueryOffset, err.Error()))
+			}
+		}
+
+		for j := range g.Rules {
+			errs = append(errs, g.Rules[j].validate(fldPath.Child("rules").Index(j))...)
+		}
+	}
+
+	for i := range spec.Tests {
+		errs = append(errs, spec.Tests[i].validate(field.NewPath("tests").Index(i))...)
+	}
+
+	if len(errs) > 0 {
+		return &PrometheusRuleValidationError{errs: errs}
+	}
+
+	return nil
+}
+
+// DuplicateAlertNames returns the sorted list of alert names which are
+// defined in more than one rule group. It is legal for Prometheus but often
+// a mistake leading to confusing notifications. Alerts sharing the same name
+// within a group (e.g. with different severities) aren't reported.
+func (spec *PrometheusRuleSpec) DuplicateAlertNames() []string {
+	var (
+		groupsByAlert = map[string]map[int]struct{}{}
+		duplicates    []string
+	)
+
+	for i, g := range spec.Groups {
+		for _, r := range g.Rules {
+			if !r.IsAlert() {
+				continue
+			}
+
+			if _, found := groupsByAlert[r.Alert]; !found {
+				groupsByAlert[r.Alert] = map[int]struct{}{}
+			}
+			// Use the index since group names may be duplicated in invalid specs.
+			groupsByAlert[r.Alert][i] = struct{}{}
+		}
+	}
+
+	for alert, groups := range groupsByAlert {
+		if len(groups) > 1 {
+			duplicates = append(duplicates, alert)
+		}
+	}
+	sort.Strings(duplicates)
+
+	return duplicates
 }
 
-// PrometheusRuleList is a list of PrometheusRules.
-// +k8s:openapi-gen=true
-type PrometheusRuleList struct {
-	metav1.TypeMeta `json:",inline"`
-	// Standard list metadata
-	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
-	metav1.ListMeta `json:"metadata,omitempty"`
-	// List of Rules
-	Items []*PrometheusRule `json:"items"`
+// ValidateIntervals checks the evaluation intervals of the rule groups
+// against the global evaluation and scrape intervals of Prometheus. Empty
+// global intervals default to `30s`.
+// It returns a *ValidationWarning for the groups evaluated at least twice
+// per scrape interval since the rules would run on the same data repeatedly.
+// The group intervals are expected to be valid (see Validate()).
+func (spec *PrometheusRuleSpec) ValidateIntervals(evaluationInterval, scrapeInterval Duration) error {
+	if scrapeInterval == "" {
+		scrapeInterval = "30s"
+	}
+
+	scrape, err := scrapeInterval.Parse()
+	if err != nil {
+		return fmt.Errorf("scrapeInterval: %w", err)
+	}
+
+	var warnings []string
+	for _, g := range spec.Groups {
+		interval := g.EffectiveInterval(evaluationInterval)
+
+		d, err := interval.Parse()
+		if err != nil {
+			return fmt.Errorf("group %q: interval: %w", g.Name, err)
+		}
+
+		if 2*d <= scrape {
+			warnings = append(warnings, fmt.Sprintf("group %q: interval %q is much smaller than the scrape interval %q", g.Name, interval, scrapeInterval))
+		}
+	}
+
+	if len(warnings) > 0 {
+		return NewValidationWarning(warnings...)
+	}
+
+	return nil
 }
 
-// +genclient
-// +k8s:openapi-gen=true
-// +kubebuilder:resource:categories="prometheus-operator",shortName="promrule"
+// PrometheusRuleValidationError is returned by PrometheusRuleSpec.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+// +k8s:deepcopy-gen=false
+type PrometheusRuleValidationError struct {
+	errs field.ErrorList
+}
 
-// PrometheusRule defines recording and alerting rules for a Prometheus instance
-type PrometheusRule struct {
-	metav1.TypeMeta   `json:",inline"`
-	metav1.ObjectMeta `json:"metadata,omitempty"`
-	// Specification of desired alerting rule definitions for Prometheus.
-	Spec PrometheusRuleSpec `json:"spec"`
+// Errors returns the list of validation errors.
+func (e *PrometheusRuleValidationError) Errors() field.ErrorList {
+	return e.errs
 }
 
-// PrometheusRuleSpec contains specification parameters for a Rule.
-// +k8s:openapi-gen=true
-type PrometheusRuleSpec struct {
-	// Content of Prometheus rule file
-	// +listType=map
-	// +listMapKey=name
-	Groups []RuleGroup `json:"groups,omitempty"`
+func (e *PrometheusRuleValidationError) Error() string {
+	return e.errs.ToAggregate().Error()
 }
 
 // RuleGroup and Rule are copied instead of vendored because the
@@ -1820,6 +4974,19 @@ type RuleGroup struct {
 	Name string `json:"name"`
 	// Interval determines how often rules in the group are evaluated.
 	Interval Duration `json:"interval,omitempty"`
+	// Limit is the maximum number of alerts an alerting rule and series a
+	// recording rule of the group can produce. 0 means no limit.
+	// It requires Prometheus >= 2.31.0 or Thanos >= 0.24.0 and is ignored for
+	// older versions.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Limit *int32 `json:"limit,omitempty"`
+	// QueryOffset defines the offset the rule evaluation timestamp of this
+	// group is shifted back by.
+	// It requires Prometheus >= 2.53.0 or Thanos >= 0.38.0 and is ignored for
+	// older versions.
+	// +optional
+	QueryOffset *Duration `json:"query_offset,omitempty"`
 	// List of alerting and recording rules.
 	Rules []Rule `json:"rules"`
 	// PartialResponseStrategy is only used by ThanosRuler and will
@@ -1830,6 +4997,21 @@ type RuleGroup struct {
 	PartialResponseStrategy string `json:"partial_response_strategy,omitempty"`
 }
 
+// EffectiveInterval returns the interval at which the rules of the group are
+// evaluated: the group's interval if defined, otherwise the given global
+// evaluation interval (`30s` if empty).
+func (g *RuleGroup) EffectiveInterval(global Duration) Duration {
+	if g.Interval != "" {
+		return g.Interval
+	}
+
+	if global == "" {
+		return "30s"
+	}
+
+	return global
+}
+
 // Rule describes an alerting or recording rule
 // See Prometheus documentation: [alerting](https://www.prometheus.io/docs/prometheus/latest/configuration/alerting_rules/) or [recording](https://www.prometheus.io/docs/prometheus/latest/configuration/recording_rules/#recording-rules) rule
 // +k8s:openapi-gen=true
@@ -1844,6 +5026,13 @@ type Rule struct {
 	Expr intstr.IntOrString `json:"expr"`
 	// Alerts are considered firing once they have been returned for this long.
 	For Duration `json:"for,omitempty"`
+	// KeepFiringFor defines how long an alert will continue firing after the
+	// condition that triggered it has cleared.
+	// Only valid for alerting rules.
+	// It requires Prometheus >= 2.42.0 or Thanos >= 0.34.0 and is ignored for
+	// older versions.
+	// +optional
+	KeepFiringFor *Duration `json:"keep_firing_for,omitempty"`
 	// Labels to add or overwrite.
 	Labels map[string]string `json:"labels,omitempty"`
 	// Annotations to add to each alert.
@@ -1851,6 +5040,126 @@ type Rule struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+var (
+	metricNameRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRe  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Expression returns the PromQL expression of the rule as a string,
+// regardless of whether it has been unmarshaled as an integer or a string.
+func (r *Rule) Expression() string {
+	if r.Expr.Type == intstr.Int {
+		return strconv.Itoa(int(r.Expr.IntVal))
+	}
+
+	return r.Expr.StrVal
+}
+
+// IsAlert returns true if the rule is an alerting rule.
+func (r *Rule) IsAlert() bool {
+	return r.Alert != ""
+}
+
+// IsRecording returns true if the rule is a recording rule.
+func (r *Rule) IsRecording() bool {
+	return r.Record != ""
+}
+
+// Validate returns the list of semantic errors of the rule.
+//
+// The PromQL expression is only checked for emptiness: parsing it requires
+// the Prometheus libraries which this module doesn't depend on to keep the
+// API types lightweight for client applications. The operator and the
+// admission webhook parse the expressions with the upstream Prometheus rule
+// parser (see ValidateRule() in pkg/prometheus).
+func (r *Rule) Validate() field.ErrorList {
+	return r.validate(nil)
+}
+
+func (r *Rule) validate(fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	switch {
+	case r.IsAlert() && r.IsRecording():
+		errs = append(errs, field.Invalid(fldPath.Child("record"), r.Record, "only one of 'record' and 'alert' must be set"))
+	case !r.IsAlert() && !r.IsRecording():
+		errs = append(errs, field.Required(fldPath.Child("record"), "one of 'record' or 'alert' must be set"))
+	}
+
+	if err := r.ValidateMetricName(); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Child("record"), r.Record, err.Error()))
+	}
+
+	if strings.TrimSpace(r.Expression()) == "" {
+		errs = append(errs, field.Required(fldPath.Child("expr"), "expression must be set"))
+	}
+
+	if r.For != "" {
+		if !r.IsAlert() {
+			errs = append(errs, field.Forbidden(fldPath.Child("for"), "only valid for alerting rules"))
+		} else if err := r.For.Validate(); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("for"), r.For, err.Error()))
+		}
+	}
+
+	if r.KeepFiringFor != nil {
+		if !r.IsAlert() {
+			errs = append(errs, field.Forbidden(fldPath.Child("keep_firing_for"), "only valid for alerting rules"))
+		} else if err := r.KeepFiringFor.Validate(); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("keep_firing_for"), *r.KeepFiringFor, err.Error()))
+		}
+	}
+
+	if !r.IsAlert() && len(r.Annotations) > 0 {
+		errs = append(errs, field.Forbidden(fldPath.Child("annotations"), "only valid for alerting rules"))
+	}
+
+	errs = append(errs, validateLabelNames(fldPath.Child("labels"), r.Labels)...)
+	errs = append(errs, validateLabelNames(fldPath.Child("annotations"), r.Annotations)...)
+
+	return errs
+}
+
+// ValidateMetricName checks that the name of a recording rule is a valid
+// metric name. Unlike label names, metric names may contain colons which are
+// reserved for recording rules (e.g. `job:http_requests:rate5m`).
+// It always returns nil for alerting rules.
+func (r *Rule) ValidateMetricName() error {
+	if !r.IsRecording() || metricNameRe.MatchString(r.Record) {
+		return nil
+	}
+
+	return &RuleValidationError{fmt.Sprintf("%q is not a valid metric name, it must match the regular expression %q", r.Record, metricNameRe.String())}
+}
+
+// RuleValidationError is returned by Rule.ValidateMetricName() on invalid
+// recording rule names.
+// +k8s:openapi-gen=false
+type RuleValidationError struct {
+	err string
+}
+
+func (e *RuleValidationError) Error() string {
+	return e.err
+}
+
+func validateLabelNames(fldPath *field.Path, m map[string]string) field.ErrorList {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs field.ErrorList
+	for _, k := range keys {
+		if !labelNameRe.MatchString(k) {
+			errs = append(errs, field.Invalid(fldPath.Key(k), k, "invalid label name"))
+		}
+	}
+
+	return errs
+}
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:categories="prometheus-operator",shortName="am"
@@ -1873,6 +5182,200 @@ type Alertmanager struct {
 	Status *AlertmanagerStatus `json:"status,omitempty"`
 }
 
+// SelectorLabels returns the labels used by the operator to select the pods
+// of the Alertmanager resource.
+func (a *Alertmanager) SelectorLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "alertmanager",
+		"app.kubernetes.io/managed-by": "prometheus-operator",
+		"app.kubernetes.io/instance":   a.Name,
+		"alertmanager":                 a.Name,
+	}
+}
+
+// ImageReferences returns the references of all the container images
+// deployed by the operator for the Alertmanager resource: Alertmanager and
+// the config reloader. Images of the additional containers defined in the
+// spec aren't included.
+func (a *Alertmanager) ImageReferences(defaults ImageDefaults) ([]string, error) {
+	image, err := buildImagePath(
+		stringPtrValue(a.Spec.Image),
+		stringValOrDefault(a.Spec.BaseImage, defaults.AlertmanagerBaseImage),
+		stringValOrDefault(a.Spec.Version, defaults.AlertmanagerVersion),
+		a.Spec.Tag,
+		a.Spec.SHA,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("alertmanager: %w", err)
+	}
+
+	images := []string{image}
+	if defaults.ConfigReloaderImage != "" {
+		images = append(images, defaults.ConfigReloaderImage)
+	}
+
+	return images, nil
+}
+
+// AlertmanagerPrefixedName returns the name of the resources (statefulset,
+// configuration secret, ...) managed by the operator for the Alertmanager
+// named `name`.
+func AlertmanagerPrefixedName(name string) string {
+	return "alertmanager-" + name
+}
+
+// EffectiveConfigSource returns where the Alertmanager configuration comes
+// from. When useCRD is true, the configuration is generated from the
+// AlertmanagerConfig resource referenced by `alertmanagerConfiguration` which
+// takes precedence over `configSecret`. Otherwise secretName is the name of
+// the Secret holding the configuration which defaults to
+// `alertmanager-<name>`.
+func (a *Alertmanager) EffectiveConfigSource() (useCRD bool, secretName string) {
+	if a.Spec.AlertmanagerConfiguration != nil {
+		return true, ""
+	}
+
+	if a.Spec.ConfigSecret != "" {
+		return false, a.Spec.ConfigSecret
+	}
+
+	return false, AlertmanagerPrefixedName(a.Name)
+}
+
+// ConfigSecretKey returns the key of the configuration secret which holds the
+// Alertmanager configuration. The other keys of the secret are mounted
+// alongside the configuration file.
+func (s *AlertmanagerSpec) ConfigSecretKey() string {
+	return "alertmanager.yaml"
+}
+
+// ValidateConfigSource checks the consistency of the configuration sources.
+// It returns a *ValidationWarning when both `configSecret` and
+// `alertmanagerConfiguration` are defined since `configSecret` is ignored
+// in this case.
+func (s *AlertmanagerSpec) ValidateConfigSource() error {
+	if s.ConfigSecret != "" && s.AlertmanagerConfiguration != nil {
+		return NewValidationWarning(fmt.Sprintf("configSecret %q is ignored because alertmanagerConfiguration is defined", s.ConfigSecret))
+	}
+
+	return nil
+}
+
+// WillFormCluster returns true when the Alertmanager pods run in cluster
+// mode, that is when there is more than one replica, when the cluster mode is
+// forced or when additional peers are defined.
+func (s *AlertmanagerSpec) WillFormCluster() bool {
+	if s.Replicas != nil && *s.Replicas > 1 {
+		return true
+	}
+
+	return s.ForceEnableClusterMode || len(s.AdditionalPeers) > 0
+}
+
+// Validate semantically validates the given AlertmanagerSpec.
+// It returns a *ValidationWarning when `clusterAdvertiseAddress` is a private
+// (RFC1918) address since such addresses usually don't need to be advertised.
+func (s *AlertmanagerSpec) Validate() error {
+	var warning error
+	if s.ClusterAdvertiseAddress != "" {
+		if err := validateHostPort(s.ClusterAdvertiseAddress); err != nil {
+			return &AlertmanagerSpecValidationError{fmt.Sprintf("invalid clusterAdvertiseAddress %q: %s", s.ClusterAdvertiseAddress, err)}
+		}
+
+		host, _, _ := net.SplitHostPort(s.ClusterAdvertiseAddress)
+		if ip := net.ParseIP(host); ip != nil && ip.To4() != nil && ip.IsPrivate() {
+			warning = NewValidationWarning(fmt.Sprintf("clusterAdvertiseAddress %q is a private (RFC1918) address which usually doesn't need to be advertised", s.ClusterAdvertiseAddress))
+		}
+	}
+
+	if err := validateAdditionalPeers(s.AdditionalPeers); err != nil {
+		return &AlertmanagerSpecValidationError{err.Error()}
+	}
+
+	if s.TerminationGracePeriodSeconds != nil && *s.TerminationGracePeriodSeconds < 0 {
+		return &AlertmanagerSpecValidationError{fmt.Sprintf("terminationGracePeriodSeconds %d must not be negative", *s.TerminationGracePeriodSeconds)}
+	}
+
+	if err := validateConfigReloaderWatchInterval(s.ConfigReloaderWatchInterval); err != nil {
+		return &AlertmanagerSpecValidationError{err.Error()}
+	}
+
+	return warning
+}
+
+// validateConfigReloaderWatchInterval checks that the watch interval is a
+// valid and strictly positive duration. A zero interval would make the config
+// reloader exit after the first run.
+func validateConfigReloaderWatchInterval(d *Duration) error {
+	if d == nil {
+		return nil
+	}
+
+	interval, err := d.Parse()
+	if err != nil {
+		return fmt.Errorf("invalid configReloaderWatchInterval %q: %w", *d, err)
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("invalid configReloaderWatchInterval %q: must be greater than 0", *d)
+	}
+
+	return nil
+}
+
+// AlertmanagerSpecValidationError is returned by AlertmanagerSpec.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type AlertmanagerSpecValidationError struct {
+	err string
+}
+
+func (e *AlertmanagerSpecValidationError) Error() string {
+	return e.err
+}
+
+// validateHostPort checks that the address is of the form `host:port`.
+func validateHostPort(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if host == "" {
+		return errors.New("missing host")
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+
+	return nil
+}
+
+// validateAdditionalPeers checks that every peer is of the form `host:port`
+// or `host`.
+func validateAdditionalPeers(peers []string) error {
+	for _, peer := range peers {
+		address := peer
+		if _, _, err := net.SplitHostPort(peer); err != nil {
+			// The peer may omit the port, in which case Alertmanager uses
+			// the default cluster port.
+			address = net.JoinHostPort(strings.Trim(peer, "[]"), "9094")
+		}
+
+		if err := validateHostPort(address); err != nil {
+			return fmt.Errorf("invalid additionalPeers entry %q: %s", peer, err)
+		}
+
+		host, _, _ := net.SplitHostPort(address)
+		if net.ParseIP(host) == nil && len(validation.IsDNS1123Subdomain(host)) > 0 {
+			return fmt.Errorf("invalid additionalPeers entry %q: invalid host %q", peer, host)
+		}
+	}
+
+	return nil
+}
+
 // AlertmanagerSpec is a specification of the desired behavior of the Alertmanager cluster. More info:
 // https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
 // +k8s:openapi-gen=true
@@ -1968,6 +5471,16 @@ type AlertmanagerSpec struct {
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	// Define resources requests and limits for single Pods.
 	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// Env defines the environment variables of the `alertmanager` container.
+	// Names must be valid C identifiers and `POD_IP` is reserved by the
+	// operator.
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+	// EnvFrom defines the sources of the environment variables of the
+	// `alertmanager` container. Each source must reference either a ConfigMap
+	// or a Secret.
+	// +optional
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
 	// If specified, the pod's scheduling constraints.
 	Affinity *v1.Affinity `json:"affinity,omitempty"`
 	// If specified, the pod's tolerations.
@@ -2003,8 +5516,10 @@ type AlertmanagerSpec struct {
 	// Priority class assigned to the Pods
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 	// AdditionalPeers allows injecting a set of additional Alertmanagers to peer with to form a highly available cluster.
+	// Each entry must be of the form `host:port` or `host`.
 	AdditionalPeers []string `json:"additionalPeers,omitempty"`
 	// ClusterAdvertiseAddress is the explicit address to advertise in cluster.
+	// It must be of the form `host:port`.
 	// Needs to be provided for non RFC1918 [1] (public) addresses.
 	// [1] RFC1918: https://tools.ietf.org/html/rfc1918
 	ClusterAdvertiseAddress string `json:"clusterAdvertiseAddress,omitempty"`
@@ -2014,11 +5529,20 @@ type AlertmanagerSpec struct {
 	ClusterPushpullInterval GoDuration `json:"clusterPushpullInterval,omitempty"`
 	// Timeout for cluster peering.
 	ClusterPeerTimeout GoDuration `json:"clusterPeerTimeout,omitempty"`
+	// Defines the identifier that uniquely identifies the Alertmanager cluster.
+	// Alertmanager drops the gossip messages from peers with a different
+	// label which prevents clusters from merging when they share peers by
+	// mistake.
+	// If unset, the `--cluster.label` flag isn't set.
+	// It requires Alertmanager >= 0.25.0 and is ignored for older versions.
+	// +optional
+	ClusterLabel *string `json:"clusterLabel,omitempty"`
 	// Port name used for the pods and governing service.
 	// This defaults to web
 	PortName string `json:"portName,omitempty"`
 	// ForceEnableClusterMode ensures Alertmanager does not deactivate the cluster mode when running with a single replica.
 	// Use case is e.g. spanning an Alertmanager cluster across Kubernetes clusters with a single replica in each.
+	// The cluster mode is also enabled when additionalPeers is not empty.
 	ForceEnableClusterMode bool `json:"forceEnableClusterMode,omitempty"`
 	// AlertmanagerConfigs to be selected for to merge and configure Alertmanager with.
 	AlertmanagerConfigSelector *metav1.LabelSelector `json:"alertmanagerConfigSelector,omitempty"`
@@ -2031,6 +5555,16 @@ type AlertmanagerSpec struct {
 	// This is an alpha field and requires enabling StatefulSetMinReadySeconds feature gate.
 	// +optional
 	MinReadySeconds *uint32 `json:"minReadySeconds,omitempty"`
+	// Optional duration in seconds the pod needs to terminate gracefully.
+	// Defaults to 120 seconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// ConfigReloaderWatchInterval defines how often the config reloader
+	// re-reads the configuration file and the watched directories.
+	// If unset, the config reloader uses its default value (3 minutes).
+	// +optional
+	ConfigReloaderWatchInterval *Duration `json:"configReloaderWatchInterval,omitempty"`
 	// Pods' hostAliases configuration
 	// +listType=map
 	// +listMapKey=ip
@@ -2059,6 +5593,40 @@ type AlertmanagerConfiguration struct {
 	Templates []SecretOrConfigMap `json:"templates,omitempty"`
 }
 
+// Validate semantically validates the given AlertmanagerConfiguration.
+func (c *AlertmanagerConfiguration) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if errs := validation.IsDNS1123Subdomain(c.Name); len(errs) > 0 {
+		return &AlertmanagerConfigurationValidationError{
+			err: fmt.Sprintf("invalid name %q: %s", c.Name, strings.Join(errs, ", ")),
+		}
+	}
+
+	for i, t := range c.Templates {
+		if err := t.Validate(); err != nil {
+			return &AlertmanagerConfigurationValidationError{
+				err: fmt.Sprintf("invalid templates[%d]: %s", i, err),
+			}
+		}
+	}
+
+	return nil
+}
+
+// AlertmanagerConfigurationValidationError is returned by
+// AlertmanagerConfiguration.Validate() on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type AlertmanagerConfigurationValidationError struct {
+	err string
+}
+
+func (e *AlertmanagerConfigurationValidationError) Error() string {
+	return e.err
+}
+
 // AlertmanagerGlobalConfig configures parameters that are valid in all other configuration contexts.
 // See https://prometheus.io/docs/alerting/latest/configuration/#configuration-file
 type AlertmanagerGlobalConfig struct {
@@ -2144,22 +5712,84 @@ type AlertmanagerStatus struct {
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
 }
 
-// NamespaceSelector is a selector for selecting either all namespaces or a
-// list of namespaces.
-// If `any` is true, it takes precedence over `matchNames`.
-// If `matchNames` is empty and `any` is false, it means that the objects are
-// selected from the current namespace.
+// NamespaceSelector is a selector for selecting either all namespaces, the
+// namespaces matching a label selector or a list of namespaces.
+// The precedence order is: `any`, then `matchLabels`/`matchExpressions`, then
+// `matchNames`. If `any` is true, all the other fields are ignored.
+// If none of the fields is set, it means that the objects are selected from
+// the current namespace.
 // +k8s:openapi-gen=true
 type NamespaceSelector struct {
 	// Boolean describing whether all namespaces are selected in contrast to a
 	// list restricting them.
 	Any bool `json:"any,omitempty"`
 	// List of namespace names to select from.
+	// Ignored if `any` is true or if a label selector is defined.
 	MatchNames []string `json:"matchNames,omitempty"`
+	// Selects the namespaces having all the labels. The operator resolves the
+	// matching namespaces when generating the configuration.
+	// Ignored if `any` is true.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+	// Selects the namespaces matching all the label selector requirements.
+	// The operator resolves the matching namespaces when generating the
+	// configuration.
+	// Ignored if `any` is true.
+	// +optional
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+}
+
+// LabelSelector returns the label selector defined by `matchLabels` and
+// `matchExpressions` or nil if none of them is set.
+func (ns *NamespaceSelector) LabelSelector() *metav1.LabelSelector {
+	if len(ns.MatchLabels) == 0 && len(ns.MatchExpressions) == 0 {
+		return nil
+	}
+
+	return &metav1.LabelSelector{
+		MatchLabels:      ns.MatchLabels,
+		MatchExpressions: ns.MatchExpressions,
+	}
+}
 
-	// TODO(fabxc): this should embed metav1.LabelSelector eventually.
-	// Currently the selector is only used for namespaces which require more complex
-	// implementation to support label selections.
+// NamespaceSelectorValidationError is returned by NamespaceSelector.Validate()
+// on semantically invalid configurations.
+// +k8s:openapi-gen=false
+type NamespaceSelectorValidationError struct {
+	err string
+}
+
+func (e *NamespaceSelectorValidationError) Error() string {
+	return e.err
+}
+
+// Validate semantically validates the given NamespaceSelector.
+// It returns a validation warning when fields are ignored because of the
+// precedence order.
+func (ns *NamespaceSelector) Validate() error {
+	for i, n := range ns.MatchNames {
+		if n == "" {
+			return &NamespaceSelectorValidationError{fmt.Sprintf("matchNames[%d] must not be empty", i)}
+		}
+	}
+
+	ls := ns.LabelSelector()
+	if ls != nil {
+		if _, err := metav1.LabelSelectorAsSelector(ls); err != nil {
+			return &NamespaceSelectorValidationError{fmt.Sprintf("invalid label selector: %v", err)}
+		}
+	}
+
+	switch {
+	case ns.Any && ls != nil:
+		return NewValidationWarning("any is true, matchLabels, matchExpressions and matchNames are ignored")
+	case ns.Any && len(ns.MatchNames) > 0:
+		return NewValidationWarning("any is true, matchNames is ignored")
+	case ls != nil && len(ns.MatchNames) > 0:
+		return NewValidationWarning("a label selector is defined, matchNames is ignored")
+	}
+
+	return nil
 }
 
 // /--rules.*/ command-line arguments
@@ -2257,6 +5887,16 @@ type SafeAuthorization struct {
 	Credentials *v1.SecretKeySelector `json:"credentials,omitempty"`
 }
 
+// EffectiveType returns the authentication type, that is `Bearer` when the
+// type is empty or the type as defined by the user otherwise.
+func (c *SafeAuthorization) EffectiveType() string {
+	if c.Type == "" {
+		return "Bearer"
+	}
+
+	return c.Type
+}
+
 // Validate semantically validates the given Authorization section.
 func (c *SafeAuthorization) Validate() error {
 	if c == nil {
@@ -2302,6 +5942,36 @@ func (e *AuthorizationValidationError) Error() string {
 	return e.err
 }
 
+// ValidationWarning is returned by Validate() methods when the configuration
+// is semantically valid but is likely to behave differently from what the
+// user expects. Callers should report the warning instead of rejecting the
+// configuration.
+// +k8s:openapi-gen=false
+type ValidationWarning struct {
+	warnings []string
+}
+
+// NewValidationWarning returns a *ValidationWarning for the given messages.
+func NewValidationWarning(warnings ...string) *ValidationWarning {
+	return &ValidationWarning{warnings: warnings}
+}
+
+// Warnings returns the list of warning messages.
+func (w *ValidationWarning) Warnings() []string {
+	return w.warnings
+}
+
+func (w *ValidationWarning) Error() string {
+	return strings.Join(w.warnings, "; ")
+}
+
+// IsValidationWarning returns true if the error is (or wraps) a
+// *ValidationWarning.
+func IsValidationWarning(err error) bool {
+	var w *ValidationWarning
+	return errors.As(err, &w)
+}
+
 // Argument as part of the AdditionalArgs list.
 // +k8s:openapi-gen=true
 type Argument struct {
@@ -16,10 +16,17 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestMarshallServiceMonitor(t *testing.T) {
@@ -313,6 +320,7 @@ func TestValidateProbeTargets(t *testing.T) {
 		name         string
 		probeTargets ProbeTargets
 		wantErr      bool
+		wantWarning  bool
 	}{
 
 		{
@@ -346,12 +354,3547 @@ func TestValidateProbeTargets(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "static config without targets",
+			probeTargets: ProbeTargets{
+				StaticConfig: &ProbeTargetStaticConfig{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "static config with empty target",
+			probeTargets: ProbeTargets{
+				StaticConfig: &ProbeTargetStaticConfig{
+					Targets: []string{"/probe", ""},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "static config with whitespace target",
+			probeTargets: ProbeTargets{
+				StaticConfig: &ProbeTargetStaticConfig{
+					Targets: []string{"example.com", " "},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "static config with invalid label name",
+			probeTargets: ProbeTargets{
+				StaticConfig: &ProbeTargetStaticConfig{
+					Targets: []string{"example.com"},
+					Labels:  map[string]string{"app-name": "foo"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "static config with invalid relabeling config",
+			probeTargets: ProbeTargets{
+				StaticConfig: &ProbeTargetStaticConfig{
+					Targets:        []string{"example.com"},
+					RelabelConfigs: []*RelabelConfig{{Regex: "("}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ingress with invalid relabeling config",
+			probeTargets: ProbeTargets{
+				Ingress: &ProbeTargetIngress{
+					RelabelConfigs: []*RelabelConfig{{Regex: "("}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ingress with null relabeling config",
+			probeTargets: ProbeTargets{
+				Ingress: &ProbeTargetIngress{
+					RelabelConfigs: []*RelabelConfig{nil},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ingress with invalid selector",
+			probeTargets: ProbeTargets{
+				Ingress: &ProbeTargetIngress{
+					Selector: metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      "app",
+								Operator: metav1.LabelSelectorOpIn,
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ingress with empty namespace name",
+			probeTargets: ProbeTargets{
+				Ingress: &ProbeTargetIngress{
+					NamespaceSelector: NamespaceSelector{
+						MatchNames: []string{""},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ingress with any and matchNames namespace selector",
+			probeTargets: ProbeTargets{
+				Ingress: &ProbeTargetIngress{
+					NamespaceSelector: NamespaceSelector{
+						Any:        true,
+						MatchNames: []string{"default"},
+					},
+				},
+			},
+			wantErr:     true,
+			wantWarning: true,
+		},
+		{
+			name: "both staticConfig and ingress",
+			probeTargets: ProbeTargets{
+				StaticConfig: &ProbeTargetStaticConfig{
+					Targets: []string{"/probe"},
+				},
+				Ingress: &ProbeTargetIngress{},
+			},
+			wantErr:     true,
+			wantWarning: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.probeTargets.Validate(); (err != nil) != tt.wantErr {
+			err := tt.probeTargets.Validate()
+			if (err != nil) != tt.wantErr {
 				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if IsValidationWarning(err) != tt.wantWarning {
+				t.Errorf("Validate() error = %v, wantWarning %v", err, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestValidateEmbeddedObjectMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		meta *EmbeddedObjectMetadata
+		err  bool
+	}{
+		{
+			name: "nil metadata",
+		},
+		{
+			name: "valid labels and annotations",
+			meta: &EmbeddedObjectMetadata{
+				Labels:      map[string]string{"app.kubernetes.io/team": "monitoring"},
+				Annotations: map[string]string{"example.com/Cost-Center": "1234 abc"},
+			},
+		},
+		{
+			name: "invalid label key",
+			meta: &EmbeddedObjectMetadata{
+				Labels: map[string]string{"foo bar": "baz"},
+			},
+			err: true,
+		},
+		{
+			name: "invalid label value",
+			meta: &EmbeddedObjectMetadata{
+				Labels: map[string]string{"foo": "bar baz"},
+			},
+			err: true,
+		},
+		{
+			name: "invalid annotation key",
+			meta: &EmbeddedObjectMetadata{
+				Annotations: map[string]string{"/foo": "bar"},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.meta.Validate()
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.meta)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %+v not to fail, err: %s", tc.meta, err)
+			}
+		})
+	}
+}
+
+func TestValidateAlertmanagerEndpoints(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		am      AlertmanagerEndpoints
+		err     bool
+		warning bool
+	}{
+		{
+			name: "minimal example",
+			am: AlertmanagerEndpoints{
+				Port: intstr.FromString("web"),
+			},
+		},
+		{
+			name: "explicit scheme and API version",
+			am: AlertmanagerEndpoints{
+				Port:       intstr.FromInt(9093),
+				Scheme:     "https",
+				APIVersion: "v2",
+			},
+		},
+		{
+			name: "missing port",
+			am:   AlertmanagerEndpoints{},
+			err:  true,
+		},
+		{
+			name: "port out of range",
+			am: AlertmanagerEndpoints{
+				Port: intstr.FromInt(70000),
+			},
+			err: true,
+		},
+		{
+			name: "invalid port name",
+			am: AlertmanagerEndpoints{
+				Port: intstr.FromString("web_port"),
+			},
+			err: true,
+		},
+		{
+			name: "invalid scheme",
+			am: AlertmanagerEndpoints{
+				Port:   intstr.FromString("web"),
+				Scheme: "ftp",
+			},
+			err: true,
+		},
+		{
+			name: "invalid API version",
+			am: AlertmanagerEndpoints{
+				Port:       intstr.FromString("web"),
+				APIVersion: "V2",
+			},
+			err: true,
+		},
+		{
+			name: "bearer token secret",
+			am: AlertmanagerEndpoints{
+				Port: intstr.FromString("web"),
+				BearerTokenSecret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+					Key:                  "token",
+				},
+			},
+		},
+		{
+			name: "bearer token secret and file",
+			am: AlertmanagerEndpoints{
+				Port:            intstr.FromString("web"),
+				BearerTokenFile: "/etc/token",
+				BearerTokenSecret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+					Key:                  "token",
+				},
+			},
+			err: true,
+		},
+		{
+			name: "bearer token secret and authorization",
+			am: AlertmanagerEndpoints{
+				Port:          intstr.FromString("web"),
+				Authorization: &SafeAuthorization{},
+				BearerTokenSecret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+					Key:                  "token",
+				},
+			},
+			err: true,
+		},
+		{
+			name: "bearer token secret without key",
+			am: AlertmanagerEndpoints{
+				Port: intstr.FromString("web"),
+				BearerTokenSecret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "deprecated API version",
+			am: AlertmanagerEndpoints{
+				Port:       intstr.FromString("web"),
+				APIVersion: "v1",
+			},
+			err:     true,
+			warning: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.am.Validate()
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.am)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %+v not to fail, err: %s", tc.am, err)
+			}
+			if IsValidationWarning(err) != tc.warning {
+				t.Fatalf("expected warning to be %v, got %v", tc.warning, err)
+			}
+		})
+	}
+}
+
+func TestPortAsString(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		port     intstr.IntOrString
+		expected string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name: "zero",
+			port: intstr.FromInt(0),
+		},
+		{
+			name:     "number",
+			port:     intstr.FromInt(9090),
+			expected: "9090",
+		},
+		{
+			name:     "name",
+			port:     intstr.FromString("web"),
+			expected: "web",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PortAsString(tc.port); got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidatePort(t *testing.T) {
+	for _, tc := range []struct {
+		port intstr.IntOrString
+		err  bool
+	}{
+		{
+			port: intstr.FromInt(1),
+		},
+		{
+			port: intstr.FromInt(65535),
+		},
+		{
+			port: intstr.FromString("http-metrics"),
+		},
+		{
+			port: intstr.FromInt(0),
+			err:  true,
+		},
+		{
+			port: intstr.FromInt(65536),
+			err:  true,
+		},
+		{
+			port: intstr.FromString(""),
+			err:  true,
+		},
+		{
+			port: intstr.FromString("metrics_port"),
+			err:  true,
+		},
+		{
+			port: intstr.FromString("very-long-port-name"),
+			err:  true,
+		},
+	} {
+		t.Run(tc.port.String(), func(t *testing.T) {
+			err := ValidatePort(tc.port)
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %q to fail, but got no error", tc.port.String())
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %q not to fail, err: %s", tc.port.String(), err)
+			}
+		})
+	}
+}
+
+func TestValidatePrometheusTracingConfig(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	for _, tc := range []struct {
+		name   string
+		config *PrometheusTracingConfig
+		err    bool
+	}{
+		{
+			name: "nil config",
+		},
+		{
+			name: "minimal example",
+			config: &PrometheusTracingConfig{
+				Endpoint: "otel-collector:4317",
+			},
+		},
+		{
+			name: "valid sampling fraction",
+			config: &PrometheusTracingConfig{
+				Endpoint:         "otel-collector:4317",
+				ClientType:       strPtr("grpc"),
+				SamplingFraction: strPtr("0.25"),
+			},
+		},
+		{
+			name:   "missing endpoint",
+			config: &PrometheusTracingConfig{},
+			err:    true,
+		},
+		{
+			name: "invalid sampling fraction",
+			config: &PrometheusTracingConfig{
+				Endpoint:         "otel-collector:4317",
+				SamplingFraction: strPtr("half"),
+			},
+			err: true,
+		},
+		{
+			name: "sampling fraction out of range",
+			config: &PrometheusTracingConfig{
+				Endpoint:         "otel-collector:4317",
+				SamplingFraction: strPtr("1.5"),
+			},
+			err: true,
+		},
+		{
+			name: "invalid client type",
+			config: &PrometheusTracingConfig{
+				Endpoint:   "otel-collector:4317",
+				ClientType: strPtr("udp"),
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.config)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %+v not to fail, err: %s", tc.config, err)
+			}
 		})
 	}
 }
+
+func TestResolveScrapeClass(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	boolPtr := func(b bool) *bool { return &b }
+
+	for _, tc := range []struct {
+		name          string
+		scrapeClasses []ScrapeClass
+		className     *string
+		expected      string
+		err           bool
+	}{
+		{
+			name: "no scrape classes",
+		},
+		{
+			name:          "default scrape class",
+			scrapeClasses: []ScrapeClass{{Name: "other"}, {Name: "default", Default: boolPtr(true)}},
+			expected:      "default",
+		},
+		{
+			name:          "no default scrape class",
+			scrapeClasses: []ScrapeClass{{Name: "other", Default: boolPtr(false)}},
+		},
+		{
+			name:          "named scrape class",
+			scrapeClasses: []ScrapeClass{{Name: "other"}, {Name: "default", Default: boolPtr(true)}},
+			className:     strPtr("other"),
+			expected:      "other",
+		},
+		{
+			name:          "missing named scrape class",
+			scrapeClasses: []ScrapeClass{{Name: "default", Default: boolPtr(true)}},
+			className:     strPtr("other"),
+			err:           true,
+		},
+		{
+			name:      "named scrape class without scrape classes",
+			className: strPtr("other"),
+			err:       true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cpf := &CommonPrometheusFields{ScrapeClasses: tc.scrapeClasses}
+
+			sc, err := cpf.ResolveScrapeClass(tc.className)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			switch {
+			case tc.expected == "" && sc != nil:
+				t.Fatalf("expected no scrape class, got %q", sc.Name)
+			case tc.expected != "" && (sc == nil || sc.Name != tc.expected):
+				t.Fatalf("expected scrape class %q, got %v", tc.expected, sc)
+			}
+		})
+	}
+}
+
+func TestValidateScrapeClasses(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	basicAuth := &BasicAuth{
+		Username: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "creds"},
+			Key:                  "username",
+		},
+		Password: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "creds"},
+			Key:                  "password",
+		},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		scrapeClasses []ScrapeClass
+		err           bool
+	}{
+		{
+			name: "no scrape classes",
+		},
+		{
+			name: "valid scrape classes",
+			scrapeClasses: []ScrapeClass{
+				{Name: "default", Default: boolPtr(true), DefaultBasicAuth: basicAuth},
+				{Name: "other", Default: boolPtr(false)},
+			},
+		},
+		{
+			name:          "missing name",
+			scrapeClasses: []ScrapeClass{{}},
+			err:           true,
+		},
+		{
+			name: "duplicate names",
+			scrapeClasses: []ScrapeClass{
+				{Name: "default"},
+				{Name: "default"},
+			},
+			err: true,
+		},
+		{
+			name: "multiple default scrape classes",
+			scrapeClasses: []ScrapeClass{
+				{Name: "default", Default: boolPtr(true)},
+				{Name: "other", Default: boolPtr(true)},
+			},
+			err: true,
+		},
+		{
+			name: "valid fallback scrape protocol",
+			scrapeClasses: []ScrapeClass{
+				{Name: "default", FallbackScrapeProtocol: func(sp ScrapeProtocol) *ScrapeProtocol { return &sp }(PrometheusText0_0_4)},
+			},
+		},
+		{
+			name: "invalid fallback scrape protocol",
+			scrapeClasses: []ScrapeClass{
+				{Name: "default", FallbackScrapeProtocol: func(sp ScrapeProtocol) *ScrapeProtocol { return &sp }("text/plain")},
+			},
+			err: true,
+		},
+		{
+			name: "basic auth without password",
+			scrapeClasses: []ScrapeClass{
+				{
+					Name: "default",
+					DefaultBasicAuth: &BasicAuth{
+						Username: basicAuth.Username,
+					},
+				},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cpf := &CommonPrometheusFields{ScrapeClasses: tc.scrapeClasses}
+			err := cpf.ValidateScrapeClasses()
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.scrapeClasses)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %+v not to fail, err: %s", tc.scrapeClasses, err)
+			}
+		})
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		rule Rule
+		errs int
+	}{
+		{
+			name: "valid alerting rule",
+			rule: Rule{
+				Alert:       "HighErrorRate",
+				Expr:        intstr.FromString("rate(errors_total[5m]) > 1"),
+				For:         "5m",
+				Labels:      map[string]string{"severity": "critical"},
+				Annotations: map[string]string{"summary": "High error rate"},
+			},
+		},
+		{
+			name: "valid recording rule",
+			rule: Rule{
+				Record: "job:errors:rate5m",
+				Expr:   intstr.FromString("sum by (job) (rate(errors_total[5m]))"),
+				Labels: map[string]string{"team": "a"},
+			},
+		},
+		{
+			name: "numeric expression",
+			rule: Rule{
+				Record: "one",
+				Expr:   intstr.FromInt(1),
+			},
+		},
+		{
+			name: "both record and alert",
+			rule: Rule{
+				Record: "job:errors:rate5m",
+				Alert:  "HighErrorRate",
+				Expr:   intstr.FromString("vector(1)"),
+			},
+			errs: 1,
+		},
+		{
+			name: "neither record nor alert",
+			rule: Rule{
+				Expr: intstr.FromString("vector(1)"),
+			},
+			errs: 1,
+		},
+		{
+			name: "invalid recording rule name",
+			rule: Rule{
+				Record: "job-errors",
+				Expr:   intstr.FromString("vector(1)"),
+			},
+			errs: 1,
+		},
+		{
+			name: "empty expression",
+			rule: Rule{
+				Alert: "HighErrorRate",
+				Expr:  intstr.FromString(" "),
+			},
+			errs: 1,
+		},
+		{
+			name: "for and annotations on recording rule",
+			rule: Rule{
+				Record:      "job:errors:rate5m",
+				Expr:        intstr.FromString("vector(1)"),
+				For:         "5m",
+				Annotations: map[string]string{"summary": "foo"},
+			},
+			errs: 2,
+		},
+		{
+			name: "invalid for duration",
+			rule: Rule{
+				Alert: "HighErrorRate",
+				Expr:  intstr.FromString("vector(1)"),
+				For:   "5 minutes",
+			},
+			errs: 1,
+		},
+		{
+			name: "invalid label and annotation names",
+			rule: Rule{
+				Alert:       "HighErrorRate",
+				Expr:        intstr.FromString("vector(1)"),
+				Labels:      map[string]string{"in-valid": "a", "1abc": "b"},
+				Annotations: map[string]string{"sum.mary": "foo"},
+			},
+			errs: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.rule.Validate()
+			if len(errs) != tc.errs {
+				t.Fatalf("expected %d errors, got %d: %v", tc.errs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	for _, tc := range []struct {
+		duration Duration
+		err      bool
+	}{
+		{duration: "0"},
+		{duration: "30s"},
+		{duration: "100ms"},
+		{duration: "1h20m15s"},
+		{duration: "1y2w3d"},
+		{duration: "", err: true},
+		{duration: "1.5h", err: true},
+		{duration: "5 minutes", err: true},
+		{duration: "-1m", err: true},
+		{duration: "1s1m", err: true},
+	} {
+		t.Run(string(tc.duration), func(t *testing.T) {
+			err := tc.duration.Validate()
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %q to fail, but got no error", tc.duration)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %q not to fail, err: %s", tc.duration, err)
+			}
+		})
+	}
+}
+
+func TestRuleExpression(t *testing.T) {
+	for _, tc := range []struct {
+		expr     intstr.IntOrString
+		expected string
+	}{
+		{expr: intstr.FromString("vector(1)"), expected: "vector(1)"},
+		{expr: intstr.FromInt(1), expected: "1"},
+		{expr: intstr.FromInt(0), expected: "0"},
+	} {
+		t.Run(tc.expected, func(t *testing.T) {
+			r := Rule{Expr: tc.expr}
+			if got := r.Expression(); got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateProberSpec(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		prober ProberSpec
+		err    bool
+	}{
+		{
+			name:   "hostname",
+			prober: ProberSpec{URL: "blackbox-exporter.example.com"},
+		},
+		{
+			name:   "hostname and port",
+			prober: ProberSpec{URL: "blackbox-exporter:9115", Scheme: "https", Path: "/probe"},
+		},
+		{
+			name:   "ip address and port",
+			prober: ProberSpec{URL: "192.168.178.3:9115"},
+		},
+		{
+			name:   "hostname starting with a digit",
+			prober: ProberSpec{URL: "12-exporter.example.com"},
+		},
+		{
+			name:   "localhost",
+			prober: ProberSpec{URL: "localhost"},
+		},
+		{
+			name:   "missing url",
+			prober: ProberSpec{},
+			err:    true,
+		},
+		{
+			name:   "empty port",
+			prober: ProberSpec{URL: "blackbox-exporter:"},
+			err:    true,
+		},
+		{
+			name:   "non-numeric port",
+			prober: ProberSpec{URL: "blackbox-exporter:http"},
+			err:    true,
+		},
+		{
+			name:   "port out of range",
+			prober: ProberSpec{URL: "blackbox-exporter:99999"},
+			err:    true,
+		},
+		{
+			name:   "url with scheme",
+			prober: ProberSpec{URL: "http://blackbox-exporter:9115"},
+			err:    true,
+		},
+		{
+			name:   "url with path",
+			prober: ProberSpec{URL: "blackbox-exporter:9115/probe"},
+			err:    true,
+		},
+		{
+			name:   "missing host",
+			prober: ProberSpec{URL: ":9115"},
+			err:    true,
+		},
+		{
+			name:   "invalid scheme",
+			prober: ProberSpec{URL: "blackbox-exporter:9115", Scheme: "ftp"},
+			err:    true,
+		},
+		{
+			name:   "relative path",
+			prober: ProberSpec{URL: "blackbox-exporter:9115", Path: "probe"},
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.prober.Validate()
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.prober)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %+v not to fail, err: %s", tc.prober, err)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerEffectiveConfigSource(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		spec           AlertmanagerSpec
+		expectedUseCRD bool
+		expectedSecret string
+	}{
+		{
+			name:           "both unset",
+			expectedSecret: "alertmanager-main",
+		},
+		{
+			name: "configSecret",
+			spec: AlertmanagerSpec{
+				ConfigSecret: "custom-config",
+			},
+			expectedSecret: "custom-config",
+		},
+		{
+			name: "alertmanagerConfiguration",
+			spec: AlertmanagerSpec{
+				AlertmanagerConfiguration: &AlertmanagerConfiguration{Name: "global"},
+			},
+			expectedUseCRD: true,
+		},
+		{
+			name: "alertmanagerConfiguration takes precedence over configSecret",
+			spec: AlertmanagerSpec{
+				ConfigSecret:              "custom-config",
+				AlertmanagerConfiguration: &AlertmanagerConfiguration{Name: "global"},
+			},
+			expectedUseCRD: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{Name: "main"},
+				Spec:       tc.spec,
+			}
+
+			useCRD, secretName := a.EffectiveConfigSource()
+			if useCRD != tc.expectedUseCRD {
+				t.Fatalf("expected useCRD to be %v, got %v", tc.expectedUseCRD, useCRD)
+			}
+			if secretName != tc.expectedSecret {
+				t.Fatalf("expected secret name %q, got %q", tc.expectedSecret, secretName)
+			}
+		})
+	}
+}
+
+func TestValidateRelabelConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		rc    RelabelConfig
+		err   bool
+		errIn []string
+	}{
+		{
+			name: "empty regex",
+			rc:   RelabelConfig{},
+		},
+		{
+			name: "valid regex",
+			rc:   RelabelConfig{Action: "keep", Regex: "foo|bar"},
+		},
+		{
+			name: "case-insensitive regex",
+			rc:   RelabelConfig{Action: "drop", Regex: "(?i)foo"},
+		},
+		{
+			name:  "invalid regex",
+			rc:    RelabelConfig{Action: "keep", Regex: "foo("},
+			err:   true,
+			errIn: []string{`"foo("`, "keep"},
+		},
+		{
+			name:  "invalid regex with default action",
+			rc:    RelabelConfig{Regex: "[a-"},
+			err:   true,
+			errIn: []string{`"[a-"`, "replace"},
+		},
+		{
+			name: "camel-case action",
+			rc:   RelabelConfig{Action: "LabelDrop", Regex: "foo"},
+		},
+		{
+			name:  "upper-case action",
+			rc:    RelabelConfig{Action: "REPLACE"},
+			err:   true,
+			errIn: []string{`"REPLACE"`, "replace, Replace"},
+		},
+		{
+			name:  "unknown action",
+			rc:    RelabelConfig{Action: "keepequal"},
+			err:   true,
+			errIn: []string{`"keepequal"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rc.Validate()
+			if !tc.err {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.rc)
+			}
+			for _, s := range tc.errIn {
+				if !strings.Contains(err.Error(), s) {
+					t.Fatalf("expected error %q to contain %q", err.Error(), s)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAlertmanagerConfiguration(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  *AlertmanagerConfiguration
+		err  bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "valid name",
+			cfg:  &AlertmanagerConfiguration{Name: "global-config"},
+		},
+		{
+			name: "valid name with dots",
+			cfg:  &AlertmanagerConfiguration{Name: "global.config"},
+		},
+		{
+			name: "valid templates",
+			cfg: &AlertmanagerConfiguration{
+				Name: "global-config",
+				Templates: []SecretOrConfigMap{
+					{
+						ConfigMap: &v1.ConfigMapKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+							Key:                  "template.tmpl",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "empty name",
+			cfg:  &AlertmanagerConfiguration{},
+			err:  true,
+		},
+		{
+			name: "invalid name",
+			cfg:  &AlertmanagerConfiguration{Name: "Global_Config"},
+			err:  true,
+		},
+		{
+			name: "invalid template",
+			cfg: &AlertmanagerConfiguration{
+				Name: "global-config",
+				Templates: []SecretOrConfigMap{
+					{
+						Secret: &v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+							Key:                  "template.tmpl",
+						},
+						ConfigMap: &v1.ConfigMapKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+							Key:                  "template.tmpl",
+						},
+					},
+				},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.cfg)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %+v not to fail, err: %s", tc.cfg, err)
+			}
+		})
+	}
+}
+
+func TestValidateProxyConfig(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	boolPtr := func(b bool) *bool { return &b }
+	proxyConnectHeader := map[string][]v1.SecretKeySelector{
+		"Proxy-Authorization": {
+			{
+				LocalObjectReference: v1.LocalObjectReference{Name: "proxy"},
+				Key:                  "auth",
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		pc   ProxyConfig
+		err  bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "proxy url",
+			pc: ProxyConfig{
+				ProxyURL:           strPtr("http://proxy:8080"),
+				NoProxy:            strPtr("example.com"),
+				ProxyConnectHeader: proxyConnectHeader,
+			},
+		},
+		{
+			name: "proxy from environment",
+			pc: ProxyConfig{
+				ProxyFromEnvironment: boolPtr(true),
+				ProxyConnectHeader:   proxyConnectHeader,
+			},
+		},
+		{
+			name: "proxy from environment with proxy url",
+			pc: ProxyConfig{
+				ProxyURL:             strPtr("http://proxy:8080"),
+				ProxyFromEnvironment: boolPtr(true),
+			},
+			err: true,
+		},
+		{
+			name: "proxy from environment with no proxy",
+			pc: ProxyConfig{
+				NoProxy:              strPtr("example.com"),
+				ProxyFromEnvironment: boolPtr(true),
+			},
+			err: true,
+		},
+		{
+			name: "no proxy without proxy url",
+			pc: ProxyConfig{
+				NoProxy: strPtr("example.com"),
+			},
+			err: true,
+		},
+		{
+			name: "proxy connect header without proxy",
+			pc: ProxyConfig{
+				ProxyConnectHeader: proxyConnectHeader,
+			},
+			err: true,
+		},
+		{
+			name: "proxy connect header without secret key",
+			pc: ProxyConfig{
+				ProxyURL: strPtr("http://proxy:8080"),
+				ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+					"Proxy-Authorization": {
+						{LocalObjectReference: v1.LocalObjectReference{Name: "proxy"}},
+					},
+				},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.pc.Validate()
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.pc)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %+v not to fail, err: %s", tc.pc, err)
+			}
+		})
+	}
+}
+
+func TestRemoteSpecProxyConfig(t *testing.T) {
+	noProxy := "example.com"
+
+	rw := RemoteWriteSpec{URL: "http://remote"}
+	if pc := rw.ProxyConfig(); pc.ProxyURL != nil {
+		t.Fatalf("expected no proxy URL, got %q", *pc.ProxyURL)
+	}
+
+	rw = RemoteWriteSpec{URL: "http://remote", ProxyURL: "http://proxy:8080", NoProxy: &noProxy}
+	pc := rw.ProxyConfig()
+	if pc.ProxyURL == nil || *pc.ProxyURL != "http://proxy:8080" {
+		t.Fatalf("expected proxy URL %q, got %v", "http://proxy:8080", pc.ProxyURL)
+	}
+	if pc.NoProxy == nil || *pc.NoProxy != noProxy {
+		t.Fatalf("expected no proxy %q, got %v", noProxy, pc.NoProxy)
+	}
+
+	rr := RemoteReadSpec{URL: "http://remote", ProxyURL: "http://proxy:8080"}
+	if pc := rr.ProxyConfig(); pc.ProxyURL == nil || *pc.ProxyURL != "http://proxy:8080" {
+		t.Fatalf("expected proxy URL %q, got %v", "http://proxy:8080", pc.ProxyURL)
+	}
+}
+
+func TestAlertmanagerConfigSecretKey(t *testing.T) {
+	spec := AlertmanagerSpec{ConfigSecret: "custom-config"}
+	if key := spec.ConfigSecretKey(); key != "alertmanager.yaml" {
+		t.Fatalf("expected key %q, got %q", "alertmanager.yaml", key)
+	}
+}
+
+func TestValidateAlertmanagerConfigSource(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		spec    AlertmanagerSpec
+		warning bool
+	}{
+		{
+			name: "both unset",
+		},
+		{
+			name: "configSecret",
+			spec: AlertmanagerSpec{ConfigSecret: "custom-config"},
+		},
+		{
+			name: "alertmanagerConfiguration",
+			spec: AlertmanagerSpec{
+				AlertmanagerConfiguration: &AlertmanagerConfiguration{Name: "global"},
+			},
+		},
+		{
+			name: "configSecret and alertmanagerConfiguration",
+			spec: AlertmanagerSpec{
+				ConfigSecret:              "custom-config",
+				AlertmanagerConfiguration: &AlertmanagerConfiguration{Name: "global"},
+			},
+			warning: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.ValidateConfigSource()
+			if !tc.warning {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+
+			if !IsValidationWarning(err) {
+				t.Fatalf("expected a validation warning, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePrometheusRuleSpec(t *testing.T) {
+	keepFiringFor := Duration("10m")
+	invalidDuration := Duration("10 minutes")
+	limit, negativeLimit := int32(100), int32(-1)
+
+	for _, tc := range []struct {
+		name     string
+		spec     PrometheusRuleSpec
+		expected []string
+	}{
+		{
+			name: "valid",
+			spec: PrometheusRuleSpec{
+				Groups: []RuleGroup{
+					{
+						Name: "group1",
+						Rules: []Rule{
+							{Record: "job:up:sum", Expr: intstr.FromString("sum by (job) (up)")},
+							{Alert: "Down", Expr: intstr.FromString("up == 0"), For: "5m", KeepFiringFor: &keepFiringFor},
+						},
+					},
+					{
+						Name:        "group2",
+						Limit:       &limit,
+						QueryOffset: &keepFiringFor,
+						Rules:       []Rule{{Alert: "Down", Expr: intstr.FromString("up == 0")}},
+					},
+				},
+			},
+		},
+		{
+			name: "all errors are reported",
+			spec: PrometheusRuleSpec{
+				Groups: []RuleGroup{
+					{
+						Name: "group1",
+						Rules: []Rule{
+							{Record: "job:up:sum", Alert: "Down", Expr: intstr.FromString("up")},
+							{Record: "job:up:sum", Expr: intstr.FromString("up"), For: "5m"},
+							{Record: "job:up:sum", Expr: intstr.FromString("up"), KeepFiringFor: &keepFiringFor},
+							{Alert: "Down", Expr: intstr.FromString("up == 0"), KeepFiringFor: &invalidDuration},
+						},
+					},
+					{
+						Name: "group1",
+						Rules: []Rule{
+							{Record: "invalid-name", Expr: intstr.FromString("up")},
+						},
+					},
+					{
+						Rules: []Rule{{Alert: "Down", Expr: intstr.FromString("up == 0")}},
+					},
+					{
+						Name:        "group3",
+						Interval:    invalidDuration,
+						Limit:       &negativeLimit,
+						QueryOffset: &invalidDuration,
+						Rules:       []Rule{{Alert: "Down", Expr: intstr.FromString("up == 0")}},
+					},
+				},
+			},
+			expected: []string{
+				"groups[0].rules[0].record",
+				"groups[0].rules[1].for",
+				"groups[0].rules[2].keep_firing_for",
+				"groups[0].rules[3].keep_firing_for",
+				"groups[1].name",
+				"groups[1].rules[0].record",
+				"groups[2].name",
+				"groups[3].interval",
+				"groups[3].limit",
+				"groups[3].query_offset",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if len(tc.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+
+			verr, ok := err.(*PrometheusRuleValidationError)
+			if !ok {
+				t.Fatalf("expected *PrometheusRuleValidationError, got %T", err)
+			}
+
+			var fields []string
+			for _, e := range verr.Errors() {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tc.expected) {
+				t.Fatalf("expected errors for %v, got %v", tc.expected, fields)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerWillFormCluster(t *testing.T) {
+	one, three := int32(1), int32(3)
+
+	for _, tc := range []struct {
+		name     string
+		spec     AlertmanagerSpec
+		expected bool
+	}{
+		{
+			name: "replicas unset",
+		},
+		{
+			name: "single replica",
+			spec: AlertmanagerSpec{Replicas: &one},
+		},
+		{
+			name:     "multiple replicas",
+			spec:     AlertmanagerSpec{Replicas: &three},
+			expected: true,
+		},
+		{
+			name:     "single replica with forceEnableClusterMode",
+			spec:     AlertmanagerSpec{Replicas: &one, ForceEnableClusterMode: true},
+			expected: true,
+		},
+		{
+			name:     "single replica with additional peers",
+			spec:     AlertmanagerSpec{Replicas: &one, AdditionalPeers: []string{"alertmanager.example.com:9094"}},
+			expected: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.spec.WillFormCluster(); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateAlertmanagerSpec(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		spec    AlertmanagerSpec
+		err     bool
+		warning bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "negative terminationGracePeriodSeconds",
+			spec: AlertmanagerSpec{TerminationGracePeriodSeconds: func(i int64) *int64 { return &i }(-1)},
+			err:  true,
+		},
+		{
+			name: "valid configReloaderWatchInterval",
+			spec: AlertmanagerSpec{ConfigReloaderWatchInterval: func(d Duration) *Duration { return &d }("30s")},
+		},
+		{
+			name: "invalid configReloaderWatchInterval",
+			spec: AlertmanagerSpec{ConfigReloaderWatchInterval: func(d Duration) *Duration { return &d }("30 seconds")},
+			err:  true,
+		},
+		{
+			name: "valid clusterAdvertiseAddress",
+			spec: AlertmanagerSpec{ClusterAdvertiseAddress: "203.0.113.10:9094"},
+		},
+		{
+			name: "valid clusterAdvertiseAddress with hostname",
+			spec: AlertmanagerSpec{ClusterAdvertiseAddress: "alertmanager.example.com:9094"},
+		},
+		{
+			name:    "private clusterAdvertiseAddress",
+			spec:    AlertmanagerSpec{ClusterAdvertiseAddress: "192.168.1.10:9094"},
+			warning: true,
+		},
+		{
+			name: "private clusterAdvertiseAddress with invalid additionalPeers",
+			spec: AlertmanagerSpec{
+				ClusterAdvertiseAddress: "10.0.0.1:9094",
+				AdditionalPeers:         []string{":9094"},
+			},
+			err: true,
+		},
+		{
+			name: "clusterAdvertiseAddress without port",
+			spec: AlertmanagerSpec{ClusterAdvertiseAddress: "203.0.113.10"},
+			err:  true,
+		},
+		{
+			name: "clusterAdvertiseAddress without host",
+			spec: AlertmanagerSpec{ClusterAdvertiseAddress: ":9094"},
+			err:  true,
+		},
+		{
+			name: "clusterAdvertiseAddress with invalid port",
+			spec: AlertmanagerSpec{ClusterAdvertiseAddress: "203.0.113.10:gossip"},
+			err:  true,
+		},
+		{
+			name: "valid additionalPeers",
+			spec: AlertmanagerSpec{AdditionalPeers: []string{
+				"alertmanager-0.alertmanager-operated:9094",
+				"alertmanager.example.com",
+				"203.0.113.10",
+				"[2001:db8::1]:9094",
+				"2001:db8::1",
+			}},
+		},
+		{
+			name: "additionalPeers with invalid port",
+			spec: AlertmanagerSpec{AdditionalPeers: []string{"alertmanager.example.com:90940"}},
+			err:  true,
+		},
+		{
+			name: "additionalPeers without host",
+			spec: AlertmanagerSpec{AdditionalPeers: []string{":9094"}},
+			err:  true,
+		},
+		{
+			name: "additionalPeers with invalid host",
+			spec: AlertmanagerSpec{AdditionalPeers: []string{"http://alertmanager:9094"}},
+			err:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.warning != IsValidationWarning(err) {
+				t.Fatalf("expected warning: %v, got %v", tc.warning, err)
+			}
+			if tc.warning {
+				return
+			}
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %+v to fail, but got no error", tc.spec)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %+v not to fail, err: %s", tc.spec, err)
+			}
+		})
+	}
+}
+
+func TestImageReferences(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	defaults := ImageDefaults{
+		PrometheusBaseImage:   "quay.io/prometheus/prometheus",
+		AlertmanagerBaseImage: "quay.io/prometheus/alertmanager",
+		AlertmanagerVersion:   "v0.24.0",
+		ThanosBaseImage:       "quay.io/thanos/thanos",
+		ThanosVersion:         "v0.28.0",
+		ConfigReloaderImage:   "quay.io/prometheus-operator/prometheus-config-reloader:v0.60.1",
+	}
+
+	for _, tc := range []struct {
+		name     string
+		images   func(ImageDefaults) ([]string, error)
+		expected []string
+	}{
+		{
+			name: "prometheus with version",
+			images: (&Prometheus{Spec: PrometheusSpec{CommonPrometheusFields: CommonPrometheusFields{
+				Version: "v2.39.1",
+			}}}).ImageReferences,
+			expected: []string{
+				"quay.io/prometheus/prometheus:v2.39.1",
+				"quay.io/prometheus-operator/prometheus-config-reloader:v0.60.1",
+			},
+		},
+		{
+			name: "prometheus with image and thanos sha",
+			images: (&Prometheus{Spec: PrometheusSpec{
+				CommonPrometheusFields: CommonPrometheusFields{
+					Image:   strPtr("registry.example.com/prometheus:custom"),
+					Version: "v2.39.1",
+				},
+				Thanos: &ThanosSpec{
+					BaseImage: strPtr("registry.example.com/thanos"),
+					Tag:       strPtr("v0.29.0"),
+					SHA:       strPtr("abcdef"),
+				},
+			}}).ImageReferences,
+			expected: []string{
+				"registry.example.com/prometheus:custom",
+				"quay.io/prometheus-operator/prometheus-config-reloader:v0.60.1",
+				"registry.example.com/thanos@sha256:abcdef",
+			},
+		},
+		{
+			name: "prometheus with tagged base image",
+			images: (&Prometheus{Spec: PrometheusSpec{
+				BaseImage: "localhost:5000/prometheus:v2.39.1",
+				Tag:       "ignored",
+			}}).ImageReferences,
+			expected: []string{
+				"localhost:5000/prometheus:v2.39.1",
+				"quay.io/prometheus-operator/prometheus-config-reloader:v0.60.1",
+			},
+		},
+		{
+			name: "prometheus with short name and tag",
+			images: (&Prometheus{Spec: PrometheusSpec{
+				BaseImage: "prometheus",
+				Tag:       "v2.39.1",
+			}}).ImageReferences,
+			expected: []string{
+				"docker.io/library/prometheus:v2.39.1",
+				"quay.io/prometheus-operator/prometheus-config-reloader:v0.60.1",
+			},
+		},
+		{
+			name: "prometheus with short name and digest",
+			images: (&Prometheus{Spec: PrometheusSpec{
+				BaseImage: "prom/prometheus",
+				Tag:       "v2.39.1",
+				SHA:       "abcdef",
+			}}).ImageReferences,
+			expected: []string{
+				"prom/prometheus@sha256:abcdef",
+				"quay.io/prometheus-operator/prometheus-config-reloader:v0.60.1",
+			},
+		},
+		{
+			name:   "alertmanager with default version",
+			images: (&Alertmanager{}).ImageReferences,
+			expected: []string{
+				"quay.io/prometheus/alertmanager:v0.24.0",
+				"quay.io/prometheus-operator/prometheus-config-reloader:v0.60.1",
+			},
+		},
+		{
+			name: "alertmanager with tag",
+			images: (&Alertmanager{Spec: AlertmanagerSpec{
+				BaseImage: "localhost:5000/alertmanager",
+				Tag:       "v0.25.0",
+			}}).ImageReferences,
+			expected: []string{
+				"localhost:5000/alertmanager:v0.25.0",
+				"quay.io/prometheus-operator/prometheus-config-reloader:v0.60.1",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			images, err := tc.images(defaults)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(images, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, images)
+			}
+		})
+	}
+}
+
+func TestValidateScrapeProtocols(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		protocols []ScrapeProtocol
+		err       bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:      "valid",
+			protocols: []ScrapeProtocol{PrometheusProto, OpenMetricsText1_0_0, OpenMetricsText0_0_1, PrometheusText0_0_4},
+		},
+		{
+			name:      "unknown protocol",
+			protocols: []ScrapeProtocol{PrometheusProto, "OpenMetricsText2.0.0"},
+			err:       true,
+		},
+		{
+			name:      "duplicate protocol",
+			protocols: []ScrapeProtocol{PrometheusProto, OpenMetricsText1_0_0, PrometheusProto},
+			err:       true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateScrapeProtocols(tc.protocols)
+			if tc.err && err == nil {
+				t.Fatalf("expected validation of %v to fail, but got no error", tc.protocols)
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected validation of %v not to fail, err: %s", tc.protocols, err)
+			}
+		})
+	}
+}
+
+func TestValidateWebTLSConfig(t *testing.T) {
+	validConfig := func() *WebTLSConfig {
+		return &WebTLSConfig{
+			Cert: SecretOrConfigMap{
+				Secret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "tls"},
+					Key:                  "tls.crt",
+				},
+			},
+			KeySecret: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "tls"},
+				Key:                  "tls.key",
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(*WebTLSConfig)
+		err    bool
+	}{
+		{
+			name:   "valid",
+			mutate: func(*WebTLSConfig) {},
+		},
+		{
+			name: "valid versions, cipher suites and curves",
+			mutate: func(c *WebTLSConfig) {
+				c.MinVersion = "TLS12"
+				c.MaxVersion = "TLS13"
+				c.CipherSuites = []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_AES_128_CBC_SHA"}
+				c.CurvePreferences = []string{"X25519", "CurveP521"}
+			},
+		},
+		{
+			name:   "same min and max versions",
+			mutate: func(c *WebTLSConfig) { c.MinVersion, c.MaxVersion = "TLS12", "TLS12" },
+		},
+		{
+			name:   "unknown minVersion",
+			mutate: func(c *WebTLSConfig) { c.MinVersion = "TLS1.2" },
+			err:    true,
+		},
+		{
+			name:   "unknown maxVersion",
+			mutate: func(c *WebTLSConfig) { c.MaxVersion = "TLS14" },
+			err:    true,
+		},
+		{
+			name:   "inverted versions",
+			mutate: func(c *WebTLSConfig) { c.MinVersion, c.MaxVersion = "TLS13", "TLS12" },
+			err:    true,
+		},
+		{
+			name:   "unknown cipher suite",
+			mutate: func(c *WebTLSConfig) { c.CipherSuites = []string{"TLS_UNKNOWN"} },
+			err:    true,
+		},
+		{
+			name:   "unknown curve",
+			mutate: func(c *WebTLSConfig) { c.CurvePreferences = []string{"P256"} },
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := validConfig()
+			tc.mutate(c)
+
+			err := c.Validate()
+			if tc.err {
+				if _, ok := err.(*WebTLSConfigError); !ok {
+					t.Fatalf("expected *WebTLSConfigError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateThanosSpec(t *testing.T) {
+	invalidDuration := Duration("1 minute")
+	for _, tc := range []struct {
+		name string
+		spec *ThanosSpec
+		err  bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "relative minTime",
+			spec: &ThanosSpec{MinTime: "-2h45m"},
+		},
+		{
+			name: "positive relative minTime",
+			spec: &ThanosSpec{MinTime: "1d"},
+		},
+		{
+			name: "RFC3339 minTime",
+			spec: &ThanosSpec{MinTime: "2022-10-01T00:00:00Z"},
+		},
+		{
+			name: "invalid minTime",
+			spec: &ThanosSpec{MinTime: "-1 day"},
+			err:  true,
+		},
+		{
+			name: "minTime with invalid date",
+			spec: &ThanosSpec{MinTime: "2022-10-01"},
+			err:  true,
+		},
+		{
+			name: "invalid getConfigInterval",
+			spec: &ThanosSpec{GetConfigInterval: &invalidDuration},
+			err:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.err {
+				if _, ok := err.(*ThanosSpecValidationError); !ok {
+					t.Fatalf("expected *ThanosSpecValidationError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePrometheusSecurityContext(t *testing.T) {
+	boolFalse := false
+	user := int64(1000)
+
+	for _, tc := range []struct {
+		name     string
+		spec     PrometheusSpec
+		warnings int
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "query log file in the default volume",
+			spec: PrometheusSpec{QueryLogFile: "query.log"},
+		},
+		{
+			name: "query log file to stdout",
+			spec: PrometheusSpec{QueryLogFile: "/dev/stdout"},
+		},
+		{
+			name: "query log file in a mounted volume",
+			spec: PrometheusSpec{
+				QueryLogFile: "/var/log/prometheus/queries/query.log",
+				CommonPrometheusFields: CommonPrometheusFields{
+					VolumeMounts: []v1.VolumeMount{{Name: "logs", MountPath: "/var/log/prometheus/"}},
+				},
+			},
+		},
+		{
+			name:     "query log file without volume",
+			spec:     PrometheusSpec{QueryLogFile: "/var/log/prometheus/query.log"},
+			warnings: 1,
+		},
+		{
+			name: "query log file in a read-only volume",
+			spec: PrometheusSpec{
+				QueryLogFile: "/var/log/prometheus/query.log",
+				CommonPrometheusFields: CommonPrometheusFields{
+					VolumeMounts: []v1.VolumeMount{{Name: "logs", MountPath: "/var/log/prometheus", ReadOnly: true}},
+				},
+			},
+			warnings: 1,
+		},
+		{
+			name: "query log file with writable root filesystem",
+			spec: PrometheusSpec{
+				QueryLogFile: "/var/log/prometheus/query.log",
+				CommonPrometheusFields: CommonPrometheusFields{
+					Containers: []v1.Container{
+						{Name: "prometheus", SecurityContext: &v1.SecurityContext{ReadOnlyRootFilesystem: &boolFalse}},
+					},
+				},
+			},
+		},
+		{
+			name: "persistent storage without fsGroup",
+			spec: PrometheusSpec{
+				QueryLogFile: "/var/log/query.log",
+				CommonPrometheusFields: CommonPrometheusFields{
+					Storage:         &StorageSpec{},
+					SecurityContext: &v1.PodSecurityContext{RunAsUser: &user},
+				},
+			},
+			warnings: 2,
+		},
+		{
+			name: "emptyDir storage without fsGroup",
+			spec: PrometheusSpec{
+				CommonPrometheusFields: CommonPrometheusFields{
+					Storage:         &StorageSpec{EmptyDir: &v1.EmptyDirVolumeSource{}},
+					SecurityContext: &v1.PodSecurityContext{RunAsUser: &user},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.ValidateSecurityContext()
+			if tc.warnings == 0 {
+				if err != nil {
+					t.Fatalf("expected no warning, got %v", err)
+				}
+				return
+			}
+
+			w, ok := err.(*ValidationWarning)
+			if !ok {
+				t.Fatalf("expected *ValidationWarning, got %v", err)
+			}
+			if len(w.Warnings()) != tc.warnings {
+				t.Fatalf("expected %d warnings, got %v", tc.warnings, w.Warnings())
+			}
+		})
+	}
+}
+
+func TestValidateProjectedToken(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		token *ProjectedToken
+		err   bool
+	}{
+		{name: "nil"},
+		{name: "audience only", token: &ProjectedToken{Audience: "oidc"}},
+		{name: "minimum expiration", token: &ProjectedToken{Audience: "oidc", ExpirationSeconds: func(i int64) *int64 { return &i }(600)}},
+		{name: "missing audience", token: &ProjectedToken{}, err: true},
+		{name: "expiration too short", token: &ProjectedToken{Audience: "oidc", ExpirationSeconds: func(i int64) *int64 { return &i }(599)}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.token.Validate()
+			if tc.err && err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateServiceMonitorSpec(t *testing.T) {
+	targetPort := intstr.FromString("metrics")
+	zeroTargetPort := intstr.FromInt(0)
+	invalidTargetPort := intstr.FromInt(70000)
+
+	for _, tc := range []struct {
+		name     string
+		endpoint Endpoint
+		err      bool
+	}{
+		{name: "port", endpoint: Endpoint{Port: "web"}},
+		{name: "targetPort", endpoint: Endpoint{TargetPort: &targetPort}},
+		{
+			name: "address from relabelings",
+			endpoint: Endpoint{
+				RelabelConfigs: []*RelabelConfig{{TargetLabel: "__address__", Replacement: "example.com:9090"}},
+			},
+		},
+		{name: "https scheme", endpoint: Endpoint{Port: "web", Scheme: "https"}},
+		{name: "interval equal to scrapeTimeout", endpoint: Endpoint{Port: "web", Interval: "10s", ScrapeTimeout: "10s"}},
+		{name: "scrapeTimeout only", endpoint: Endpoint{Port: "web", ScrapeTimeout: "1m"}},
+		{name: "port and zero targetPort", endpoint: Endpoint{Port: "web", TargetPort: &zeroTargetPort}},
+		{name: "port and targetPort", endpoint: Endpoint{Port: "web", TargetPort: &targetPort}, err: true},
+		{name: "zero targetPort only", endpoint: Endpoint{TargetPort: &zeroTargetPort}, err: true},
+		{name: "targetPort out of range", endpoint: Endpoint{TargetPort: &invalidTargetPort}, err: true},
+		{name: "no port", endpoint: Endpoint{}, err: true},
+		{
+			name: "no port with unrelated relabelings",
+			endpoint: Endpoint{
+				RelabelConfigs: []*RelabelConfig{{TargetLabel: "__address__", Action: "drop"}},
+			},
+			err: true,
+		},
+		{name: "invalid scheme", endpoint: Endpoint{Port: "web", Scheme: "ftp"}, err: true},
+		{name: "invalid interval", endpoint: Endpoint{Port: "web", Interval: "10"}, err: true},
+		{name: "invalid scrapeTimeout", endpoint: Endpoint{Port: "web", ScrapeTimeout: "foo"}, err: true},
+		{name: "scrapeTimeout greater than interval", endpoint: Endpoint{Port: "web", Interval: "10s", ScrapeTimeout: "1m"}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := ServiceMonitorSpec{
+				Endpoints: []Endpoint{{Port: "web"}, tc.endpoint},
+			}
+
+			err := spec.Validate()
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				if !strings.HasPrefix(err.Error(), "endpoints[1]: ") {
+					t.Fatalf("expected error to reference endpoints[1], got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestEffectiveRemoteTimeout(t *testing.T) {
+	if got := (&RemoteWriteSpec{}).EffectiveRemoteTimeout(); got != "30s" {
+		t.Fatalf("expected 30s, got %s", got)
+	}
+
+	if got := (&RemoteWriteSpec{RemoteTimeout: "10s"}).EffectiveRemoteTimeout(); got != "10s" {
+		t.Fatalf("expected 10s, got %s", got)
+	}
+
+	if got := (&RemoteReadSpec{}).EffectiveRemoteTimeout(); got != "1m" {
+		t.Fatalf("expected 1m, got %s", got)
+	}
+
+	if got := (&RemoteReadSpec{RemoteTimeout: "2m"}).EffectiveRemoteTimeout(); got != "2m" {
+		t.Fatalf("expected 2m, got %s", got)
+	}
+}
+
+func TestValidateQueueConfig(t *testing.T) {
+	durationPtr := func(d Duration) *Duration { return &d }
+
+	for _, tc := range []struct {
+		name    string
+		queue   *QueueConfig
+		err     bool
+		warning bool
+	}{
+		{name: "nil"},
+		{name: "empty", queue: &QueueConfig{}},
+		{name: "valid", queue: &QueueConfig{Capacity: 2500, MaxSamplesPerSend: 500}},
+		{name: "only maxSamplesPerSend", queue: &QueueConfig{MaxSamplesPerSend: 500}},
+		{name: "negative capacity", queue: &QueueConfig{Capacity: -1}, err: true},
+		{name: "negative maxSamplesPerSend", queue: &QueueConfig{MaxSamplesPerSend: -1}, err: true},
+		{name: "capacity lower than maxSamplesPerSend", queue: &QueueConfig{Capacity: 100, MaxSamplesPerSend: 500}, warning: true},
+		{name: "valid sampleAgeLimit", queue: &QueueConfig{SampleAgeLimit: durationPtr("2h")}},
+		{name: "invalid sampleAgeLimit", queue: &QueueConfig{SampleAgeLimit: durationPtr("2 hours")}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.queue.Validate()
+			switch {
+			case tc.err:
+				if err == nil || IsValidationWarning(err) {
+					t.Fatalf("expected error but got: %v", err)
+				}
+			case tc.warning:
+				if !IsValidationWarning(err) {
+					t.Fatalf("expected warning but got: %v", err)
+				}
+				if !strings.Contains(err.Error(), "1500") {
+					t.Fatalf("expected the recommended capacity in the warning, got: %v", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateExternalLabels(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	for _, tc := range []struct {
+		name     string
+		cpf      CommonPrometheusFields
+		err      bool
+		warnings int
+	}{
+		{name: "no external labels"},
+		{
+			name: "valid external labels",
+			cpf:  CommonPrometheusFields{ExternalLabels: map[string]string{"cluster": "eu-west-1", "env": "prod"}},
+		},
+		{
+			name: "reserved label name",
+			cpf:  CommonPrometheusFields{ExternalLabels: map[string]string{"__cluster__": "eu-west-1"}},
+			err:  true,
+		},
+		{
+			name: "invalid label name",
+			cpf:  CommonPrometheusFields{ExternalLabels: map[string]string{"cluster-name": "eu-west-1"}},
+			err:  true,
+		},
+		{
+			name:     "default replica label name",
+			cpf:      CommonPrometheusFields{ExternalLabels: map[string]string{"prometheus_replica": "a"}},
+			warnings: 1,
+		},
+		{
+			name:     "default prometheus label name",
+			cpf:      CommonPrometheusFields{ExternalLabels: map[string]string{"prometheus": "ns/name"}},
+			warnings: 1,
+		},
+		{
+			name: "custom replica and prometheus label names",
+			cpf: CommonPrometheusFields{
+				ExternalLabels:              map[string]string{"prometheus": "ns/name", "replica": "a", "cluster": "b"},
+				PrometheusExternalLabelName: strPtr("cluster"),
+				ReplicaExternalLabelName:    strPtr("replica"),
+			},
+			warnings: 2,
+		},
+		{
+			name: "disabled replica and prometheus labels",
+			cpf: CommonPrometheusFields{
+				ExternalLabels:              map[string]string{"prometheus": "ns/name", "prometheus_replica": "a"},
+				PrometheusExternalLabelName: strPtr(""),
+				ReplicaExternalLabelName:    strPtr(""),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cpf.ValidateExternalLabels()
+			switch {
+			case tc.err:
+				var verr *ExternalLabelsValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("expected ExternalLabelsValidationError but got: %v", err)
+				}
+			case tc.warnings > 0:
+				var w *ValidationWarning
+				if !errors.As(err, &w) {
+					t.Fatalf("expected warning but got: %v", err)
+				}
+				if len(w.Warnings()) != tc.warnings {
+					t.Fatalf("expected %d warnings, got %v", tc.warnings, w.Warnings())
+				}
+			default:
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateExemplars(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+
+	for _, tc := range []struct {
+		name           string
+		enableFeatures []string
+		remoteWrite    []RemoteWriteSpec
+		warnings       []string
+	}{
+		{
+			name: "no remote write",
+		},
+		{
+			name:        "exemplars not sent",
+			remoteWrite: []RemoteWriteSpec{{URL: "http://example.com"}, {URL: "http://example.org", SendExemplars: boolPtr(false)}},
+		},
+		{
+			name:           "exemplar storage enabled",
+			enableFeatures: []string{"memory-snapshot-on-shutdown", "exemplar-storage"},
+			remoteWrite:    []RemoteWriteSpec{{URL: "http://example.com", SendExemplars: boolPtr(true)}},
+		},
+		{
+			name:           "exemplar storage disabled",
+			enableFeatures: []string{"memory-snapshot-on-shutdown"},
+			remoteWrite: []RemoteWriteSpec{
+				{URL: "http://example.com", SendExemplars: boolPtr(true)},
+				{Name: "disabled", URL: "http://example.org", SendExemplars: boolPtr(true), Enabled: boolPtr(false)},
+				{Name: "named", URL: "http://example.net", SendExemplars: boolPtr(true)},
+			},
+			warnings: []string{
+				`remoteWrite[0]: sendExemplars has no effect without the "exemplar-storage" feature enabled`,
+				`remoteWrite[2] ("named"): sendExemplars has no effect without the "exemplar-storage" feature enabled`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cpf := &CommonPrometheusFields{
+				EnableFeatures: tc.enableFeatures,
+				RemoteWrite:    tc.remoteWrite,
+			}
+
+			err := cpf.ValidateExemplars()
+			if len(tc.warnings) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				return
+			}
+
+			var w *ValidationWarning
+			if !errors.As(err, &w) {
+				t.Fatalf("expected warning but got: %v", err)
+			}
+			if !reflect.DeepEqual(w.Warnings(), tc.warnings) {
+				t.Fatalf("expected warnings %q, got %q", tc.warnings, w.Warnings())
+			}
+		})
+	}
+}
+
+func TestEffectiveRetryOnRateLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		queue    *QueueConfig
+		version  string
+		expected bool
+		err      bool
+	}{
+		{name: "nil queue", version: "v2.20.0"},
+		{name: "disabled", queue: &QueueConfig{}, version: "v2.20.0"},
+		{name: "supported version", queue: &QueueConfig{RetryOnRateLimit: true}, version: "v2.26.0", expected: true},
+		{name: "pre-release version", queue: &QueueConfig{RetryOnRateLimit: true}, version: "2.30.0-rc.0", expected: true},
+		{name: "unsupported version", queue: &QueueConfig{RetryOnRateLimit: true}, version: "v2.25.2", err: true},
+		{name: "invalid version", queue: &QueueConfig{RetryOnRateLimit: true}, version: "latest", err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.queue.EffectiveRetryOnRateLimit(tc.version)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateCommonPrometheusFields(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		scrapeInterval Duration
+		scrapeTimeout  Duration
+		// terminationGracePeriod is left unset when 0.
+		terminationGracePeriod int64
+		serviceDiscoveryRole   ServiceDiscoveryRole
+		// shards is left unset when 0.
+		shards         int32
+		shardingLabels []LabelName
+		// additionalScrapeConfigs sets both the Secret reference and the
+		// inline scrape configurations.
+		additionalScrapeConfigs bool
+		// configReloaderWatchInterval is left unset when empty.
+		configReloaderWatchInterval Duration
+		err                         bool
+	}{
+		{name: "no timeout", scrapeInterval: "10s"},
+		{name: "timeout equal to interval", scrapeInterval: "1m", scrapeTimeout: "60s"},
+		{name: "timeout lower than default interval", scrapeTimeout: "20s"},
+		{name: "timeout greater than interval", scrapeInterval: "10s", scrapeTimeout: "15s", err: true},
+		{name: "timeout greater than default interval", scrapeTimeout: "1m", err: true},
+		{name: "invalid timeout", scrapeTimeout: "1x", err: true},
+		{name: "negative termination grace period", terminationGracePeriod: -1, err: true},
+		{name: "valid service discovery role", serviceDiscoveryRole: EndpointSliceRole},
+		{name: "invalid service discovery role", serviceDiscoveryRole: "Pod", err: true},
+		{name: "sharding labels", shards: 2, shardingLabels: []LabelName{"__meta_kubernetes_pod_name", "instance"}},
+		{name: "sharding labels without shards", shardingLabels: []LabelName{"instance"}, err: true},
+		{name: "sharding labels with a single shard", shards: 1, shardingLabels: []LabelName{"instance"}, err: true},
+		{name: "invalid sharding label", shards: 2, shardingLabels: []LabelName{"pod-name"}, err: true},
+		{name: "additional scrape configs from secret and inline", additionalScrapeConfigs: true, err: true},
+		{name: "valid config reloader watch interval", configReloaderWatchInterval: "1m"},
+		{name: "invalid config reloader watch interval", configReloaderWatchInterval: "1 minute", err: true},
+		{name: "zero config reloader watch interval", configReloaderWatchInterval: "0s", err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cpf := &CommonPrometheusFields{
+				ScrapeInterval: tc.scrapeInterval,
+				ScrapeTimeout:  tc.scrapeTimeout,
+			}
+			if tc.serviceDiscoveryRole != "" {
+				cpf.ServiceDiscoveryRole = &tc.serviceDiscoveryRole
+			}
+			if tc.terminationGracePeriod != 0 {
+				cpf.TerminationGracePeriodSeconds = &tc.terminationGracePeriod
+			}
+			if tc.shards != 0 {
+				cpf.Shards = &tc.shards
+			}
+			cpf.ShardingLabels = tc.shardingLabels
+			if tc.additionalScrapeConfigs {
+				cpf.AdditionalScrapeConfigs = &v1.SecretKeySelector{Key: "scrape-configs.yaml"}
+				cpf.AdditionalScrapeConfigsInline = new(string)
+			}
+			if tc.configReloaderWatchInterval != "" {
+				cpf.ConfigReloaderWatchInterval = &tc.configReloaderWatchInterval
+			}
+
+			err := cpf.Validate()
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePrometheusSpec(t *testing.T) {
+	durationPtr := func(d Duration) *Duration { return &d }
+
+	for _, tc := range []struct {
+		name               string
+		ruleQueryOffset    *Duration
+		evaluationInterval Duration
+		drainOnShutdown    bool
+		remoteWrite        []RemoteWriteSpec
+		retention          Duration
+		retentionSize      ByteSize
+		disableCompaction  bool
+		version            string
+		allowOverlapping   bool
+		outOfOrderWindow   Duration
+		err                bool
+		warning            bool
+	}{
+		{name: "no offset"},
+		{name: "offset lower than default interval", ruleQueryOffset: durationPtr("10s")},
+		{name: "offset equal to interval", ruleQueryOffset: durationPtr("1m"), evaluationInterval: "1m"},
+		{name: "offset greater than interval", ruleQueryOffset: durationPtr("2m"), evaluationInterval: "1m", warning: true},
+		{name: "offset greater than default interval", ruleQueryOffset: durationPtr("1m"), warning: true},
+		{name: "invalid offset", ruleQueryOffset: durationPtr("1 minute"), err: true},
+		{name: "drain on shutdown with remote write", drainOnShutdown: true, remoteWrite: []RemoteWriteSpec{{URL: "http://example.com"}}},
+		{name: "drain on shutdown without remote write", drainOnShutdown: true, warning: true},
+		{name: "valid retention", retention: "15d", retentionSize: "512MiB"},
+		{name: "invalid retention", retention: "15 days", err: true},
+		{name: "invalid retention size", retentionSize: "512M", err: true},
+		{name: "compaction disabled with retention", disableCompaction: true, retention: "6h"},
+		{name: "compaction disabled with retention size", disableCompaction: true, retentionSize: "1GB"},
+		{name: "compaction disabled with default retention", disableCompaction: true, warning: true},
+		{name: "overlapping blocks with supported version", allowOverlapping: true, version: "v2.38.0"},
+		{name: "overlapping blocks with default version", allowOverlapping: true, warning: true},
+		{name: "overlapping blocks always enabled", allowOverlapping: true, version: "v2.39.0", warning: true},
+		{name: "overlapping blocks removed", allowOverlapping: true, version: "v3.0.0", err: true},
+		{name: "overlapping blocks with invalid version", allowOverlapping: true, version: "latest", err: true},
+		{name: "out-of-order window", outOfOrderWindow: "10m", version: "v2.39.0"},
+		{name: "out-of-order window with default version", outOfOrderWindow: "10m"},
+		{name: "out-of-order window with unsupported version", outOfOrderWindow: "10m", version: "v2.38.0", warning: true},
+		{name: "out-of-order window with unsupported version and overlapping blocks", outOfOrderWindow: "10m", allowOverlapping: true, version: "v2.38.0", warning: true},
+		{name: "out-of-order window with invalid version", outOfOrderWindow: "10m", version: "latest", err: true},
+		{name: "invalid out-of-order window", outOfOrderWindow: "10 minutes", version: "v2.39.0", err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &PrometheusSpec{
+				RuleQueryOffset:    tc.ruleQueryOffset,
+				EvaluationInterval: tc.evaluationInterval,
+				Retention:          tc.retention,
+				RetentionSize:      tc.retentionSize,
+				DisableCompaction:  tc.disableCompaction,
+			}
+			spec.Version = tc.version
+			spec.AllowOverlappingBlocks = tc.allowOverlapping
+			spec.TSDB.OutOfOrderTimeWindow = tc.outOfOrderWindow
+			spec.RemoteWrite = tc.remoteWrite
+			if tc.drainOnShutdown {
+				spec.RemoteWriteDrainOnShutdown = &tc.drainOnShutdown
+			}
+
+			err := spec.Validate()
+			switch {
+			case tc.err:
+				if err == nil || IsValidationWarning(err) {
+					t.Fatalf("expected error but got: %v", err)
+				}
+			case tc.warning:
+				if !IsValidationWarning(err) {
+					t.Fatalf("expected warning but got: %v", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateReloadStrategy(t *testing.T) {
+	processSignal := ProcessSignalReloadStrategyType
+	httpStrategy := HTTPReloadStrategyType
+
+	for _, tc := range []struct {
+		name                 string
+		reloadStrategy       *ReloadStrategyType
+		skipInitConfigReload bool
+		containers           []v1.Container
+		initContainers       []v1.Container
+		err                  bool
+	}{
+		{
+			name: "default",
+			containers: []v1.Container{
+				{Name: "config-reloader", Args: []string{"--reload-url=http://localhost:9090/-/reload"}},
+			},
+		},
+		{
+			name:           "http with reload url override",
+			reloadStrategy: &httpStrategy,
+			containers: []v1.Container{
+				{Name: "config-reloader", Args: []string{"--reload-url=http://localhost:9090/-/reload"}},
+			},
+		},
+		{
+			name:           "process signal",
+			reloadStrategy: &processSignal,
+			containers: []v1.Container{
+				{Name: "prometheus", Command: []string{"/bin/prometheus"}},
+			},
+		},
+		{
+			name:           "process signal with reload url override",
+			reloadStrategy: &processSignal,
+			containers: []v1.Container{
+				{Name: "config-reloader", Args: []string{"--reload-url=http://localhost:9090/-/reload"}},
+			},
+			err: true,
+		},
+		{
+			name:           "process signal with custom executable",
+			reloadStrategy: &processSignal,
+			containers: []v1.Container{
+				{Name: "prometheus", Command: []string{"/bin/sh", "-c", "prometheus"}},
+			},
+			err: true,
+		},
+		{
+			name:                 "skip init config reload with process signal",
+			reloadStrategy:       &processSignal,
+			skipInitConfigReload: true,
+			initContainers: []v1.Container{
+				{Name: "init-config-reloader", Args: []string{"--log-level=debug"}},
+			},
+		},
+		{
+			name:                 "skip init config reload with custom init command",
+			skipInitConfigReload: true,
+			initContainers: []v1.Container{
+				{Name: "init-config-reloader", Command: []string{"/bin/true"}},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cpf := &CommonPrometheusFields{
+				ReloadStrategy:       tc.reloadStrategy,
+				SkipInitConfigReload: &tc.skipInitConfigReload,
+				Containers:           tc.containers,
+				InitContainers:       tc.initContainers,
+			}
+
+			err := cpf.ValidateReloadStrategy()
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestEffectiveListenAddress(t *testing.T) {
+	if got := (&CommonPrometheusFields{}).EffectiveListenAddress(); got != "0.0.0.0:9090" {
+		t.Fatalf("expected 0.0.0.0:9090, got %s", got)
+	}
+
+	if got := (&CommonPrometheusFields{ListenLocal: true, PortName: "http"}).EffectiveListenAddress(); got != "127.0.0.1:9090" {
+		t.Fatalf("expected 127.0.0.1:9090, got %s", got)
+	}
+
+	port := int32(8080)
+	if got := (&CommonPrometheusFields{Web: &PrometheusWebSpec{ListenPort: &port}}).EffectiveListenAddress(); got != "0.0.0.0:8080" {
+		t.Fatalf("expected 0.0.0.0:8080, got %s", got)
+	}
+}
+
+func TestContainerPorts(t *testing.T) {
+	port := int32(8080)
+	cpf := &CommonPrometheusFields{Web: &PrometheusWebSpec{ListenPort: &port}}
+
+	ports := cpf.ContainerPorts()
+	if len(ports) != 1 || ports[0].Name != "web" || ports[0].ContainerPort != 8080 {
+		t.Fatalf("unexpected container ports: %v", ports)
+	}
+
+	cpf.ListenLocal = true
+	if ports := cpf.ContainerPorts(); len(ports) != 0 {
+		t.Fatalf("expected no container ports, got %v", ports)
+	}
+}
+
+func TestValidatePrometheusWebSpec(t *testing.T) {
+	for _, tc := range []struct {
+		port int32
+		err  bool
+	}{
+		{port: 1},
+		{port: 65535},
+		{port: 0, err: true},
+		{port: 65536, err: true},
+	} {
+		t.Run(strconv.Itoa(int(tc.port)), func(t *testing.T) {
+			err := (&PrometheusWebSpec{ListenPort: &tc.port}).Validate()
+			if tc.err && err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateOAuth2TLSConfig(t *testing.T) {
+	oauth2 := OAuth2{
+		ClientID: SecretOrConfigMap{
+			Secret: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "oauth2"},
+				Key:                  "client_id",
+			},
+		},
+		TokenURL: "https://idp.example.com/token",
+		TLSConfig: &SafeTLSConfig{
+			CA: SecretOrConfigMap{
+				Secret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "idp"},
+					Key:                  "ca.crt",
+				},
+			},
+		},
+	}
+
+	if err := oauth2.Validate(); err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	oauth2.TLSConfig.Cert = SecretOrConfigMap{
+		Secret: &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "idp"},
+			Key:                  "tls.crt",
+		},
+	}
+
+	if err := oauth2.Validate(); err == nil {
+		t.Fatal("expected error for client cert without client key but got none")
+	}
+}
+
+func TestShardingRelabelConfigs(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		shardingLabels []LabelName
+		shardIndex     int32
+		totalShards    int32
+		expected       []*RelabelConfig
+	}{
+		{
+			name:        "single shard",
+			totalShards: 1,
+		},
+		{
+			name:        "default sharding label",
+			shardIndex:  1,
+			totalShards: 3,
+			expected: []*RelabelConfig{
+				{SourceLabels: []LabelName{"__address__"}, TargetLabel: "__tmp_hash", Modulus: 3, Action: "hashmod"},
+				{SourceLabels: []LabelName{"__tmp_hash"}, Regex: "1", Action: "keep"},
+			},
+		},
+		{
+			name:           "custom sharding labels",
+			shardingLabels: []LabelName{"namespace", "pod"},
+			shardIndex:     0,
+			totalShards:    2,
+			expected: []*RelabelConfig{
+				{SourceLabels: []LabelName{"namespace", "pod"}, TargetLabel: "__tmp_hash", Modulus: 2, Action: "hashmod"},
+				{SourceLabels: []LabelName{"__tmp_hash"}, Regex: "0", Action: "keep"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cpf := &CommonPrometheusFields{ShardingLabels: tc.shardingLabels}
+
+			got := cpf.ShardingRelabelConfigs(tc.shardIndex, tc.totalShards)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnsupportedFields(t *testing.T) {
+	offset := Duration("1m")
+	spec := &PrometheusSpec{
+		CommonPrometheusFields: CommonPrometheusFields{
+			EnforcedBodySizeLimit: "10MB",
+		},
+		RuleQueryOffset: &offset,
+	}
+
+	for _, tc := range []struct {
+		version  string
+		expected []FieldVersionRequirement
+	}{
+		{
+			version: "v2.27.0",
+			expected: []FieldVersionRequirement{
+				{Field: "enforcedBodySizeLimit", MinimumVersion: "2.28.0"},
+				{Field: "ruleQueryOffset", MinimumVersion: "2.53.0"},
+			},
+		},
+		{
+			version: "v2.28.0",
+			expected: []FieldVersionRequirement{
+				{Field: "ruleQueryOffset", MinimumVersion: "2.53.0"},
+			},
+		},
+		{
+			version: "v2.53.0",
+		},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			got, err := spec.UnsupportedFields(tc.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+
+	if _, err := spec.UnsupportedFields("latest"); err == nil {
+		t.Fatal("expected error for invalid version")
+	}
+}
+
+func TestByteSizeBytes(t *testing.T) {
+	for _, tc := range []struct {
+		size     ByteSize
+		expected int64
+		err      bool
+	}{
+		{size: "0", expected: 0},
+		{size: "0B", expected: 0},
+		{size: "512B", expected: 512},
+		{size: "1KB", expected: 1 << 10},
+		{size: "1KiB", expected: 1 << 10},
+		{size: "1.5KB", expected: 1536},
+		{size: ".5MB", expected: 1 << 19},
+		{size: "512MB", expected: 512 << 20},
+		{size: "512MiB", expected: 512 << 20},
+		{size: "2GB", expected: 2 << 30},
+		{size: "2GiB", expected: 2 << 30},
+		{size: "3TB", expected: 3 << 40},
+		{size: "3TiB", expected: 3 << 40},
+		{size: "4PB", expected: 4 << 50},
+		{size: "4PiB", expected: 4 << 50},
+		{size: "1EB", expected: 1 << 60},
+		{size: "1EiB", expected: 1 << 60},
+		{size: "8EiB", err: true},
+		{size: "", err: true},
+		{size: "1", err: true},
+		{size: "1K", err: true},
+		{size: "1kB", err: true},
+		{size: "1ZB", err: true},
+		{size: "-1MB", err: true},
+		{size: "1..5MB", err: true},
+	} {
+		t.Run(string(tc.size), func(t *testing.T) {
+			got, err := tc.size.Bytes()
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %d", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.expected {
+				t.Fatalf("expected %d bytes, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateRemoteWrites(t *testing.T) {
+	enabled, disabled := true, false
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		rws      []RemoteWriteSpec
+		expected []string
+	}{
+		{
+			name: "valid",
+			rws: []RemoteWriteSpec{
+				{URL: "http://example.com", Name: "a"},
+				{URL: "https://example.com/api/v1/write", Name: "b"},
+			},
+		},
+		{
+			name: "invalid urls",
+			rws: []RemoteWriteSpec{
+				{Name: "a"},
+				{URL: "example.com", Name: "b"},
+				{URL: "ftp://example.com", Name: "c"},
+			},
+			expected: []string{
+				"spec.remoteWrite[0].url",
+				"spec.remoteWrite[1].url",
+				"spec.remoteWrite[2].url",
+			},
+		},
+		{
+			name: "urls",
+			rws: []RemoteWriteSpec{
+				{URLs: []string{"http://example.com/0", "example.com", "http://example.com/0"}, Name: "a"},
+				{URL: "http://example.com", URLs: []string{"http://example.com/1"}, Name: "b"},
+			},
+			expected: []string{
+				"spec.remoteWrite[0].urls[1]",
+				"spec.remoteWrite[0].urls[2]",
+				"spec.remoteWrite[1]",
+			},
+		},
+		{
+			name: "duplicate names",
+			rws: []RemoteWriteSpec{
+				{URL: "http://example.com", Name: "a"},
+				{URL: "http://example.com", Name: "a"},
+			},
+			expected: []string{"spec.remoteWrite[1].name"},
+		},
+		{
+			name: "multiple problems",
+			rws: []RemoteWriteSpec{
+				{
+					URL:           "http://example.com",
+					BasicAuth:     &BasicAuth{},
+					Authorization: &Authorization{},
+					RemoteTimeout: "1x",
+					QueueConfig:   &QueueConfig{Capacity: -1},
+					WriteRelabelConfigs: []RelabelConfig{
+						{Action: "keep"},
+						{Regex: "("},
+					},
+				},
+			},
+			expected: []string{
+				"spec.remoteWrite[0]",
+				"spec.remoteWrite[0].basicAuth",
+				"spec.remoteWrite[0].remoteTimeout",
+				"spec.remoteWrite[0].queueConfig",
+				"spec.remoteWrite[0].writeRelabelConfigs[1]",
+			},
+		},
+		{
+			name: "missing names",
+			rws: []RemoteWriteSpec{
+				{URL: "http://example.com", Name: "a"},
+				{URL: "http://example.com"},
+			},
+			expected: []string{"spec.remoteWrite[1].name"},
+		},
+		{
+			name: "single entry without name",
+			rws: []RemoteWriteSpec{
+				{URL: "http://example.com"},
+			},
+		},
+		{
+			name: "disabled entry without name",
+			rws: []RemoteWriteSpec{
+				{URL: "http://example.com", Enabled: &disabled},
+			},
+			expected: []string{"spec.remoteWrite[0].name"},
+		},
+		{
+			name:    "version-gated fields",
+			version: "v2.25.0",
+			rws: []RemoteWriteSpec{
+				{
+					URL:         "http://example.com",
+					Name:        "a",
+					Headers:     map[string]string{"foo": "bar"},
+					EnableHTTP2: &enabled,
+					QueueConfig: &QueueConfig{RetryOnRateLimit: true},
+				},
+			},
+			expected: []string{
+				"spec.remoteWrite[0].enableHTTP2",
+				"spec.remoteWrite[0].queueConfig.retryOnRateLimit",
+			},
+		},
+		{
+			name:    "invalid version",
+			version: "latest",
+			rws: []RemoteWriteSpec{
+				{URL: "http://example.com", Name: "a"},
+			},
+			expected: []string{"spec.version"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cpf := &CommonPrometheusFields{
+				Version:     tc.version,
+				RemoteWrite: tc.rws,
+			}
+
+			var got []string
+			for _, err := range cpf.ValidateRemoteWrites() {
+				got = append(got, err.Field)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected errors for %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDurationParse(t *testing.T) {
+	for _, tc := range []struct {
+		d        Duration
+		expected time.Duration
+		err      bool
+	}{
+		{d: "0", expected: 0},
+		{d: "30s", expected: 30 * time.Second},
+		{d: "500ms", expected: 500 * time.Millisecond},
+		{d: "1h20m15s", expected: time.Hour + 20*time.Minute + 15*time.Second},
+		{d: "1d", expected: 24 * time.Hour},
+		{d: "2w", expected: 14 * 24 * time.Hour},
+		{d: "1y", expected: 365 * 24 * time.Hour},
+		{d: "1y2w3d4h5m6s7ms", expected: 365*24*time.Hour + 14*24*time.Hour + 3*24*time.Hour + 4*time.Hour + 5*time.Minute + 6*time.Second + 7*time.Millisecond},
+		{d: "", err: true},
+		{d: "1", err: true},
+		{d: "1.5h", err: true},
+		{d: "5m1h", err: true},
+		{d: "-1m", err: true},
+	} {
+		t.Run(string(tc.d), func(t *testing.T) {
+			got, err := tc.d.Parse()
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDurationLessThan(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     Duration
+		expected bool
+		err      bool
+	}{
+		{a: "10s", b: "1m", expected: true},
+		{a: "1m", b: "60s", expected: false},
+		{a: "1h", b: "59m59s", expected: false},
+		{a: "6d", b: "1w", expected: true},
+		{a: "1x", b: "1m", err: true},
+		{a: "1m", b: "", err: true},
+	} {
+		t.Run(fmt.Sprintf("%s<%s", tc.a, tc.b), func(t *testing.T) {
+			got, err := tc.a.LessThan(tc.b)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateTLSConfigVersions(t *testing.T) {
+	tls10, tls13, unknown := TLSVersion10, TLSVersion13, TLSVersion("SSL3")
+
+	for _, tc := range []struct {
+		name string
+		min  *TLSVersion
+		max  *TLSVersion
+		err  bool
+	}{
+		{name: "none"},
+		{name: "min only", min: &tls13},
+		{name: "max only", max: &tls10},
+		{name: "valid range", min: &tls10, max: &tls13},
+		{name: "same version", min: &tls13, max: &tls13},
+		{name: "inverted range", min: &tls13, max: &tls10, err: true},
+		{name: "unknown min", min: &unknown, err: true},
+		{name: "unknown max", max: &unknown, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			safe := &SafeTLSConfig{MinVersion: tc.min, MaxVersion: tc.max}
+			full := &TLSConfig{SafeTLSConfig: *safe}
+
+			for _, err := range []error{safe.Validate(), full.Validate()} {
+				if tc.err {
+					if err == nil {
+						t.Fatal("expected error, got none")
+					}
+					continue
+				}
+
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestDurationNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		d        Duration
+		expected Duration
+		err      bool
+	}{
+		{d: "0", expected: "0s"},
+		{d: "0s", expected: "0s"},
+		{d: "30s", expected: "30s"},
+		{d: "60s", expected: "1m"},
+		{d: "90m", expected: "1h30m"},
+		{d: "05m", expected: "5m"},
+		{d: "1h20m15s", expected: "1h20m15s"},
+		{d: "14d", expected: "2w"},
+		{d: "366d", expected: "1y1d"},
+		{d: "1500ms", expected: "1s500ms"},
+		{d: "1x", err: true},
+	} {
+		t.Run(string(tc.d), func(t *testing.T) {
+			got, err := tc.d.Normalize()
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+
+			// The normalized duration must be equal to the original one.
+			a, _ := tc.d.Parse()
+			b, _ := got.Parse()
+			if a != b {
+				t.Fatalf("expected %v, got %v", a, b)
+			}
+		})
+	}
+}
+
+func TestByteSizeAndDurationYAML(t *testing.T) {
+	unmarshalString := func(s string) func(interface{}) error {
+		return func(v interface{}) error {
+			*(v.(*string)) = s
+			return nil
+		}
+	}
+
+	for _, tc := range []struct {
+		in       string
+		expected string
+	}{
+		{in: "0", expected: "0"},
+		{in: "30s", expected: "30s"},
+		{in: "100s", expected: "100s"},
+		{in: "05m", expected: "5m"},
+		{in: "1h05m00s", expected: "1h5m0s"},
+		{in: "invalid", expected: "invalid"},
+	} {
+		t.Run("duration/"+tc.in, func(t *testing.T) {
+			var d Duration
+			if err := d.UnmarshalYAML(unmarshalString(tc.in)); err != nil {
+				t.Fatal(err)
+			}
+
+			if string(d) != tc.expected {
+				t.Fatalf("expected %q after unmarshaling, got %q", tc.expected, d)
+			}
+
+			out, err := Duration(tc.in).MarshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if out != tc.expected {
+				t.Fatalf("expected %q after marshaling, got %q", tc.expected, out)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		in       string
+		expected string
+	}{
+		{in: "0", expected: "0"},
+		{in: "512MB", expected: "512MB"},
+		{in: "0512MB", expected: "512MB"},
+		{in: "0.5GiB", expected: "0.5GiB"},
+		{in: "00.5GiB", expected: "0.5GiB"},
+		{in: "1.05KB", expected: "1.05KB"},
+		{in: "invalid", expected: "invalid"},
+	} {
+		t.Run("bytesize/"+tc.in, func(t *testing.T) {
+			var bs ByteSize
+			if err := bs.UnmarshalYAML(unmarshalString(tc.in)); err != nil {
+				t.Fatal(err)
+			}
+
+			if string(bs) != tc.expected {
+				t.Fatalf("expected %q after unmarshaling, got %q", tc.expected, bs)
+			}
+
+			out, err := ByteSize(tc.in).MarshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if out != tc.expected {
+				t.Fatalf("expected %q after marshaling, got %q", tc.expected, out)
+			}
+		})
+	}
+}
+
+func TestValidateProbeTargetStaticConfigListsAllErrors(t *testing.T) {
+	sc := &ProbeTargetStaticConfig{
+		Targets:        []string{"", "example.com"},
+		Labels:         map[string]string{"app-name": "foo", "valid": "bar", "1st": "baz"},
+		RelabelConfigs: []*RelabelConfig{nil},
+	}
+
+	err := sc.Validate()
+
+	var pErr *ProbeTargetsValidationError
+	if !errors.As(err, &pErr) {
+		t.Fatalf("expected *ProbeTargetsValidationError, got %v", err)
+	}
+
+	expected := `.spec.targets.staticConfig.static[0] must not be empty; ` +
+		`.spec.targets.staticConfig.labels: invalid label name "1st"; ` +
+		`.spec.targets.staticConfig.labels: invalid label name "app-name"; ` +
+		`.spec.targets.staticConfig.relabelingConfigs[0] must not be null`
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestSafeAuthorizationEffectiveType(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		typ      string
+		expected string
+	}{
+		{name: "empty", typ: "", expected: "Bearer"},
+		{name: "bearer", typ: "Bearer", expected: "Bearer"},
+		{name: "lower-case", typ: "bearer", expected: "bearer"},
+		{name: "upper-case", typ: "BEARER", expected: "BEARER"},
+		{name: "custom", typ: "Digest", expected: "Digest"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &SafeAuthorization{Type: tc.typ}
+			if got := c.EffectiveType(); got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateWebConfigFileFields(t *testing.T) {
+	enabled, disabled := true, false
+	tlsConfig := &WebTLSConfig{
+		Cert: SecretOrConfigMap{
+			Secret: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "tls"},
+				Key:                  "tls.crt",
+			},
+		},
+		KeySecret: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "tls"},
+			Key:                  "tls.key",
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		fields WebConfigFileFields
+		err    bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "http2 disabled without TLS",
+			fields: WebConfigFileFields{
+				HTTPConfig: &WebHTTPConfig{HTTP2: &disabled},
+			},
+		},
+		{
+			name: "http2 enabled with TLS",
+			fields: WebConfigFileFields{
+				TLSConfig:  tlsConfig,
+				HTTPConfig: &WebHTTPConfig{HTTP2: &enabled},
+			},
+		},
+		{
+			name: "http2 enabled without TLS",
+			fields: WebConfigFileFields{
+				HTTPConfig: &WebHTTPConfig{HTTP2: &enabled},
+			},
+			err: true,
+		},
+		{
+			name: "invalid TLS config",
+			fields: WebConfigFileFields{
+				TLSConfig: &WebTLSConfig{},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fields.Validate()
+			if tc.err && err == nil {
+				t.Fatal("expected error, got none")
+			}
+
+			if !tc.err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveAuth(t *testing.T) {
+	secret := v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+		Key:                  "token",
+	}
+
+	for _, tc := range []struct {
+		name     string
+		resolve  func() (string, error)
+		expected string
+		err      bool
+	}{
+		{
+			name:    "endpoint without auth",
+			resolve: (&Endpoint{}).ResolveAuth,
+		},
+		{
+			name:     "endpoint with bearer token file",
+			resolve:  (&Endpoint{BearerTokenFile: "/etc/token"}).ResolveAuth,
+			expected: "bearerTokenFile",
+		},
+		{
+			name:     "endpoint with projected token",
+			resolve:  (&Endpoint{BearerTokenProjected: &ProjectedToken{Audience: "oidc"}}).ResolveAuth,
+			expected: "bearerTokenProjected",
+		},
+		{
+			name:    "endpoint with basic auth and authorization",
+			resolve: (&Endpoint{BasicAuth: &BasicAuth{}, Authorization: &SafeAuthorization{}}).ResolveAuth,
+			err:     true,
+		},
+		{
+			name:    "endpoint with projected token and bearer token secret",
+			resolve: (&Endpoint{BearerTokenProjected: &ProjectedToken{}, BearerTokenSecret: secret}).ResolveAuth,
+			err:     true,
+		},
+		{
+			name:     "pod metrics endpoint with oauth2",
+			resolve:  (&PodMetricsEndpoint{OAuth2: &OAuth2{}}).ResolveAuth,
+			expected: "oauth2",
+		},
+		{
+			name:    "pod metrics endpoint with bearer token secret and oauth2",
+			resolve: (&PodMetricsEndpoint{BearerTokenSecret: secret, OAuth2: &OAuth2{}}).ResolveAuth,
+			err:     true,
+		},
+		{
+			name:     "probe with bearer token secret",
+			resolve:  (&ProbeSpec{BearerTokenSecret: secret}).ResolveAuth,
+			expected: "bearerTokenSecret",
+		},
+		{
+			name:    "probe with basic auth and oauth2",
+			resolve: (&ProbeSpec{BasicAuth: &BasicAuth{}, OAuth2: &OAuth2{}}).ResolveAuth,
+			err:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.resolve()
+			if tc.err {
+				var aErr *AuthConflictError
+				if !errors.As(err, &aErr) {
+					t.Fatalf("expected *AuthConflictError, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateNamespaceSelector(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		nsel    NamespaceSelector
+		err     bool
+		warning bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "match labels and expressions",
+			nsel: NamespaceSelector{
+				MatchLabels: map[string]string{"tenant": "true"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+				},
+			},
+		},
+		{
+			name: "empty namespace name",
+			nsel: NamespaceSelector{MatchNames: []string{"foo", ""}},
+			err:  true,
+		},
+		{
+			name: "invalid match expression",
+			nsel: NamespaceSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "team", Operator: metav1.LabelSelectorOpExists, Values: []string{"a"}},
+				},
+			},
+			err: true,
+		},
+		{
+			name:    "any with match names",
+			nsel:    NamespaceSelector{Any: true, MatchNames: []string{"foo"}},
+			warning: true,
+		},
+		{
+			name:    "any with match labels",
+			nsel:    NamespaceSelector{Any: true, MatchLabels: map[string]string{"tenant": "true"}},
+			warning: true,
+		},
+		{
+			name:    "match labels with match names",
+			nsel:    NamespaceSelector{MatchNames: []string{"foo"}, MatchLabels: map[string]string{"tenant": "true"}},
+			warning: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.nsel.Validate()
+			switch {
+			case tc.err:
+				if err == nil || IsValidationWarning(err) {
+					t.Fatalf("expected error, got %v", err)
+				}
+			case tc.warning:
+				if !IsValidationWarning(err) {
+					t.Fatalf("expected warning, got %v", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleValidateMetricName(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		rule Rule
+		err  bool
+	}{
+		{name: "alerting rule", rule: Rule{Alert: "High-Error-Rate"}},
+		{name: "simple metric name", rule: Rule{Record: "http_requests_total"}},
+		{name: "recording rule convention", rule: Rule{Record: "my:metric:rate"}},
+		{name: "leading colon", rule: Rule{Record: ":metric"}},
+		{name: "dash", rule: Rule{Record: "my-metric"}, err: true},
+		{name: "leading digit", rule: Rule{Record: "5xx_rate"}, err: true},
+		{name: "dot", rule: Rule{Record: "job.rate5m"}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.ValidateMetricName()
+			if !tc.err {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !strings.Contains(err.Error(), strconv.Quote(tc.rule.Record)) {
+				t.Fatalf("expected error to contain the offending name %q, got %q", tc.rule.Record, err)
+			}
+		})
+	}
+}
+
+func TestDuplicateAlertNames(t *testing.T) {
+	spec := PrometheusRuleSpec{
+		Groups: []RuleGroup{
+			{
+				Name: "group1",
+				Rules: []Rule{
+					{Alert: "HighErrorRate", Labels: map[string]string{"severity": "warning"}},
+					{Alert: "HighErrorRate", Labels: map[string]string{"severity": "critical"}},
+					{Alert: "TargetDown"},
+					{Record: "job:up:sum"},
+				},
+			},
+			{
+				Name: "group2",
+				Rules: []Rule{
+					{Alert: "TargetDown"},
+					{Alert: "InstanceDown"},
+					{Record: "job:up:sum"},
+				},
+			},
+			{
+				Name: "group3",
+				Rules: []Rule{
+					{Alert: "InstanceDown"},
+				},
+			},
+		},
+	}
+
+	expected := []string{"InstanceDown", "TargetDown"}
+	if got := spec.DuplicateAlertNames(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if got := (&PrometheusRuleSpec{}).DuplicateAlertNames(); len(got) != 0 {
+		t.Fatalf("expected no duplicates, got %v", got)
+	}
+}
+
+func TestRuleGroupEffectiveInterval(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		group    RuleGroup
+		global   Duration
+		expected Duration
+	}{
+		{name: "default", expected: "30s"},
+		{name: "global", global: "1m", expected: "1m"},
+		{name: "group override", group: RuleGroup{Interval: "15s"}, global: "1m", expected: "15s"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.group.EffectiveInterval(tc.global); got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateRuleIntervals(t *testing.T) {
+	for _, tc := range []struct {
+		name               string
+		groups             []RuleGroup
+		evaluationInterval Duration
+		scrapeInterval     Duration
+		err                bool
+		warnings           int
+	}{
+		{
+			name:   "defaults",
+			groups: []RuleGroup{{Name: "group1"}},
+		},
+		{
+			name:           "interval equal to the scrape interval",
+			groups:         []RuleGroup{{Name: "group1", Interval: "1m"}},
+			scrapeInterval: "1m",
+		},
+		{
+			name:               "group interval much smaller than the scrape interval",
+			groups:             []RuleGroup{{Name: "group1", Interval: "15s"}, {Name: "group2"}, {Name: "group3", Interval: "10s"}},
+			evaluationInterval: "1m",
+			scrapeInterval:     "1m",
+			warnings:           2,
+		},
+		{
+			name:               "global interval much smaller than the scrape interval",
+			groups:             []RuleGroup{{Name: "group1"}, {Name: "group2", Interval: "1m"}},
+			evaluationInterval: "10s",
+			scrapeInterval:     "1m",
+			warnings:           1,
+		},
+		{
+			name:           "invalid scrape interval",
+			groups:         []RuleGroup{{Name: "group1"}},
+			scrapeInterval: "1 minute",
+			err:            true,
+		},
+		{
+			name:   "invalid group interval",
+			groups: []RuleGroup{{Name: "group1", Interval: "1 minute"}},
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &PrometheusRuleSpec{Groups: tc.groups}
+			err := spec.ValidateIntervals(tc.evaluationInterval, tc.scrapeInterval)
+			switch {
+			case tc.err:
+				if err == nil || IsValidationWarning(err) {
+					t.Fatalf("expected error but got: %v", err)
+				}
+			case tc.warnings > 0:
+				var w *ValidationWarning
+				if !errors.As(err, &w) {
+					t.Fatalf("expected warning but got: %v", err)
+				}
+				if len(w.Warnings()) != tc.warnings {
+					t.Fatalf("expected %d warnings, got %v", tc.warnings, w.Warnings())
+				}
+			default:
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRuleTestGroup(t *testing.T) {
+	interval := Duration("1m")
+	invalidInterval := Duration("1 minute")
+
+	for _, tc := range []struct {
+		name     string
+		group    RuleTestGroup
+		expected []string
+	}{
+		{
+			name: "valid",
+			group: RuleTestGroup{
+				Interval: &interval,
+				InputSeries: []RuleTestSeries{
+					{Series: `up{job="node"}`, Values: "1 1 0 0"},
+				},
+				AlertRuleTests: []AlertRuleTestCase{
+					{
+						EvalTime:  "3m",
+						AlertName: "Down",
+						ExpAlerts: []ExpectedAlert{
+							{
+								ExpLabels:      map[string]string{"job": "node", "severity": "critical"},
+								ExpAnnotations: map[string]string{"summary": "node is down"},
+							},
+						},
+					},
+				},
+				PromQLExprTests: []PromQLExprTestCase{
+					{
+						Expr:       "avg(up)",
+						EvalTime:   "2m",
+						ExpSamples: []ExpectedSample{{Value: "0.5"}, {Labels: `{job="node"}`, Value: "NaN"}},
+					},
+				},
+				ExternalLabels: map[string]string{"cluster": "eu1"},
+			},
+		},
+		{
+			name: "invalid",
+			group: RuleTestGroup{
+				Interval: &invalidInterval,
+				InputSeries: []RuleTestSeries{
+					{Series: " ", Values: ""},
+				},
+				AlertRuleTests: []AlertRuleTestCase{
+					{
+						EvalTime: "3 minutes",
+						ExpAlerts: []ExpectedAlert{
+							{ExpLabels: map[string]string{"job-name": "node"}},
+						},
+					},
+				},
+				PromQLExprTests: []PromQLExprTestCase{
+					{
+						EvalTime:   "2m",
+						ExpSamples: []ExpectedSample{{Value: "half"}},
+					},
+				},
+				ExternalLabels: map[string]string{"0cluster": "eu1"},
+			},
+			expected: []string{
+				"interval",
+				"input_series[0].series",
+				"input_series[0].values",
+				"alert_rule_test[0].eval_time",
+				"alert_rule_test[0].alertname",
+				"alert_rule_test[0].exp_alerts[0].exp_labels[job-name]",
+				"promql_expr_test[0].expr",
+				"promql_expr_test[0].exp_samples[0].value",
+				"external_labels[0cluster]",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var fields []string
+			for _, e := range tc.group.Validate() {
+				fields = append(fields, e.Field)
+			}
+
+			if !reflect.DeepEqual(fields, tc.expected) {
+				t.Fatalf("expected errors for %v, got %v", tc.expected, fields)
+			}
+		})
+	}
+
+	spec := PrometheusRuleSpec{Tests: []RuleTestGroup{{}, {InputSeries: []RuleTestSeries{{Values: "1"}}}}}
+	verr, ok := spec.Validate().(*PrometheusRuleValidationError)
+	if !ok {
+		t.Fatalf("expected *PrometheusRuleValidationError, got %v", spec.Validate())
+	}
+	if len(verr.Errors()) != 1 || verr.Errors()[0].Field != "tests[1].input_series[0].series" {
+		t.Fatalf("expected an error for tests[1].input_series[0].series, got %v", verr.Errors())
+	}
+}
+
+func TestSelectorLabels(t *testing.T) {
+	p := &Prometheus{ObjectMeta: metav1.ObjectMeta{Name: "k8s", Namespace: "monitoring"}}
+	am := &Alertmanager{ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"}}
+
+	for _, tc := range []struct {
+		name     string
+		got      map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "prometheus",
+			got:  p.SelectorLabels(),
+			expected: map[string]string{
+				"app.kubernetes.io/name":       "prometheus",
+				"app.kubernetes.io/managed-by": "prometheus-operator",
+				"app.kubernetes.io/instance":   "k8s",
+				"prometheus":                   "k8s",
+				"operator.prometheus.io/name":  "k8s",
+			},
+		},
+		{
+			name: "prometheus shard 0",
+			got:  p.ShardSelectorLabels(0),
+			expected: map[string]string{
+				"app.kubernetes.io/name":       "prometheus",
+				"app.kubernetes.io/managed-by": "prometheus-operator",
+				"app.kubernetes.io/instance":   "k8s",
+				"prometheus":                   "k8s",
+				"operator.prometheus.io/name":  "k8s",
+				"operator.prometheus.io/shard": "0",
+			},
+		},
+		{
+			name: "prometheus shard 2",
+			got:  p.ShardSelectorLabels(2),
+			expected: map[string]string{
+				"app.kubernetes.io/name":       "prometheus",
+				"app.kubernetes.io/managed-by": "prometheus-operator",
+				"app.kubernetes.io/instance":   "k8s",
+				"prometheus":                   "k8s",
+				"operator.prometheus.io/name":  "k8s",
+				"operator.prometheus.io/shard": "2",
+			},
+		},
+		{
+			name: "alertmanager",
+			got:  am.SelectorLabels(),
+			expected: map[string]string{
+				"app.kubernetes.io/name":       "alertmanager",
+				"app.kubernetes.io/managed-by": "prometheus-operator",
+				"app.kubernetes.io/instance":   "main",
+				"alertmanager":                 "main",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, tc.got)
+			}
+		})
+	}
+
+	// ShardSelectorLabels must not modify the labels returned by
+	// SelectorLabels.
+	if _, found := p.SelectorLabels()[PrometheusShardLabelName]; found {
+		t.Fatalf("expected no %q label in SelectorLabels()", PrometheusShardLabelName)
+	}
+}
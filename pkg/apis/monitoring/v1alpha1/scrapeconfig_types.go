@@ -0,0 +1,302 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	ScrapeConfigsKind   = "ScrapeConfig"
+	ScrapeConfigName    = "scrapeconfigs"
+	ScrapeConfigKindKey = "scrapeconfig"
+)
+
+// Target represents a target for Prometheus to scrape.
+// +kubebuilder:validation:MinLength:=1
+type Target string
+
+// SDFile represents a file used for service discovery.
+// +kubebuilder:validation:Pattern=`^[^*]*(\*[^/]*)?\.(json|yml|yaml|JSON|YML|YAML)$`
+type SDFile string
+
+// KubernetesRole is the role of the Kubernetes objects discovered by the
+// Kubernetes service discovery.
+// +kubebuilder:validation:Enum=Pod;Endpoints;EndpointSlice;Service;Node;Ingress
+type KubernetesRole string
+
+const (
+	KubernetesRolePod           KubernetesRole = "Pod"
+	KubernetesRoleEndpoints     KubernetesRole = "Endpoints"
+	KubernetesRoleEndpointSlice KubernetesRole = "EndpointSlice"
+	KubernetesRoleService       KubernetesRole = "Service"
+	KubernetesRoleNode          KubernetesRole = "Node"
+	KubernetesRoleIngress       KubernetesRole = "Ingress"
+)
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories="prometheus-operator",shortName="scfg"
+
+// ScrapeConfig defines a namespaced Prometheus scrape_config to be aggregated
+// across multiple namespaces into the Prometheus configuration.
+type ScrapeConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScrapeConfigSpec `json:"spec"`
+}
+
+// ScrapeConfigList is a list of ScrapeConfig.
+// +k8s:openapi-gen=true
+type ScrapeConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of ScrapeConfigs
+	Items []*ScrapeConfig `json:"items"`
+}
+
+// ScrapeConfigSpec is a specification of the desired configuration for a scrape configuration.
+type ScrapeConfigSpec struct {
+	// StaticConfigs defines a list of static targets with a common label set.
+	// +optional
+	StaticConfigs []StaticConfig `json:"staticConfigs,omitempty"`
+	// FileSDConfigs defines a list of file service discovery configurations.
+	// +optional
+	FileSDConfigs []FileSDConfig `json:"fileSDConfigs,omitempty"`
+	// HTTPSDConfigs defines a list of HTTP service discovery configurations.
+	// +optional
+	HTTPSDConfigs []HTTPSDConfig `json:"httpSDConfigs,omitempty"`
+	// KubernetesSDConfigs defines a list of Kubernetes service discovery configurations.
+	// +optional
+	KubernetesSDConfigs []KubernetesSDConfig `json:"kubernetesSDConfigs,omitempty"`
+	// ConsulSDConfigs defines a list of Consul service discovery configurations.
+	// +optional
+	ConsulSDConfigs []ConsulSDConfig `json:"consulSDConfigs,omitempty"`
+	// RelabelConfigs defines how to rewrite the target's labels before scraping.
+	// Prometheus Operator automatically adds relabelings for a few standard Kubernetes fields.
+	// The original scrape job's name is available via the `__tmp_prometheus_job_name` label.
+	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
+	// +optional
+	RelabelConfigs []*monitoringv1.RelabelConfig `json:"relabelings,omitempty"`
+	// MetricsPath HTTP path to scrape for metrics. If empty, Prometheus uses the default value (e.g. /metrics).
+	// +optional
+	MetricsPath *string `json:"metricsPath,omitempty"`
+	// ScrapeInterval is the interval between consecutive scrapes.
+	// If not specified, the Prometheus global scrape interval is used.
+	// +optional
+	ScrapeInterval *monitoringv1.Duration `json:"scrapeInterval,omitempty"`
+	// ScrapeTimeout is the number of seconds to wait until a scrape request times out.
+	// If not specified, the Prometheus global scrape timeout is used.
+	// +optional
+	ScrapeTimeout *monitoringv1.Duration `json:"scrapeTimeout,omitempty"`
+	// Configures the protocol scheme used for requests.
+	// If empty, Prometheus uses HTTP by default.
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +optional
+	Scheme *string `json:"scheme,omitempty"`
+	// BasicAuth information to use on every scrape request.
+	// +optional
+	BasicAuth *monitoringv1.BasicAuth `json:"basicAuth,omitempty"`
+	// Authorization header to use on every scrape request.
+	// +optional
+	Authorization *monitoringv1.SafeAuthorization `json:"authorization,omitempty"`
+	// TLS configuration to use on every scrape request
+	// +optional
+	TLSConfig *monitoringv1.SafeTLSConfig `json:"tlsConfig,omitempty"`
+	// MetricRelabelConfigs to apply to samples before ingestion.
+	// +optional
+	MetricRelabelConfigs []*monitoringv1.RelabelConfig `json:"metricRelabelings,omitempty"`
+}
+
+// StaticConfig defines a Prometheus static configuration.
+// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#static_config
+// +k8s:openapi-gen=true
+type StaticConfig struct {
+	// List of targets for this static configuration.
+	// +optional
+	Targets []Target `json:"targets,omitempty"`
+	// Labels assigned to all metrics scraped from the targets.
+	// +mapType:=atomic
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// FileSDConfig defines a Prometheus file service discovery configuration.
+// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#file_sd_config
+// +k8s:openapi-gen=true
+type FileSDConfig struct {
+	// List of files to be used for file discovery. Recommendation: use absolute paths. While relative paths work, the
+	// prometheus-operator project makes no guarantees about the working directory where the configuration file is
+	// stored.
+	// Files must be mounted using Prometheus.ConfigMaps or Prometheus.Secrets.
+	// +kubebuilder:validation:MinItems:=1
+	Files []SDFile `json:"files"`
+	// RefreshInterval configures the refresh interval at which Prometheus will reload the content of the files.
+	// +optional
+	RefreshInterval *monitoringv1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// HTTPSDConfig defines a Prometheus HTTP service discovery configuration.
+// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#http_sd_config
+// +k8s:openapi-gen=true
+type HTTPSDConfig struct {
+	// URL from which the targets are fetched.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:Pattern:="^http(s)?://.+$"
+	URL string `json:"url"`
+	// RefreshInterval configures the refresh interval at which Prometheus will re-query the
+	// endpoint to update the target list.
+	// +optional
+	RefreshInterval *monitoringv1.Duration `json:"refreshInterval,omitempty"`
+	// BasicAuth information to authenticate against the target HTTP endpoint.
+	// +optional
+	BasicAuth *monitoringv1.BasicAuth `json:"basicAuth,omitempty"`
+	// Authorization header configuration to authenticate against the target HTTP endpoint.
+	// +optional
+	Authorization *monitoringv1.SafeAuthorization `json:"authorization,omitempty"`
+	// TLS configuration applying to the target HTTP endpoint.
+	// +optional
+	TLSConfig *monitoringv1.SafeTLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// KubernetesSDConfig defines a Prometheus Kubernetes service discovery configuration.
+// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#kubernetes_sd_config
+// +k8s:openapi-gen=true
+type KubernetesSDConfig struct {
+	// Role of the Kubernetes entities that should be discovered.
+	// The `EndpointSlice` role requires Prometheus >= v2.21.0.
+	// +required
+	Role KubernetesRole `json:"role"`
+	// NamespaceSelector selects the namespaces in which the targets are discovered.
+	// If empty, only the namespace of the ScrapeConfig object is used.
+	// +optional
+	NamespaceSelector monitoringv1.NamespaceSelector `json:"namespaceSelector,omitempty"`
+	// Selectors to restrict the discovered objects with label and field
+	// selectors. The roles of the selectors must be compatible with the
+	// discovery role (see the Prometheus documentation).
+	// +optional
+	Selectors []K8SSelectorConfig `json:"selectors,omitempty"`
+}
+
+// K8SSelectorConfig restricts the Kubernetes objects of a given role
+// discovered by the Kubernetes service discovery.
+// +k8s:openapi-gen=true
+type K8SSelectorConfig struct {
+	// Role of the Kubernetes entities to which the selectors apply.
+	// +required
+	Role KubernetesRole `json:"role"`
+	// Label selector, using the Kubernetes label selector syntax
+	// (e.g. "app=foo,tier!=frontend").
+	// +optional
+	Label string `json:"label,omitempty"`
+	// Field selector, using the Kubernetes field selector syntax
+	// (e.g. "metadata.name=foo").
+	// +optional
+	Field string `json:"field,omitempty"`
+}
+
+// ConsulSDConfig defines a Prometheus Consul service discovery configuration.
+// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#consul_sd_config
+// +k8s:openapi-gen=true
+type ConsulSDConfig struct {
+	// Address of the Consul server, made of a host name or IP address
+	// followed by an optional port number.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Server string `json:"server"`
+	// Consul ACL token. If not provided, the ACL token of the local Consul
+	// agent is used.
+	// It can't be set at the same time as `authorization` or `oauth2`.
+	// +optional
+	TokenRef *v1.SecretKeySelector `json:"tokenRef,omitempty"`
+	// Consul datacenter. If not provided, the datacenter of the local Consul
+	// agent is used.
+	// +optional
+	Datacenter *string `json:"datacenter,omitempty"`
+	// Consul namespace. Namespaces are only supported by Consul Enterprise.
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+	// Consul admin partition. Admin partitions are only supported by Consul
+	// Enterprise.
+	// It requires Prometheus >= v2.41.0.
+	// +optional
+	Partition *string `json:"partition,omitempty"`
+	// Protocol scheme used to connect to the Consul server.
+	// If empty, Prometheus uses HTTP by default.
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +optional
+	Scheme *string `json:"scheme,omitempty"`
+	// List of services for which targets are discovered. If omitted, all
+	// services are discovered.
+	// +optional
+	Services []string `json:"services,omitempty"`
+	// List of tags used to filter the nodes of a given service. The
+	// services must have all the tags of the list.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// String by which the Consul tags are joined into the tag label.
+	// If empty, Prometheus uses "," by default.
+	// +optional
+	TagSeparator *string `json:"tagSeparator,omitempty"`
+	// Node metadata key/value pairs used to filter the nodes of a given
+	// service.
+	// +mapType:=atomic
+	// +optional
+	NodeMeta map[string]string `json:"nodeMeta,omitempty"`
+	// Whether to allow stale Consul results, which reduces the load on Consul.
+	// See https://www.consul.io/api/features/consistency.html
+	// +optional
+	AllowStale *bool `json:"allowStale,omitempty"`
+	// RefreshInterval configures the interval at which Prometheus refreshes
+	// the list of discovered targets.
+	// +optional
+	RefreshInterval *monitoringv1.Duration `json:"refreshInterval,omitempty"`
+	// BasicAuth information to authenticate against the Consul server.
+	// +optional
+	BasicAuth *monitoringv1.BasicAuth `json:"basicAuth,omitempty"`
+	// Authorization header configuration to authenticate against the Consul
+	// server.
+	// +optional
+	Authorization *monitoringv1.SafeAuthorization `json:"authorization,omitempty"`
+	// OAuth2 configuration to authenticate against the Consul server.
+	// +optional
+	OAuth2 *monitoringv1.OAuth2 `json:"oauth2,omitempty"`
+	// Whether HTTP requests to the Consul server follow HTTP 3xx redirects.
+	// +optional
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+	// Whether to enable HTTP2 when connecting to the Consul server.
+	// +optional
+	EnableHTTP2 *bool `json:"enableHTTP2,omitempty"`
+	// TLS configuration to connect to the Consul server.
+	// +optional
+	TLSConfig *monitoringv1.SafeTLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *ScrapeConfig) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *ScrapeConfigList) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
@@ -64,6 +64,118 @@ func (hc *HTTPConfig) Validate() error {
 	return nil
 }
 
+// Validate semantically validates the given ScrapeConfigSpec.
+func (sc *ScrapeConfigSpec) Validate() error {
+	if sc.BasicAuth != nil && sc.Authorization != nil {
+		return errors.New("at most one of basicAuth & authorization must be configured")
+	}
+
+	if err := sc.Authorization.Validate(); err != nil {
+		return err
+	}
+
+	if sc.TLSConfig != nil {
+		if err := sc.TLSConfig.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for i, ksd := range sc.KubernetesSDConfigs {
+		if err := ksd.Validate(); err != nil {
+			return fmt.Errorf("kubernetesSDConfigs[%d]: %w", i, err)
+		}
+	}
+
+	for i, csd := range sc.ConsulSDConfigs {
+		if err := csd.Validate(); err != nil {
+			return fmt.Errorf("consulSDConfigs[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate returns an error if the role isn't supported by the Kubernetes
+// service discovery.
+func (r KubernetesRole) Validate() error {
+	switch r {
+	case KubernetesRolePod, KubernetesRoleEndpoints, KubernetesRoleEndpointSlice, KubernetesRoleService, KubernetesRoleNode, KubernetesRoleIngress:
+		return nil
+	case "":
+		return errors.New("role is required")
+	}
+
+	return fmt.Errorf("invalid role %q", r)
+}
+
+// Validate semantically validates the given KubernetesSDConfig.
+func (k *KubernetesSDConfig) Validate() error {
+	if err := k.Role.Validate(); err != nil {
+		return err
+	}
+
+	for i, sel := range k.Selectors {
+		if err := sel.Role.Validate(); err != nil {
+			return fmt.Errorf("selectors[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate semantically validates the given ConsulSDConfig.
+func (c *ConsulSDConfig) Validate() error {
+	if c.Server == "" {
+		return errors.New("server is required")
+	}
+
+	if c.TokenRef != nil && (c.TokenRef.Name == "" || c.TokenRef.Key == "") {
+		return errors.New("tokenRef must reference a secret name and key")
+	}
+
+	var authN int
+	for _, set := range []bool{c.BasicAuth != nil, c.Authorization != nil, c.OAuth2 != nil} {
+		if set {
+			authN++
+		}
+	}
+	if authN > 1 {
+		return errors.New("at most one of basicAuth, authorization & oauth2 must be configured")
+	}
+
+	if c.TokenRef != nil && (c.Authorization != nil || c.OAuth2 != nil) {
+		return errors.New("tokenRef can't be configured with authorization or oauth2")
+	}
+
+	if err := c.BasicAuth.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Authorization.Validate(); err != nil {
+		return err
+	}
+
+	if c.OAuth2 != nil {
+		if err := c.OAuth2.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.RefreshInterval != nil {
+		if err := c.RefreshInterval.Validate(); err != nil {
+			return fmt.Errorf("invalid refreshInterval: %w", err)
+		}
+	}
+
+	if c.TLSConfig != nil {
+		if err := c.TLSConfig.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Validate the MuteTimeInterval
 func (mti MuteTimeInterval) Validate() error {
 	if mti.Name == "" {
@@ -17,6 +17,10 @@ package v1alpha1
 import (
 	"reflect"
 	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	v1 "k8s.io/api/core/v1"
 )
 
 func TestTimeRange_Parse(t *testing.T) {
@@ -343,3 +347,170 @@ func TestYearRange_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestScrapeConfigSpec_Validate(t *testing.T) {
+	secretKey := v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+		Key:                  "key",
+	}
+
+	testCases := []struct {
+		name      string
+		in        ScrapeConfigSpec
+		expectErr bool
+	}{
+		{
+			name: "Test empty spec is valid",
+			in:   ScrapeConfigSpec{},
+		},
+		{
+			name: "Test basicAuth and authorization are mutually exclusive",
+			in: ScrapeConfigSpec{
+				BasicAuth:     &monitoringv1.BasicAuth{Username: secretKey, Password: secretKey},
+				Authorization: &monitoringv1.SafeAuthorization{Credentials: &secretKey},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test valid Kubernetes SD config",
+			in: ScrapeConfigSpec{
+				KubernetesSDConfigs: []KubernetesSDConfig{
+					{
+						Role: KubernetesRoleEndpoints,
+						Selectors: []K8SSelectorConfig{
+							{Role: KubernetesRolePod, Label: "app=foo"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Test Kubernetes SD config without role",
+			in: ScrapeConfigSpec{
+				KubernetesSDConfigs: []KubernetesSDConfig{{}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test Kubernetes SD config with invalid role",
+			in: ScrapeConfigSpec{
+				KubernetesSDConfigs: []KubernetesSDConfig{{Role: "pods"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test Kubernetes SD config with invalid selector role",
+			in: ScrapeConfigSpec{
+				KubernetesSDConfigs: []KubernetesSDConfig{
+					{
+						Role:      KubernetesRolePod,
+						Selectors: []K8SSelectorConfig{{Role: "foo"}},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test valid Consul SD config",
+			in: ScrapeConfigSpec{
+				ConsulSDConfigs: []ConsulSDConfig{
+					{
+						Server:    "consul:8500",
+						TokenRef:  &secretKey,
+						BasicAuth: &monitoringv1.BasicAuth{Username: secretKey, Password: secretKey},
+					},
+				},
+			},
+		},
+		{
+			name: "Test Consul SD config with tokenRef and authorization",
+			in: ScrapeConfigSpec{
+				ConsulSDConfigs: []ConsulSDConfig{
+					{
+						Server:        "consul:8500",
+						TokenRef:      &secretKey,
+						Authorization: &monitoringv1.SafeAuthorization{Credentials: &secretKey},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test Consul SD config with tokenRef and oauth2",
+			in: ScrapeConfigSpec{
+				ConsulSDConfigs: []ConsulSDConfig{
+					{
+						Server:   "consul:8500",
+						TokenRef: &secretKey,
+						OAuth2: &monitoringv1.OAuth2{
+							ClientID:     monitoringv1.SecretOrConfigMap{Secret: &secretKey},
+							ClientSecret: secretKey,
+							TokenURL:     "http://example.com/token",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test Consul SD config without server",
+			in: ScrapeConfigSpec{
+				ConsulSDConfigs: []ConsulSDConfig{{}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test Consul SD config with incomplete token reference",
+			in: ScrapeConfigSpec{
+				ConsulSDConfigs: []ConsulSDConfig{
+					{
+						Server:   "consul:8500",
+						TokenRef: &v1.SecretKeySelector{Key: "token"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test Consul SD config with basicAuth and authorization",
+			in: ScrapeConfigSpec{
+				ConsulSDConfigs: []ConsulSDConfig{
+					{
+						Server:        "consul:8500",
+						BasicAuth:     &monitoringv1.BasicAuth{Username: secretKey, Password: secretKey},
+						Authorization: &monitoringv1.SafeAuthorization{Credentials: &secretKey},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Test Consul SD config with invalid refresh interval",
+			in: ScrapeConfigSpec{
+				ConsulSDConfigs: []ConsulSDConfig{
+					{
+						Server:          "consul:8500",
+						RefreshInterval: func() *monitoringv1.Duration { d := monitoringv1.Duration("1 minute"); return &d }(),
+					},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.in.Validate()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected err but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+		})
+	}
+}
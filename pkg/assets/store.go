@@ -20,6 +20,8 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"hash/fnv"
+	"strconv"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
@@ -42,27 +44,60 @@ type Store struct {
 	sClient  corev1client.SecretsGetter
 	objStore cache.Store
 
-	TLSAssets       map[TLSAssetKey]TLSAsset
-	TokenAssets     map[string]Token
-	BasicAuthAssets map[string]BasicAuthCredentials
-	OAuth2Assets    map[string]OAuth2Credentials
-	SigV4Assets     map[string]SigV4Credentials
+	TLSAssets                map[TLSAssetKey]TLSAsset
+	TokenAssets              map[string]Token
+	BasicAuthAssets          map[string]BasicAuthCredentials
+	OAuth2Assets             map[string]OAuth2Credentials
+	SigV4Assets              map[string]SigV4Credentials
+	ProxyConnectHeaderAssets map[string]ProxyConnectHeader
+	ProjectedTokenAssets     map[string]monitoringv1.ProjectedToken
 }
 
 // NewStore returns an empty assetStore.
 func NewStore(cmClient corev1client.ConfigMapsGetter, sClient corev1client.SecretsGetter) *Store {
 	return &Store{
-		cmClient:        cmClient,
-		sClient:         sClient,
-		TLSAssets:       make(map[TLSAssetKey]TLSAsset),
-		TokenAssets:     make(map[string]Token),
-		BasicAuthAssets: make(map[string]BasicAuthCredentials),
-		OAuth2Assets:    make(map[string]OAuth2Credentials),
-		SigV4Assets:     make(map[string]SigV4Credentials),
-		objStore:        cache.NewStore(assetKeyFunc),
+		cmClient:                 cmClient,
+		sClient:                  sClient,
+		TLSAssets:                make(map[TLSAssetKey]TLSAsset),
+		TokenAssets:              make(map[string]Token),
+		BasicAuthAssets:          make(map[string]BasicAuthCredentials),
+		OAuth2Assets:             make(map[string]OAuth2Credentials),
+		SigV4Assets:              make(map[string]SigV4Credentials),
+		ProxyConnectHeaderAssets: make(map[string]ProxyConnectHeader),
+		ProjectedTokenAssets:     make(map[string]monitoringv1.ProjectedToken),
+		objStore:                 cache.NewStore(assetKeyFunc),
 	}
 }
 
+// ProjectedTokenKey returns the name of the file holding the projected
+// service account token. Tokens with the same audience and expiration share
+// the same file.
+func ProjectedTokenKey(pt *monitoringv1.ProjectedToken) string {
+	h := fnv.New32a()
+	h.Write([]byte(pt.Audience))
+	if pt.ExpirationSeconds != nil {
+		h.Write([]byte("/" + strconv.FormatInt(*pt.ExpirationSeconds, 10)))
+	}
+
+	return fmt.Sprintf("token-%08x", h.Sum32())
+}
+
+// AddProjectedToken validates the given projected service account token and
+// adds it to the store.
+func (s *Store) AddProjectedToken(pt *monitoringv1.ProjectedToken) error {
+	if pt == nil {
+		return nil
+	}
+
+	if err := pt.Validate(); err != nil {
+		return errors.Wrap(err, "invalid bearerTokenProjected")
+	}
+
+	s.ProjectedTokenAssets[ProjectedTokenKey(pt)] = *pt
+
+	return nil
+}
+
 func assetKeyFunc(obj interface{}) (string, error) {
 	switch v := obj.(type) {
 	case *v1.ConfigMap:
@@ -200,6 +235,47 @@ func (s *Store) AddOAuth2(ctx context.Context, ns string, oauth2 *monitoringv1.O
 		ClientSecret: clientSecret,
 	}
 
+	if err := s.AddProxyConfig(ctx, ns, &oauth2.ProxyConfig, "oauth2/"+key); err != nil {
+		return errors.Wrap(err, "oauth2")
+	}
+
+	if err := s.AddSafeTLSConfig(ctx, ns, oauth2.TLSConfig); err != nil {
+		return errors.Wrap(err, "oauth2")
+	}
+
+	return nil
+}
+
+// AddProxyConfig processes the given *ProxyConfig and adds the referenced
+// proxy connect headers to the store.
+func (s *Store) AddProxyConfig(ctx context.Context, ns string, pc *monitoringv1.ProxyConfig, key string) error {
+	if pc == nil {
+		return nil
+	}
+
+	if err := pc.Validate(); err != nil {
+		return err
+	}
+
+	if len(pc.ProxyConnectHeader) == 0 {
+		return nil
+	}
+
+	headers := make(ProxyConnectHeader, len(pc.ProxyConnectHeader))
+	for k, sels := range pc.ProxyConnectHeader {
+		values := make([]string, 0, len(sels))
+		for _, sel := range sels {
+			value, err := s.GetSecretKey(ctx, ns, sel)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get proxy connect header %q", k)
+			}
+			values = append(values, value)
+		}
+		headers[k] = values
+	}
+
+	s.ProxyConnectHeaderAssets[key] = headers
+
 	return nil
 }
 
@@ -1039,3 +1039,141 @@ func TestAddSigV4(t *testing.T) {
 		})
 	}
 }
+
+func TestAddProxyConfig(t *testing.T) {
+	c := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "ns1",
+			},
+			Data: map[string][]byte{
+				"key1": []byte("val1"),
+				"key2": []byte("val2"),
+			},
+		},
+	)
+
+	proxyURL := "http://proxy:8080"
+	for i, tc := range []struct {
+		ns          string
+		proxyConfig *monitoringv1.ProxyConfig
+
+		err      bool
+		expected ProxyConnectHeader
+	}{
+		{
+			ns:          "ns1",
+			proxyConfig: nil,
+		},
+		{
+			ns: "ns1",
+			proxyConfig: &monitoringv1.ProxyConfig{
+				ProxyURL: &proxyURL,
+				ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+					"X-Foo": {
+						{LocalObjectReference: v1.LocalObjectReference{Name: "secret"}, Key: "key1"},
+						{LocalObjectReference: v1.LocalObjectReference{Name: "secret"}, Key: "key2"},
+					},
+				},
+			},
+
+			expected: ProxyConnectHeader{"X-Foo": []string{"val1", "val2"}},
+		},
+		// Wrong namespace.
+		{
+			ns: "ns2",
+			proxyConfig: &monitoringv1.ProxyConfig{
+				ProxyURL: &proxyURL,
+				ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+					"X-Foo": {
+						{LocalObjectReference: v1.LocalObjectReference{Name: "secret"}, Key: "key1"},
+					},
+				},
+			},
+
+			err: true,
+		},
+		// Invalid proxy configuration.
+		{
+			ns: "ns1",
+			proxyConfig: &monitoringv1.ProxyConfig{
+				ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+					"X-Foo": {
+						{LocalObjectReference: v1.LocalObjectReference{Name: "secret"}, Key: "key1"},
+					},
+				},
+			},
+
+			err: true,
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			store := NewStore(c.CoreV1(), c.CoreV1())
+
+			key := fmt.Sprintf("proxy/%d", i)
+			err := store.AddProxyConfig(context.Background(), tc.ns, tc.proxyConfig, key)
+
+			if tc.err {
+				if err == nil {
+					t.Fatal("expecting error, got no error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expecting no error, got %q", err)
+			}
+
+			if !reflect.DeepEqual(tc.expected, store.ProxyConnectHeaderAssets[key]) {
+				t.Fatalf("expecting %v, got %v", tc.expected, store.ProxyConnectHeaderAssets[key])
+			}
+		})
+	}
+}
+
+func TestAddProjectedToken(t *testing.T) {
+	expiration := int64(3600)
+	for _, tc := range []struct {
+		token *monitoringv1.ProjectedToken
+		err   bool
+	}{
+		{
+			token: &monitoringv1.ProjectedToken{Audience: "oidc"},
+		},
+		{
+			token: &monitoringv1.ProjectedToken{Audience: "oidc", ExpirationSeconds: &expiration},
+		},
+		{
+			token: &monitoringv1.ProjectedToken{},
+			err:   true,
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			store := NewStore(nil, nil)
+
+			err := store.AddProjectedToken(tc.token)
+
+			if tc.err {
+				if err == nil {
+					t.Fatal("expecting error, got no error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expecting no error, got %q", err)
+			}
+
+			if !reflect.DeepEqual(*tc.token, store.ProjectedTokenAssets[ProjectedTokenKey(tc.token)]) {
+				t.Fatalf("expecting %v, got %v", *tc.token, store.ProjectedTokenAssets)
+			}
+		})
+	}
+
+	a := ProjectedTokenKey(&monitoringv1.ProjectedToken{Audience: "oidc"})
+	b := ProjectedTokenKey(&monitoringv1.ProjectedToken{Audience: "oidc", ExpirationSeconds: &expiration})
+	if a == b {
+		t.Fatalf("expecting different keys for different expirations, got %q", a)
+	}
+}
@@ -32,6 +32,10 @@ type OAuth2Credentials struct {
 // https://tools.ietf.org/html/rfc6750.
 type Token string
 
+// ProxyConnectHeader represents the headers (with the values resolved from
+// secrets) sent to a proxy during CONNECT requests.
+type ProxyConnectHeader map[string][]string
+
 // TLSAsset represents any TLS related opaque string, e.g. CA files, client
 // certificates.
 type TLSAsset string
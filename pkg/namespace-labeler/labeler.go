@@ -112,8 +112,7 @@ func (l *Labeler) EnforceNamespaceLabel(rule *monitoringv1.PrometheusRule) error
 			}
 			rule.Spec.Groups[gi].Rules[ri].Labels[l.enforcedNsLabel] = rule.Namespace
 
-			expr := r.Expr.String()
-			parsedExpr, err := parser.ParseExpr(expr)
+			parsedExpr, err := parser.ParseExpr(r.Expression())
 			if err != nil {
 				return errors.Wrap(err, "failed to parse promql expression")
 			}
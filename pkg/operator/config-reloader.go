@@ -21,6 +21,8 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
 
 const configReloaderPort = 8080
@@ -37,10 +39,13 @@ type ConfigReloader struct {
 	logFormat          string
 	logLevel           string
 	reloadURL          url.URL
+	processName        string
 	runOnce            bool
+	skipInitialReload  bool
 	shard              *int32
 	volumeMounts       []v1.VolumeMount
 	watchedDirectories []string
+	watchInterval      *monitoringv1.Duration
 }
 
 type ReloaderOption = func(*ConfigReloader)
@@ -52,6 +57,23 @@ func ReloaderRunOnce() ReloaderOption {
 	}
 }
 
+// ReloaderSkipInitialReload sets the skipInitialReload option for the
+// config-reloader container.
+func ReloaderSkipInitialReload(skip bool) ReloaderOption {
+	return func(c *ConfigReloader) {
+		c.skipInitialReload = skip
+	}
+}
+
+// WatchInterval sets the watchInterval option for the config-reloader
+// container. It has no effect when the value is nil or when combined with
+// ReloaderRunOnce(). The value is expected to be valid.
+func WatchInterval(watchInterval *monitoringv1.Duration) ReloaderOption {
+	return func(c *ConfigReloader) {
+		c.watchInterval = watchInterval
+	}
+}
+
 // WatchedDirectories sets the watchedDirectories option for the config-reloader container
 func WatchedDirectories(watchedDirectories []string) ReloaderOption {
 	return func(c *ConfigReloader) {
@@ -87,6 +109,14 @@ func ReloaderURL(reloadURL url.URL) ReloaderOption {
 	}
 }
 
+// ReloaderProcessSignal configures the config-reloader container to send a
+// SIGHUP signal to the named process instead of calling the reload URL.
+func ReloaderProcessSignal(processName string) ReloaderOption {
+	return func(c *ConfigReloader) {
+		c.processName = processName
+	}
+}
+
 // ListenLocal sets the listenLocal option for the config-reloader container
 func ListenLocal(listenLocal bool) ReloaderOption {
 	return func(c *ConfigReloader) {
@@ -153,6 +183,12 @@ func CreateConfigReloader(name string, options ...ReloaderOption) v1.Container {
 
 	if configReloader.runOnce {
 		args = append(args, fmt.Sprintf("--watch-interval=%d", 0))
+	} else if configReloader.watchInterval != nil {
+		// The config-reloader parses Go durations which don't support the
+		// day/week/year units of Prometheus durations.
+		if d, err := configReloader.watchInterval.Parse(); err == nil {
+			args = append(args, fmt.Sprintf("--watch-interval=%s", d))
+		}
 	}
 
 	if configReloader.listenLocal {
@@ -169,7 +205,14 @@ func CreateConfigReloader(name string, options ...ReloaderOption) v1.Container {
 		)
 	}
 
-	if len(configReloader.reloadURL.String()) > 0 {
+	if configReloader.skipInitialReload && !configReloader.runOnce {
+		args = append(args, "--skip-initial-reload")
+	}
+
+	if configReloader.processName != "" {
+		args = append(args, "--reload-method=signal")
+		args = append(args, fmt.Sprintf("--process-executable-name=%s", configReloader.processName))
+	} else if len(configReloader.reloadURL.String()) > 0 {
 		args = append(args, fmt.Sprintf("--reload-url=%s", configReloader.reloadURL.String()))
 	}
 
@@ -18,7 +18,10 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
 
 var reloaderConfig = ReloaderConfig{
@@ -106,6 +109,95 @@ func TestCreateConfigReloader(t *testing.T) {
 	}
 }
 
+func TestCreateConfigReloaderWatchInterval(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		options  []ReloaderOption
+		expected string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:     "custom interval",
+			options:  []ReloaderOption{WatchInterval(durationPtr("30s"))},
+			expected: "--watch-interval=30s",
+		},
+		{
+			name:     "prometheus duration",
+			options:  []ReloaderOption{WatchInterval(durationPtr("1d"))},
+			expected: "--watch-interval=24h0m0s",
+		},
+		{
+			name:     "run once",
+			options:  []ReloaderOption{WatchInterval(durationPtr("30s")), ReloaderRunOnce()},
+			expected: "--watch-interval=0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			container := CreateConfigReloader("config-reloader", append(tc.options, ReloaderResources(reloaderConfig))...)
+
+			var got string
+			for _, arg := range container.Args {
+				if strings.HasPrefix(arg, "--watch-interval=") {
+					got = arg
+				}
+			}
+
+			if got != tc.expected {
+				t.Errorf("Expected %q watch interval argument, got %q in %s", tc.expected, got, container.Args)
+			}
+		})
+	}
+}
+
+func durationPtr(d monitoringv1.Duration) *monitoringv1.Duration {
+	return &d
+}
+
+func TestCreateConfigReloaderProcessSignal(t *testing.T) {
+	var container = CreateConfigReloader(
+		"config-reloader",
+		ReloaderResources(reloaderConfig),
+		ReloaderURL(url.URL{
+			Scheme: "http",
+			Host:   "localhost:9090",
+			Path:   "/-/reload",
+		}),
+		ReloaderProcessSignal("prometheus"),
+	)
+	if !contains(container.Args, "--reload-method=signal") {
+		t.Errorf("Expected '--reload-method=signal' not found in %s", container.Args)
+	}
+	if !contains(container.Args, "--process-executable-name=prometheus") {
+		t.Errorf("Expected '--process-executable-name=prometheus' not found in %s", container.Args)
+	}
+	if contains(container.Args, "--reload-url=http://localhost:9090/-/reload") {
+		t.Errorf("Unexpected '--reload-url' found in %s", container.Args)
+	}
+}
+
+func TestCreateConfigReloaderSkipInitialReload(t *testing.T) {
+	container := CreateConfigReloader(
+		"config-reloader",
+		ReloaderResources(reloaderConfig),
+		ReloaderSkipInitialReload(true),
+	)
+	if !contains(container.Args, "--skip-initial-reload") {
+		t.Errorf("Expected '--skip-initial-reload' not found in %s", container.Args)
+	}
+
+	container = CreateConfigReloader(
+		"init-config-reloader",
+		ReloaderResources(reloaderConfig),
+		ReloaderRunOnce(),
+		ReloaderSkipInitialReload(true),
+	)
+	if contains(container.Args, "--skip-initial-reload") {
+		t.Errorf("Unexpected '--skip-initial-reload' found in %s", container.Args)
+	}
+}
+
 func contains(s []string, str string) bool {
 	for _, v := range s {
 		if v == str {
@@ -16,6 +16,8 @@ package operator
 
 import (
 	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
 
 type ImageSpec struct {
@@ -72,3 +74,88 @@ func TestBuildImagePath(t *testing.T) {
 		}
 	}
 }
+
+// TestBuildImagePathMatchesImageReferences checks that the image references
+// computed by the API types (which can't import this package) agree with
+// BuildImagePath().
+func TestBuildImagePathMatchesImageReferences(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec ImageSpec
+	}{
+		{
+			name: "spec image",
+			spec: ImageSpec{SpecImage: "myspecrepo.com/myimage", Image: "myrepo.com/foo", Version: "1.0"},
+		},
+		{
+			name: "version",
+			spec: ImageSpec{Image: "quay.io/prometheus/prometheus", Version: "v2.40.0"},
+		},
+		{
+			name: "tag",
+			spec: ImageSpec{Image: "quay.io/prometheus/prometheus", Version: "v2.40.0", Tag: "latest"},
+		},
+		{
+			name: "digest",
+			spec: ImageSpec{Image: "quay.io/prometheus/prometheus", Version: "v2.40.0", Tag: "latest", SHA: "abcd1234"},
+		},
+		{
+			name: "tagged base image",
+			spec: ImageSpec{Image: "quay.io/prometheus/prometheus:v2.39.0", Version: "v2.40.0"},
+		},
+		{
+			name: "base image with digest",
+			spec: ImageSpec{Image: "quay.io/prometheus/prometheus@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", Tag: "latest"},
+		},
+		{
+			name: "registry with port",
+			spec: ImageSpec{Image: "myhost:9090/myrepo/myimage", Tag: "latest"},
+		},
+		{
+			name: "short name with version",
+			spec: ImageSpec{Image: "prometheus", Version: "v2.40.0"},
+		},
+		{
+			name: "short name with tag",
+			spec: ImageSpec{Image: "prometheus", Tag: "latest"},
+		},
+		{
+			name: "docker hub name with tag",
+			spec: ImageSpec{Image: "prom/prometheus", Tag: "latest"},
+		},
+		{
+			name: "localhost with tag",
+			spec: ImageSpec{Image: "localhost/prometheus", Tag: "latest"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expected, err := BuildImagePath(tc.spec.SpecImage, tc.spec.Image, tc.spec.Version, tc.spec.Tag, tc.spec.SHA)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			p := &monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.spec.Version,
+					},
+					BaseImage: tc.spec.Image,
+					Tag:       tc.spec.Tag,
+					SHA:       tc.spec.SHA,
+				},
+			}
+			if tc.spec.SpecImage != "" {
+				p.Spec.Image = &tc.spec.SpecImage
+			}
+
+			images, err := p.ImageReferences(monitoringv1.ImageDefaults{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if images[0] != expected {
+				t.Fatalf("expected image %q, got %q", expected, images[0])
+			}
+		})
+	}
+}
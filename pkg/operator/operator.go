@@ -16,6 +16,7 @@ package operator
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 
@@ -47,9 +48,16 @@ var (
 
 type ReconciliationStatus struct {
 	err error
+	// degraded lists the reasons why a successful reconciliation didn't
+	// fully apply the object's spec.
+	degraded []string
 }
 
 func (rs ReconciliationStatus) Reason() string {
+	if rs.Degraded() {
+		return "ReconciliationDegraded"
+	}
+
 	if rs.Ok() {
 		return ""
 	}
@@ -58,6 +66,10 @@ func (rs ReconciliationStatus) Reason() string {
 }
 
 func (rs ReconciliationStatus) Message() string {
+	if rs.Degraded() {
+		return strings.Join(rs.degraded, "\n")
+	}
+
 	if rs.Ok() {
 		return ""
 	}
@@ -69,6 +81,12 @@ func (rs ReconciliationStatus) Ok() bool {
 	return rs.err == nil
 }
 
+// Degraded returns true if the reconciliation was successful but some parts
+// of the object's spec have been ignored.
+func (rs ReconciliationStatus) Degraded() bool {
+	return rs.Ok() && len(rs.degraded) > 0
+}
+
 // ReconciliationTracker tracks reconciliation status per object.
 // The zero ReconciliationTracker is ready to use.
 type ReconciliationTracker struct {
@@ -79,7 +97,8 @@ type ReconciliationTracker struct {
 }
 
 // SetStatus updates the last reconciliation status for the given object.
-func (rt *ReconciliationTracker) SetStatus(k string, err error) {
+// The degraded messages are only taken into account when err is nil.
+func (rt *ReconciliationTracker) SetStatus(k string, err error, degraded ...string) {
 	rt.mtx.Lock()
 	defer rt.mtx.Unlock()
 
@@ -87,7 +106,7 @@ func (rt *ReconciliationTracker) SetStatus(k string, err error) {
 		rt.statusByObject = map[string]ReconciliationStatus{}
 	})
 
-	rt.statusByObject[k] = ReconciliationStatus{err: err}
+	rt.statusByObject[k] = ReconciliationStatus{err: err, degraded: degraded}
 }
 
 // GetStatus returns the last reconciliation status for the given object.
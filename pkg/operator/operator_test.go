@@ -0,0 +1,67 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReconciliationTracker(t *testing.T) {
+	rt := &ReconciliationTracker{}
+
+	if _, found := rt.GetStatus("ns/ok"); found {
+		t.Fatal("expected unknown object")
+	}
+
+	rt.SetStatus("ns/ok", nil)
+	rt.SetStatus("ns/failed", errors.New("boom"), "ignored")
+	rt.SetStatus("ns/degraded", nil, "foo requires Prometheus >= v2.30.0", "bar requires Prometheus >= v2.40.0")
+
+	for _, tc := range []struct {
+		key      string
+		ok       bool
+		degraded bool
+		reason   string
+		message  string
+	}{
+		{key: "ns/ok", ok: true},
+		{key: "ns/failed", reason: "ReconciliationFailed", message: "boom"},
+		{key: "ns/degraded", ok: true, degraded: true, reason: "ReconciliationDegraded", message: "foo requires Prometheus >= v2.30.0\nbar requires Prometheus >= v2.40.0"},
+	} {
+		t.Run(tc.key, func(t *testing.T) {
+			st, found := rt.GetStatus(tc.key)
+			if !found {
+				t.Fatal("expected object to be found")
+			}
+
+			if st.Ok() != tc.ok {
+				t.Fatalf("expected Ok() to be %v, got %v", tc.ok, st.Ok())
+			}
+
+			if st.Degraded() != tc.degraded {
+				t.Fatalf("expected Degraded() to be %v, got %v", tc.degraded, st.Degraded())
+			}
+
+			if st.Reason() != tc.reason {
+				t.Fatalf("expected reason %q, got %q", tc.reason, st.Reason())
+			}
+
+			if st.Message() != tc.message {
+				t.Fatalf("expected message %q, got %q", tc.message, st.Message())
+			}
+		})
+	}
+}
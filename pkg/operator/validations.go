@@ -15,10 +15,15 @@
 package operator
 
 import (
+	"fmt"
+
 	"github.com/alecthomas/units"
 	"github.com/pkg/errors"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func ValidateSizeField(sizeField string) error {
@@ -29,6 +34,11 @@ func ValidateSizeField(sizeField string) error {
 	return nil
 }
 
+// ParseByteSize returns the number of bytes represented by the given size.
+func ParseByteSize(size monitoringv1.ByteSize) (int64, error) {
+	return size.Bytes()
+}
+
 func ValidateDurationField(durationField string) error {
 	// To validate if given value is parsable for the acceptable duration values
 	if _, err := model.ParseDuration(durationField); err != nil {
@@ -62,3 +72,88 @@ func CompareScrapeTimeoutToScrapeInterval(scrapeTimeout, scrapeInterval monitori
 
 	return nil
 }
+
+// ValidateImagePullSecrets checks that the image pull secrets reference
+// valid Secret names.
+func ValidateImagePullSecrets(fldPath *field.Path, secrets []v1.LocalObjectReference) error {
+	var errs field.ErrorList
+	for i, s := range secrets {
+		namePath := fldPath.Index(i).Child("name")
+		if s.Name == "" {
+			errs = append(errs, field.Required(namePath, "secret name must be set"))
+			continue
+		}
+
+		for _, msg := range validation.IsDNS1123Subdomain(s.Name) {
+			errs = append(errs, field.Invalid(namePath, s.Name, msg))
+		}
+	}
+
+	return errs.ToAggregate()
+}
+
+// ValidateEnvVars checks that the environment variables have valid names
+// which don't collide with the variables managed by the operator.
+func ValidateEnvVars(fldPath *field.Path, env []v1.EnvVar, reserved ...string) error {
+	var errs field.ErrorList
+	for i, e := range env {
+		namePath := fldPath.Index(i).Child("name")
+		if e.Name == "" {
+			errs = append(errs, field.Required(namePath, "environment variable name must be set"))
+			continue
+		}
+
+		for _, msg := range validation.IsCIdentifier(e.Name) {
+			errs = append(errs, field.Invalid(namePath, e.Name, msg))
+		}
+
+		for _, r := range reserved {
+			if e.Name == r {
+				errs = append(errs, field.Forbidden(namePath, fmt.Sprintf("%q is managed by the operator", e.Name)))
+			}
+		}
+	}
+
+	return errs.ToAggregate()
+}
+
+// ValidateEnvFromSources checks that every source references either a
+// ConfigMap or a Secret with a valid name.
+func ValidateEnvFromSources(fldPath *field.Path, envFrom []v1.EnvFromSource) error {
+	var errs field.ErrorList
+	for i, e := range envFrom {
+		idxPath := fldPath.Index(i)
+
+		if e.Prefix != "" {
+			for _, msg := range validation.IsCIdentifier(e.Prefix) {
+				errs = append(errs, field.Invalid(idxPath.Child("prefix"), e.Prefix, msg))
+			}
+		}
+
+		var name string
+		var namePath *field.Path
+		switch {
+		case e.ConfigMapRef != nil && e.SecretRef != nil:
+			errs = append(errs, field.Invalid(idxPath, "", "only one of configMapRef and secretRef must be set"))
+			continue
+		case e.ConfigMapRef != nil:
+			name, namePath = e.ConfigMapRef.Name, idxPath.Child("configMapRef", "name")
+		case e.SecretRef != nil:
+			name, namePath = e.SecretRef.Name, idxPath.Child("secretRef", "name")
+		default:
+			errs = append(errs, field.Required(idxPath, "one of configMapRef or secretRef must be set"))
+			continue
+		}
+
+		if name == "" {
+			errs = append(errs, field.Required(namePath, "name must be set"))
+			continue
+		}
+
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			errs = append(errs, field.Invalid(namePath, name, msg))
+		}
+	}
+
+	return errs.ToAggregate()
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateImagePullSecrets(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		secrets  []v1.LocalObjectReference
+		expected []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:    "valid",
+			secrets: []v1.LocalObjectReference{{Name: "registry"}, {Name: "registry.example.com"}},
+		},
+		{
+			name:     "empty name",
+			secrets:  []v1.LocalObjectReference{{Name: "registry"}, {}},
+			expected: []string{"spec.imagePullSecrets[1].name"},
+		},
+		{
+			name:     "invalid names",
+			secrets:  []v1.LocalObjectReference{{Name: "Registry"}, {Name: "registry"}, {Name: "registry_secret"}},
+			expected: []string{"spec.imagePullSecrets[0].name", "spec.imagePullSecrets[2].name"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateImagePullSecrets(field.NewPath("spec", "imagePullSecrets"), tc.secrets)
+			if len(tc.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected errors for %v, got none", tc.expected)
+			}
+			for _, f := range tc.expected {
+				if !strings.Contains(err.Error(), f) {
+					t.Fatalf("expected error for %q, got %v", f, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEnvVars(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		env      []v1.EnvVar
+		expected []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			env:  []v1.EnvVar{{Name: "AWS_REGION", Value: "eu-west-1"}, {Name: "_HTTPS_PROXY"}},
+		},
+		{
+			name:     "invalid names",
+			env:      []v1.EnvVar{{Name: "AWS-REGION"}, {Name: "HTTPS_PROXY"}, {Name: "1PROXY"}, {}},
+			expected: []string{"spec.env[0].name", "spec.env[2].name", "spec.env[3].name"},
+		},
+		{
+			name:     "reserved name",
+			env:      []v1.EnvVar{{Name: "HTTPS_PROXY"}, {Name: "POD_IP", Value: "1.2.3.4"}},
+			expected: []string{"spec.env[1].name"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateEnvVars(field.NewPath("spec", "env"), tc.env, "POD_IP")
+			if len(tc.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected errors for %v, got none", tc.expected)
+			}
+			for _, f := range tc.expected {
+				if !strings.Contains(err.Error(), f) {
+					t.Fatalf("expected error for %q, got %v", f, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEnvFromSources(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		envFrom  []v1.EnvFromSource
+		expected []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			envFrom: []v1.EnvFromSource{
+				{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "proxy"}}},
+				{Prefix: "AWS_", SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "aws-credentials"}}},
+			},
+		},
+		{
+			name: "invalid sources",
+			envFrom: []v1.EnvFromSource{
+				{},
+				{
+					ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "proxy"}},
+					SecretRef:    &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "proxy"}},
+				},
+				{ConfigMapRef: &v1.ConfigMapEnvSource{}},
+				{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "AWS"}}},
+				{Prefix: "AWS-", SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "aws"}}},
+			},
+			expected: []string{
+				"spec.envFrom[0]",
+				"spec.envFrom[1]",
+				"spec.envFrom[2].configMapRef.name",
+				"spec.envFrom[3].secretRef.name",
+				"spec.envFrom[4].prefix",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateEnvFromSources(field.NewPath("spec", "envFrom"), tc.envFrom)
+			if len(tc.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected errors for %v, got none", tc.expected)
+			}
+			for _, f := range tc.expected {
+				if !strings.Contains(err.Error(), f) {
+					t.Fatalf("expected error for %q, got %v", f, err)
+				}
+			}
+		})
+	}
+}
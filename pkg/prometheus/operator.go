@@ -18,13 +18,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/asaskevich/govalidator"
 	"github.com/blang/semver/v4"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -32,7 +31,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -40,10 +41,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
 	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
@@ -71,6 +74,7 @@ type Operator struct {
 	smonInfs  *informers.ForResource
 	pmonInfs  *informers.ForResource
 	probeInfs *informers.ForResource
+	sconInfs  *informers.ForResource
 	ruleInfs  *informers.ForResource
 	cmapInfs  *informers.ForResource
 	secrInfs  *informers.ForResource
@@ -81,6 +85,8 @@ type Operator struct {
 	metrics         *operator.Metrics
 	reconciliations *operator.ReconciliationTracker
 
+	thanosUploadFailures thanosUploadFailureTracker
+
 	nodeAddressLookupErrors prometheus.Counter
 	nodeEndpointSyncs       prometheus.Counter
 	nodeEndpointSyncErrors  prometheus.Counter
@@ -91,6 +97,7 @@ type Operator struct {
 	kubeletSyncEnabled     bool
 	config                 operator.Config
 	endpointSliceSupported bool
+	scrapeConfigSupported  bool
 }
 
 // New creates a new controller.
@@ -239,6 +246,30 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 		return nil, errors.Wrap(err, "error creating probe informers")
 	}
 
+	// The ScrapeConfig CRD is optional, the informers are created only when
+	// the resource is served by the API.
+	c.scrapeConfigSupported, err = k8sutil.IsAPIGroupVersionResourceSupported(c.kclient.Discovery(), monitoringv1alpha1.SchemeGroupVersion.String(), monitoringv1alpha1.ScrapeConfigName)
+	if err != nil {
+		level.Warn(c.logger).Log("msg", "failed to check if the API supports the scrapeconfig resources", "err ", err)
+	}
+	level.Info(c.logger).Log("msg", "Kubernetes API capabilities", "scrapeconfigs", c.scrapeConfigSupported)
+
+	if c.scrapeConfigSupported {
+		c.sconInfs, err = informers.NewInformersForResource(
+			informers.NewMonitoringInformerFactories(
+				c.config.Namespaces.AllowList,
+				c.config.Namespaces.DenyList,
+				mclient,
+				resyncPeriod,
+				nil,
+			),
+			monitoringv1alpha1.SchemeGroupVersion.WithResource(monitoringv1alpha1.ScrapeConfigName),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating scrapeconfig informers")
+		}
+	}
+
 	c.ruleInfs, err = informers.NewInformersForResource(
 		informers.NewMonitoringInformerFactories(
 			c.config.Namespaces.AllowList,
@@ -338,10 +369,12 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 
 // waitForCacheSync waits for the informers' caches to be synced.
 func (c *Operator) waitForCacheSync(ctx context.Context) error {
-	for _, infs := range []struct {
+	type namedInformers struct {
 		name                 string
 		informersForResource *informers.ForResource
-	}{
+	}
+
+	infList := []namedInformers{
 		{"Prometheus", c.promInfs},
 		{"ServiceMonitor", c.smonInfs},
 		{"PodMonitor", c.pmonInfs},
@@ -350,7 +383,12 @@ func (c *Operator) waitForCacheSync(ctx context.Context) error {
 		{"ConfigMap", c.cmapInfs},
 		{"Secret", c.secrInfs},
 		{"StatefulSet", c.ssetInfs},
-	} {
+	}
+	if c.sconInfs != nil {
+		infList = append(infList, namedInformers{"ScrapeConfig", c.sconInfs})
+	}
+
+	for _, infs := range infList {
 		for _, inf := range infs.informersForResource.GetInformers() {
 			if !operator.WaitForNamedCacheSync(ctx, "prometheus", log.With(c.logger, "informer", infs.name), inf.Informer()) {
 				return errors.Errorf("failed to sync cache for %s informer", infs.name)
@@ -396,6 +434,13 @@ func (c *Operator) addHandlers() {
 		UpdateFunc: c.handleBmonUpdate,
 		DeleteFunc: c.handleBmonDelete,
 	})
+	if c.sconInfs != nil {
+		c.sconInfs.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleScrapeConfigAdd,
+			UpdateFunc: c.handleScrapeConfigUpdate,
+			DeleteFunc: c.handleScrapeConfigDelete,
+		})
+	}
 	c.ruleInfs.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.handleRuleAdd,
 		DeleteFunc: c.handleRuleDelete,
@@ -415,9 +460,12 @@ func (c *Operator) addHandlers() {
 	// The controller needs to watch the namespaces in which the service/pod
 	// monitors and rules live because a label change on a namespace may
 	// trigger a configuration change.
-	// It doesn't need to watch on addition/deletion though because it's
-	// already covered by the event handlers on service/pod monitors and rules.
+	// It also watches on addition because a new namespace may match the
+	// namespace selector of service/pod monitors and probes. It doesn't need
+	// to watch on deletion though because it's already covered by the event
+	// handlers on service/pod monitors and rules.
 	c.nsMonInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.handleMonitorNamespaceAdd,
 		UpdateFunc: c.handleMonitorNamespaceUpdate,
 	})
 }
@@ -452,6 +500,9 @@ func (c *Operator) Run(ctx context.Context) error {
 	go c.smonInfs.Start(ctx.Done())
 	go c.pmonInfs.Start(ctx.Done())
 	go c.probeInfs.Start(ctx.Done())
+	if c.sconInfs != nil {
+		go c.sconInfs.Start(ctx.Done())
+	}
 	go c.ruleInfs.Start(ctx.Done())
 	go c.cmapInfs.Start(ctx.Done())
 	go c.secrInfs.Start(ctx.Done())
@@ -789,6 +840,37 @@ func (c *Operator) handleBmonDelete(obj interface{}) {
 	}
 }
 
+// TODO: Don't enqueue just for the namespace
+func (c *Operator) handleScrapeConfigAdd(obj interface{}) {
+	if o, ok := c.getObject(obj); ok {
+		level.Debug(c.logger).Log("msg", "ScrapeConfig added")
+		c.metrics.TriggerByCounter(monitoringv1alpha1.ScrapeConfigsKind, operator.AddEvent).Inc()
+		c.enqueueForMonitorNamespace(o.GetNamespace())
+	}
+}
+
+// TODO: Don't enqueue just for the namespace
+func (c *Operator) handleScrapeConfigUpdate(old, cur interface{}) {
+	if old.(*monitoringv1alpha1.ScrapeConfig).ResourceVersion == cur.(*monitoringv1alpha1.ScrapeConfig).ResourceVersion {
+		return
+	}
+
+	if o, ok := c.getObject(cur); ok {
+		level.Debug(c.logger).Log("msg", "ScrapeConfig updated")
+		c.metrics.TriggerByCounter(monitoringv1alpha1.ScrapeConfigsKind, operator.UpdateEvent).Inc()
+		c.enqueueForMonitorNamespace(o.GetNamespace())
+	}
+}
+
+// TODO: Don't enqueue just for the namespace
+func (c *Operator) handleScrapeConfigDelete(obj interface{}) {
+	if o, ok := c.getObject(obj); ok {
+		level.Debug(c.logger).Log("msg", "ScrapeConfig deleted")
+		c.metrics.TriggerByCounter(monitoringv1alpha1.ScrapeConfigsKind, operator.DeleteEvent).Inc()
+		c.enqueueForMonitorNamespace(o.GetNamespace())
+	}
+}
+
 // TODO: Don't enqueue just for the namespace
 func (c *Operator) handleRuleAdd(obj interface{}) {
 	o, ok := c.getObject(obj)
@@ -1073,6 +1155,15 @@ func prometheusKeyToStatefulSetKey(key string, shard int) string {
 	return fmt.Sprintf("%s/%s", keyParts[0], statefulSetNameFromPrometheusName(keyParts[1], shard))
 }
 
+func (c *Operator) handleMonitorNamespaceAdd(obj interface{}) {
+	ns := obj.(*v1.Namespace)
+
+	level.Debug(c.logger).Log("msg", "Monitor namespace added", "namespace", ns.GetName())
+	c.metrics.TriggerByCounter("Namespace", operator.AddEvent).Inc()
+
+	c.enqueueForMonitorNamespaceSelectors(nil, ns.Labels)
+}
+
 func (c *Operator) handleMonitorNamespaceUpdate(oldo, curo interface{}) {
 	old := oldo.(*v1.Namespace)
 	cur := curo.(*v1.Namespace)
@@ -1089,7 +1180,7 @@ func (c *Operator) handleMonitorNamespaceUpdate(oldo, curo interface{}) {
 	c.metrics.TriggerByCounter("Namespace", operator.UpdateEvent).Inc()
 
 	// Check for Prometheus instances selecting ServiceMonitors, PodMonitors,
-	// Probes and PrometheusRules in the namespace.
+	// Probes, ScrapeConfigs and PrometheusRules in the namespace.
 	err := c.promInfs.ListAll(labels.Everything(), func(obj interface{}) {
 		p := obj.(*monitoringv1.Prometheus)
 
@@ -1097,6 +1188,7 @@ func (c *Operator) handleMonitorNamespaceUpdate(oldo, curo interface{}) {
 			"PodMonitors":     p.Spec.PodMonitorNamespaceSelector,
 			"Probes":          p.Spec.ProbeNamespaceSelector,
 			"PrometheusRules": p.Spec.RuleNamespaceSelector,
+			"ScrapeConfigs":   p.Spec.ScrapeConfigNamespaceSelector,
 			"ServiceMonitors": p.Spec.ServiceMonitorNamespaceSelector,
 		} {
 
@@ -1123,16 +1215,146 @@ func (c *Operator) handleMonitorNamespaceUpdate(oldo, curo interface{}) {
 			"err", err,
 		)
 	}
+
+	c.enqueueForMonitorNamespaceSelectors(old.Labels, cur.Labels)
+}
+
+// enqueueForMonitorNamespaceSelectors enqueues the Prometheus instances
+// selecting ServiceMonitors, PodMonitors and Probes whose namespace selector
+// matches the old and current labels of a namespace differently.
+func (c *Operator) enqueueForMonitorNamespaceSelectors(old, cur map[string]string) {
+	namespaces := map[string]struct{}{}
+
+	checkFn := func(kind string, o metav1.Object, nsel *monitoringv1.NamespaceSelector) {
+		changed, err := namespaceSelectionHasChanged(old, cur, nsel)
+		if err != nil {
+			level.Error(c.logger).Log(
+				"err", err,
+				"name", o.GetName(),
+				"namespace", o.GetNamespace(),
+				"kind", kind,
+			)
+			return
+		}
+
+		if changed {
+			namespaces[o.GetNamespace()] = struct{}{}
+		}
+	}
+
+	for _, inf := range []struct {
+		kind    string
+		listAll func(labels.Selector, cache.AppendFunc) error
+		checkFn func(interface{})
+	}{
+		{
+			kind:    monitoringv1.ServiceMonitorsKind,
+			listAll: c.smonInfs.ListAll,
+			checkFn: func(obj interface{}) {
+				sm := obj.(*monitoringv1.ServiceMonitor)
+				checkFn(monitoringv1.ServiceMonitorsKind, sm, &sm.Spec.NamespaceSelector)
+			},
+		},
+		{
+			kind:    monitoringv1.PodMonitorsKind,
+			listAll: c.pmonInfs.ListAll,
+			checkFn: func(obj interface{}) {
+				pm := obj.(*monitoringv1.PodMonitor)
+				checkFn(monitoringv1.PodMonitorsKind, pm, &pm.Spec.NamespaceSelector)
+			},
+		},
+		{
+			kind:    monitoringv1.ProbesKind,
+			listAll: c.probeInfs.ListAll,
+			checkFn: func(obj interface{}) {
+				probe := obj.(*monitoringv1.Probe)
+				if probe.Spec.Targets.Ingress == nil {
+					return
+				}
+				checkFn(monitoringv1.ProbesKind, probe, &probe.Spec.Targets.Ingress.NamespaceSelector)
+			},
+		},
+	} {
+		if err := inf.listAll(labels.Everything(), inf.checkFn); err != nil {
+			level.Error(c.logger).Log(
+				"msg", "listing all objects from cache failed",
+				"kind", inf.kind,
+				"err", err,
+			)
+		}
+	}
+
+	for ns := range namespaces {
+		c.enqueueForMonitorNamespace(ns)
+	}
+}
+
+// namespaceSelectionHasChanged returns true if the label selector of the
+// namespace selector matches the old and current labels of a namespace
+// differently. It returns false when the label selector is ignored.
+func namespaceSelectionHasChanged(old, cur map[string]string, nsel *monitoringv1.NamespaceSelector) (bool, error) {
+	ls := nsel.LabelSelector()
+	if nsel.Any || ls == nil {
+		return false, nil
+	}
+
+	return k8sutil.LabelSelectionHasChanged(old, cur, ls)
 }
 
 // Sync implements the operator.Syncer interface.
 func (c *Operator) Sync(ctx context.Context, key string) error {
 	err := c.sync(ctx, key)
-	c.reconciliations.SetStatus(key, err)
+	c.reconciliations.SetStatus(key, err, c.degradedReasons(key)...)
 
 	return err
 }
 
+// degradedReasons returns messages describing why the Prometheus object is
+// only partially reconciled.
+func (c *Operator) degradedReasons(key string) []string {
+	pobj, err := c.promInfs.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	p := pobj.(*monitoringv1.Prometheus)
+
+	reasons := unsupportedFields(p)
+	reasons = append(reasons, validationWarnings(p.Spec.ValidateExternalLabels())...)
+	reasons = append(reasons, validationWarnings(p.Spec.ValidateExemplars())...)
+
+	return reasons
+}
+
+// validationWarnings returns the messages of the *ValidationWarning error.
+// It returns nil for other errors which are reported by the reconciliation.
+func validationWarnings(err error) []string {
+	var w *monitoringv1.ValidationWarning
+	if !errors.As(err, &w) {
+		return nil
+	}
+
+	return w.Warnings()
+}
+
+// unsupportedFields returns messages describing the fields of the Prometheus
+// object which are ignored because the Prometheus version doesn't support
+// them.
+func unsupportedFields(p *monitoringv1.Prometheus) []string {
+	version := operator.StringValOrDefault(p.Spec.Version, operator.DefaultPrometheusVersion)
+	unsupported, err := p.Spec.UnsupportedFields(version)
+	if err != nil {
+		return nil
+	}
+
+	messages := make([]string, 0, len(unsupported))
+	for _, fv := range unsupported {
+		messages = append(messages, fmt.Sprintf("%s (current version: %s)", fv, version))
+	}
+
+	return messages
+}
+
 func (c *Operator) sync(ctx context.Context, key string) error {
 	pobj, err := c.promInfs.Get(key)
 
@@ -1159,6 +1381,52 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if err := operator.ValidateImagePullSecrets(field.NewPath("spec", "imagePullSecrets"), p.Spec.ImagePullSecrets); err != nil {
+		return errors.Wrap(err, "invalid image pull secrets")
+	}
+
+	if err := operator.ValidateEnvVars(field.NewPath("spec", "env"), p.Spec.Env); err != nil {
+		return errors.Wrap(err, "invalid environment variables")
+	}
+
+	if err := operator.ValidateEnvFromSources(field.NewPath("spec", "envFrom"), p.Spec.EnvFrom); err != nil {
+		return errors.Wrap(err, "invalid environment variable sources")
+	}
+
+	if err := p.Spec.Thanos.Validate(); err != nil {
+		return errors.Wrap(err, "invalid thanos spec")
+	}
+
+	if err := p.Spec.ValidateReloadStrategy(); err != nil {
+		return errors.Wrap(err, "invalid reload strategy")
+	}
+
+	if err := p.Spec.Web.Validate(); err != nil {
+		return errors.Wrap(err, "invalid web spec")
+	}
+
+	if err := p.Spec.ValidateExternalLabels(); err != nil {
+		if !monitoringv1.IsValidationWarning(err) {
+			return errors.Wrap(err, "invalid external labels")
+		}
+		level.Warn(logger).Log("msg", "external labels validation warning", "warning", err.Error())
+	}
+
+	if err := p.Spec.ValidateExemplars(); err != nil {
+		level.Warn(logger).Log("msg", "exemplars validation warning", "warning", err.Error())
+	}
+
+	if err := p.Spec.Validate(); err != nil {
+		if !monitoringv1.IsValidationWarning(err) {
+			return errors.Wrap(err, "invalid prometheus spec")
+		}
+		level.Warn(logger).Log("msg", "prometheus validation warning", "warning", err.Error())
+	}
+
+	if err := p.Spec.ValidateSecurityContext(); err != nil {
+		level.Warn(logger).Log("msg", "inconsistent security context", "warning", err.Error())
+	}
+
 	level.Info(logger).Log("msg", "sync prometheus")
 	ruleConfigMapNames, err := c.createOrUpdateRuleConfigMaps(ctx, p)
 	if err != nil {
@@ -1220,12 +1488,12 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 			}
 		}
 
-		newSSetInputHash, err := createSSetInputHash(*p, c.config, ruleConfigMapNames, tlsAssets, existingStatefulSet.Spec)
+		newSSetInputHash, err := createSSetInputHash(*p, c.config, ruleConfigMapNames, tlsAssets, assetStore.ProjectedTokenAssets, existingStatefulSet.Spec)
 		if err != nil {
 			return err
 		}
 
-		sset, err := makeStatefulSet(logger, ssetName, *p, &c.config, ruleConfigMapNames, newSSetInputHash, int32(shard), tlsAssets.ShardNames())
+		sset, err := makeStatefulSet(logger, ssetName, *p, &c.config, ruleConfigMapNames, newSSetInputHash, int32(shard), tlsAssets.ShardNames(), assetStore.ProjectedTokenAssets)
 		if err != nil {
 			return errors.Wrap(err, "making statefulset failed")
 		}
@@ -1340,10 +1608,14 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 			},
 			ObservedGeneration: p.Generation,
 		}
-		messages []string
+		messages  []string
+		readyPods []*pod
 	)
 
-	for shard := range expectedStatefulSetShardNames(p) {
+	ssetNames := expectedStatefulSetShardNames(p)
+	pStatus.ShardsDesired = int32(len(ssetNames))
+
+	for shard := range ssetNames {
 		ssetName := prometheusKeyToStatefulSetKey(key, shard)
 		logger := log.With(logger, "statefulset", ssetName, "shard", shard)
 
@@ -1368,6 +1640,7 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 			return errors.Wrap(err, "failed to retrieve statefulset state")
 		}
 
+		pStatus.ShardsObserved++
 		pStatus.Replicas += int32(len(stsReporter.pods))
 		pStatus.UpdatedReplicas += int32(len(stsReporter.Updated()))
 		pStatus.AvailableReplicas += int32(len(stsReporter.Ready()))
@@ -1384,6 +1657,8 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 			},
 		)
 
+		readyPods = append(readyPods, stsReporter.Ready()...)
+
 		if len(stsReporter.Ready()) == len(stsReporter.pods) {
 			// All pods are ready (or the desired number of replicas is zero).
 			continue
@@ -1406,6 +1681,16 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 
 	availableCondition.Message = strings.Join(messages, "\n")
 
+	if reportsThanosSidecarStatus(p) {
+		thanosStatus, err := thanosSidecarStatus(ctx, c.kclient, &c.thanosUploadFailures, readyPods)
+		if err != nil {
+			// Keep the last known status.
+			level.Warn(logger).Log("msg", "failed to retrieve Thanos sidecar status", "err", err)
+			thanosStatus = p.Status.ThanosSidecar
+		}
+		pStatus.ThanosSidecar = thanosStatus
+	}
+
 	// Compute the Reconciled ConditionType.
 	reconciledCondition := monitoringv1.PrometheusCondition{
 		Type:   monitoringv1.PrometheusReconciled,
@@ -1421,8 +1706,11 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 		reconciledCondition.Reason = "NotFound"
 		reconciledCondition.Message = fmt.Sprintf("object %q not found", key)
 	} else {
-		if !reconciliationStatus.Ok() {
+		switch {
+		case !reconciliationStatus.Ok():
 			reconciledCondition.Status = monitoringv1.PrometheusConditionFalse
+		case reconciliationStatus.Degraded():
+			reconciledCondition.Status = monitoringv1.PrometheusConditionDegraded
 		}
 		reconciledCondition.Reason = reconciliationStatus.Reason()
 		reconciledCondition.Message = reconciliationStatus.Message()
@@ -1476,12 +1764,12 @@ func logDeprecatedFields(logger log.Logger, p *monitoringv1.Prometheus) {
 		}
 	}
 
-	if p.Spec.ServiceMonitorSelector == nil && p.Spec.PodMonitorSelector == nil && p.Spec.ProbeSelector == nil {
-		level.Warn(logger).Log("msg", "neither serviceMonitorSelector nor podMonitorSelector, nor probeSelector specified. Custom configuration is deprecated, use additionalScrapeConfigs instead")
+	if p.Spec.ServiceMonitorSelector == nil && p.Spec.PodMonitorSelector == nil && p.Spec.ProbeSelector == nil && p.Spec.ScrapeConfigSelector == nil {
+		level.Warn(logger).Log("msg", "neither serviceMonitorSelector nor podMonitorSelector, nor probeSelector, nor scrapeConfigSelector specified. Custom configuration is deprecated, use additionalScrapeConfigs instead")
 	}
 }
 
-func createSSetInputHash(p monitoringv1.Prometheus, c operator.Config, ruleConfigMapNames []string, tlsAssets *operator.ShardedSecret, ssSpec appsv1.StatefulSetSpec) (string, error) {
+func createSSetInputHash(p monitoringv1.Prometheus, c operator.Config, ruleConfigMapNames []string, tlsAssets *operator.ShardedSecret, projectedTokens map[string]monitoringv1.ProjectedToken, ssSpec appsv1.StatefulSetSpec) (string, error) {
 	var http2 *bool
 	if p.Spec.Web != nil && p.Spec.Web.WebConfigFileFields.HTTPConfig != nil {
 		http2 = p.Spec.Web.WebConfigFileFields.HTTPConfig.HTTP2
@@ -1496,6 +1784,7 @@ func createSSetInputHash(p monitoringv1.Prometheus, c operator.Config, ruleConfi
 		StatefulSetSpec       appsv1.StatefulSetSpec
 		RuleConfigMaps        []string `hash:"set"`
 		Assets                []string `hash:"set"`
+		ProjectedTokens       map[string]monitoringv1.ProjectedToken
 	}{
 		PrometheusLabels:      p.Labels,
 		PrometheusAnnotations: p.Annotations,
@@ -1505,6 +1794,7 @@ func createSSetInputHash(p monitoringv1.Prometheus, c operator.Config, ruleConfi
 		StatefulSetSpec:       ssSpec,
 		RuleConfigMaps:        ruleConfigMapNames,
 		Assets:                tlsAssets.ShardNames(),
+		ProjectedTokens:       projectedTokens,
 	},
 		nil,
 	)
@@ -1639,8 +1929,11 @@ func newStatefulSetReporter(ctx context.Context, kclient kubernetes.Interface, s
 func Status(ctx context.Context, kclient kubernetes.Interface, p *monitoringv1.Prometheus) (monitoringv1.PrometheusStatus, []v1.Pod, error) {
 	res := monitoringv1.PrometheusStatus{Paused: p.Spec.Paused}
 
+	ssetNames := expectedStatefulSetShardNames(p)
+	res.ShardsDesired = int32(len(ssetNames))
+
 	var oldPods []v1.Pod
-	for _, ssetName := range expectedStatefulSetShardNames(p) {
+	for _, ssetName := range ssetNames {
 		sset, err := kclient.AppsV1().StatefulSets(p.Namespace).Get(ctx, ssetName, metav1.GetOptions{})
 		if err != nil {
 			return monitoringv1.PrometheusStatus{}, nil, errors.Wrapf(err, "failed to retrieve statefulset %s/%s", p.Namespace, ssetName)
@@ -1651,6 +1944,7 @@ func Status(ctx context.Context, kclient kubernetes.Interface, p *monitoringv1.P
 			return monitoringv1.PrometheusStatus{}, nil, errors.Wrapf(err, "failed to retrieve pods state for statefulset %s/%s", p.Namespace, ssetName)
 		}
 
+		res.ShardsObserved++
 		res.Replicas += int32(len(stsReporter.pods))
 		res.UpdatedReplicas += int32(len(stsReporter.Updated()))
 		res.AvailableReplicas += int32(len(stsReporter.Ready()))
@@ -1689,6 +1983,26 @@ func (c *Operator) loadConfigFromSecret(sks *v1.SecretKeySelector, s *v1.SecretL
 	return nil, nil
 }
 
+// validateAdditionalScrapeConfigs checks that the additional scrape
+// configurations can be loaded by Prometheus.
+// The service discovery implementations aren't registered in the operator
+// which means that unknown fields are ignored and the service discovery
+// configurations aren't validated.
+func validateAdditionalScrapeConfigs(b []byte) error {
+	var scrapeConfigs []*promconfig.ScrapeConfig
+	if err := yaml.Unmarshal(b, &scrapeConfigs); err != nil {
+		return err
+	}
+
+	for i, sc := range scrapeConfigs {
+		if sc == nil {
+			return errors.Errorf("scrape configuration at index %d must not be empty", i)
+		}
+	}
+
+	return nil
+}
+
 func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *monitoringv1.Prometheus, ruleConfigMapNames []string, store *assets.Store) error {
 	// If no service or pod monitor selectors are configured, the user wants to
 	// manage configuration themselves. Do create an empty Secret if it doesn't
@@ -1729,6 +2043,11 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 	if err != nil {
 		return errors.Wrap(err, "selecting Probes failed")
 	}
+
+	scrapeConfigs, err := c.selectScrapeConfigs(ctx, p, store)
+	if err != nil {
+		return errors.Wrap(err, "selecting ScrapeConfigs failed")
+	}
 	sClient := c.kclient.CoreV1().Secrets(p.Namespace)
 	SecretsInPromNS, err := sClient.List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -1736,6 +2055,9 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 	}
 
 	for i, remote := range p.Spec.RemoteRead {
+		if err := remote.EffectiveRemoteTimeout().Validate(); err != nil {
+			return errors.Wrapf(err, "remote read %d: invalid remoteTimeout", i)
+		}
 		if err := store.AddBasicAuth(ctx, p.GetNamespace(), remote.BasicAuth, fmt.Sprintf("remoteRead/%d", i)); err != nil {
 			return errors.Wrapf(err, "remote read %d", i)
 		}
@@ -1748,11 +2070,34 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 		if err := store.AddAuthorizationCredentials(ctx, p.GetNamespace(), remote.Authorization, fmt.Sprintf("remoteRead/auth/%d", i)); err != nil {
 			return errors.Wrapf(err, "remote read %d", i)
 		}
+		proxyConfig := remote.ProxyConfig()
+		if err := store.AddProxyConfig(ctx, p.GetNamespace(), &proxyConfig, fmt.Sprintf("remoteRead/%d", i)); err != nil {
+			return errors.Wrapf(err, "remote read %d", i)
+		}
+	}
+
+	promVersion := operator.StringValOrDefault(p.Spec.Version, operator.DefaultPrometheusVersion)
+	version, err := semver.ParseTolerant(promVersion)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse Prometheus version")
+	}
+
+	if err := monitoringv1.ValidateScrapeProtocols(p.Spec.ScrapeProtocols); err != nil {
+		return errors.Wrap(err, "invalid scrapeProtocols")
+	}
+
+	if p.Spec.ScrapeFallbackProtocol != nil {
+		if err := p.Spec.ScrapeFallbackProtocol.Validate(); err != nil {
+			return errors.Wrap(err, "invalid scrapeFallbackProtocol")
+		}
 	}
 
 	for i, remote := range p.Spec.RemoteWrite {
-		if err := validateRemoteWriteSpec(remote); err != nil {
-			return errors.Wrapf(err, "remote write %d", i)
+		if err := validateRemoteWriteSpec(remote, version); err != nil {
+			if !monitoringv1.IsValidationWarning(err) {
+				return errors.Wrapf(err, "remote write %d", i)
+			}
+			level.Warn(c.logger).Log("msg", "remote write validation warning", "remote_write", i, "warning", err.Error(), "prometheus", p.Name, "namespace", p.Namespace)
 		}
 		key := fmt.Sprintf("remoteWrite/%d", i)
 		if err := store.AddBasicAuth(ctx, p.GetNamespace(), remote.BasicAuth, key); err != nil {
@@ -1770,6 +2115,22 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 		if err := store.AddSigV4(ctx, p.GetNamespace(), remote.Sigv4, key); err != nil {
 			return errors.Wrapf(err, "remote write %d", i)
 		}
+		proxyConfig := remote.ProxyConfig()
+		if err := store.AddProxyConfig(ctx, p.GetNamespace(), &proxyConfig, key); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+	}
+
+	for _, sc := range p.Spec.ScrapeClasses {
+		if err := store.AddBasicAuth(ctx, p.GetNamespace(), sc.DefaultBasicAuth, fmt.Sprintf("scrapeClass/%s", sc.Name)); err != nil {
+			return errors.Wrapf(err, "scrape class %q", sc.Name)
+		}
+	}
+
+	if p.Spec.TracingConfig != nil {
+		if err := store.AddTLSConfig(ctx, p.GetNamespace(), p.Spec.TracingConfig.TLSConfig); err != nil {
+			return errors.Wrap(err, "tracing config")
+		}
 	}
 
 	if p.Spec.APIServerConfig != nil {
@@ -1779,12 +2140,32 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 		if err := store.AddAuthorizationCredentials(ctx, p.GetNamespace(), p.Spec.APIServerConfig.Authorization, "apiserver/auth"); err != nil {
 			return errors.Wrapf(err, "apiserver config")
 		}
+		if err := store.AddProxyConfig(ctx, p.GetNamespace(), &p.Spec.APIServerConfig.ProxyConfig, "apiserver"); err != nil {
+			return errors.Wrap(err, "apiserver config")
+		}
 	}
 	if p.Spec.Alerting != nil {
 		for i, am := range p.Spec.Alerting.Alertmanagers {
 			if err := store.AddSafeAuthorizationCredentials(ctx, p.GetNamespace(), am.Authorization, fmt.Sprintf("alertmanager/auth/%d", i)); err != nil {
 				return errors.Wrapf(err, "apiserver config")
 			}
+			if am.BearerTokenSecret != nil {
+				if err := store.AddBearerToken(ctx, p.GetNamespace(), *am.BearerTokenSecret, fmt.Sprintf("alertmanager/%d", i)); err != nil {
+					return errors.Wrapf(err, "alertmanagers[%d]", i)
+				}
+			}
+		}
+
+		for i, rc := range p.Spec.Alerting.AlertRelabelConfigs {
+			if err := rc.Validate(); err != nil {
+				return errors.Wrapf(err, "alertRelabelConfigs[%d]", i)
+			}
+
+			if rc.Action != "" {
+				if err := validateRelabelConfig(*p, rc); err != nil {
+					return errors.Wrapf(err, "alertRelabelConfigs[%d]", i)
+				}
+			}
 		}
 	}
 
@@ -1792,6 +2173,12 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 	if err != nil {
 		return errors.Wrap(err, "loading additional scrape configs from Secret failed")
 	}
+	if p.Spec.AdditionalScrapeConfigsInline != nil {
+		additionalScrapeConfigs = []byte(*p.Spec.AdditionalScrapeConfigsInline)
+		if err := validateAdditionalScrapeConfigs(additionalScrapeConfigs); err != nil {
+			return errors.Wrap(err, "invalid additionalScrapeConfigsInline")
+		}
+	}
 	additionalAlertRelabelConfigs, err := c.loadConfigFromSecret(p.Spec.AdditionalAlertRelabelConfigs, SecretsInPromNS)
 	if err != nil {
 		return errors.Wrap(err, "loading additional alert relabel configs from Secret failed")
@@ -1812,6 +2199,7 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 		smons,
 		pmons,
 		bmons,
+		scrapeConfigs,
 		store,
 		additionalScrapeConfigs,
 		additionalAlertRelabelConfigs,
@@ -1963,9 +2351,16 @@ func (c *Operator) selectServiceMonitors(ctx context.Context, p *monitoringv1.Pr
 	var rejected int
 	res := make(map[string]*monitoringv1.ServiceMonitor, len(serviceMonitors))
 	for namespaceAndName, sm := range serviceMonitors {
-		var err error
+		_, err := p.Spec.ResolveScrapeClass(sm.Spec.ScrapeClassName)
+		if err == nil {
+			err = sm.Spec.Validate()
+		}
 
 		for i, endpoint := range sm.Spec.Endpoints {
+			if err != nil {
+				break
+			}
+
 			// If denied by Prometheus spec, filter out all service monitors that access
 			// the file system.
 			if p.Spec.ArbitraryFSAccessThroughSMs.Deny {
@@ -1974,6 +2369,10 @@ func (c *Operator) selectServiceMonitors(ctx context.Context, p *monitoringv1.Pr
 				}
 			}
 
+			if _, err = endpoint.ResolveAuth(); err != nil {
+				break
+			}
+
 			smKey := fmt.Sprintf("serviceMonitor/%s/%s/%d", sm.GetNamespace(), sm.GetName(), i)
 
 			if err = store.AddBearerToken(ctx, sm.GetNamespace(), endpoint.BearerTokenSecret, smKey); err != nil {
@@ -1994,15 +2393,39 @@ func (c *Operator) selectServiceMonitors(ctx context.Context, p *monitoringv1.Pr
 				break
 			}
 
+			if err = store.AddProxyConfig(ctx, sm.GetNamespace(), &endpoint.ProxyConfig, smKey); err != nil {
+				break
+			}
+
 			smAuthKey := fmt.Sprintf("serviceMonitor/auth/%s/%s/%d", sm.GetNamespace(), sm.GetName(), i)
 			if err = store.AddSafeAuthorizationCredentials(ctx, sm.GetNamespace(), endpoint.Authorization, smAuthKey); err != nil {
 				break
 			}
 
+			if endpoint.BearerTokenProjected != nil {
+				if err = store.AddProjectedToken(endpoint.BearerTokenProjected); err != nil {
+					break
+				}
+			}
+
 			if err = validateScrapeIntervalAndTimeout(p, endpoint.Interval, endpoint.ScrapeTimeout); err != nil {
 				break
 			}
 
+			if err = validateBodySizeLimit(endpoint.BodySizeLimit); err != nil {
+				break
+			}
+
+			if err = monitoringv1.ValidateScrapeProtocols(endpoint.ScrapeProtocols); err != nil {
+				break
+			}
+
+			if endpoint.ScrapeFallbackProtocol != nil {
+				if err = endpoint.ScrapeFallbackProtocol.Validate(); err != nil {
+					break
+				}
+			}
+
 			for _, rl := range endpoint.RelabelConfigs {
 				if rl.Action != "" {
 					if err = validateRelabelConfig(*p, *rl); err != nil {
@@ -2020,6 +2443,11 @@ func (c *Operator) selectServiceMonitors(ctx context.Context, p *monitoringv1.Pr
 			}
 		}
 
+		matched := true
+		if err == nil {
+			matched, err = c.resolveNamespaceSelector(p, &sm.Spec.NamespaceSelector)
+		}
+
 		if err != nil {
 			rejected++
 			level.Warn(c.logger).Log(
@@ -2032,6 +2460,16 @@ func (c *Operator) selectServiceMonitors(ctx context.Context, p *monitoringv1.Pr
 			continue
 		}
 
+		if !matched {
+			level.Debug(c.logger).Log(
+				"msg", "skipping servicemonitor because no namespace matches its namespace selector",
+				"servicemonitor", namespaceAndName,
+				"namespace", p.Namespace,
+				"prometheus", p.Name,
+			)
+			continue
+		}
+
 		res[namespaceAndName] = sm
 	}
 
@@ -2096,9 +2534,17 @@ func (c *Operator) selectPodMonitors(ctx context.Context, p *monitoringv1.Promet
 	var rejected int
 	res := make(map[string]*monitoringv1.PodMonitor, len(podMonitors))
 	for namespaceAndName, pm := range podMonitors {
-		var err error
+		_, err := p.Spec.ResolveScrapeClass(pm.Spec.ScrapeClassName)
 
 		for i, endpoint := range pm.Spec.PodMetricsEndpoints {
+			if err != nil {
+				break
+			}
+
+			if _, err = endpoint.ResolveAuth(); err != nil {
+				break
+			}
+
 			pmKey := fmt.Sprintf("podMonitor/%s/%s/%d", pm.GetNamespace(), pm.GetName(), i)
 
 			if err = store.AddBearerToken(ctx, pm.GetNamespace(), endpoint.BearerTokenSecret, pmKey); err != nil {
@@ -2119,6 +2565,10 @@ func (c *Operator) selectPodMonitors(ctx context.Context, p *monitoringv1.Promet
 				break
 			}
 
+			if err = store.AddProxyConfig(ctx, pm.GetNamespace(), &endpoint.ProxyConfig, pmKey); err != nil {
+				break
+			}
+
 			pmAuthKey := fmt.Sprintf("podMonitor/auth/%s/%s/%d", pm.GetNamespace(), pm.GetName(), i)
 			if err = store.AddSafeAuthorizationCredentials(ctx, pm.GetNamespace(), endpoint.Authorization, pmAuthKey); err != nil {
 				break
@@ -2128,6 +2578,27 @@ func (c *Operator) selectPodMonitors(ctx context.Context, p *monitoringv1.Promet
 				break
 			}
 
+			if err = validateBodySizeLimit(endpoint.BodySizeLimit); err != nil {
+				break
+			}
+
+			if err = monitoringv1.ValidateScrapeProtocols(endpoint.ScrapeProtocols); err != nil {
+				break
+			}
+
+			if endpoint.ScrapeFallbackProtocol != nil {
+				if err = endpoint.ScrapeFallbackProtocol.Validate(); err != nil {
+					break
+				}
+			}
+
+			//nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
+			if endpoint.TargetPort != nil && monitoringv1.PortAsString(*endpoint.TargetPort) != "" {
+				if err = monitoringv1.ValidatePort(*endpoint.TargetPort); err != nil {
+					break
+				}
+			}
+
 			for _, rl := range endpoint.RelabelConfigs {
 				if rl.Action != "" {
 					if err = validateRelabelConfig(*p, *rl); err != nil {
@@ -2145,6 +2616,11 @@ func (c *Operator) selectPodMonitors(ctx context.Context, p *monitoringv1.Promet
 			}
 		}
 
+		matched := true
+		if err == nil {
+			matched, err = c.resolveNamespaceSelector(p, &pm.Spec.NamespaceSelector)
+		}
+
 		if err != nil {
 			rejected++
 			level.Warn(c.logger).Log(
@@ -2157,6 +2633,16 @@ func (c *Operator) selectPodMonitors(ctx context.Context, p *monitoringv1.Promet
 			continue
 		}
 
+		if !matched {
+			level.Debug(c.logger).Log(
+				"msg", "skipping podmonitor because no namespace matches its namespace selector",
+				"podmonitor", namespaceAndName,
+				"namespace", p.Namespace,
+				"prometheus", p.Name,
+			)
+			continue
+		}
+
 		res[namespaceAndName] = pm
 	}
 
@@ -2232,7 +2718,45 @@ func (c *Operator) selectProbes(ctx context.Context, p *monitoringv1.Prometheus,
 			)
 		}
 
+		if _, err = p.Spec.ResolveScrapeClass(probe.Spec.ScrapeClassName); err != nil {
+			rejectFn(probe, err)
+			continue
+		}
+
 		if err = probe.Spec.Targets.Validate(); err != nil {
+			if !monitoringv1.IsValidationWarning(err) {
+				rejectFn(probe, err)
+				continue
+			}
+
+			level.Warn(c.logger).Log(
+				"msg", "probe configuration warning",
+				"warning", err.Error(),
+				"probe", probe.GetName(),
+				"namespace", probe.GetNamespace(),
+				"prometheus", p.Name,
+			)
+		}
+
+		if probe.Spec.Targets.Ingress != nil {
+			matched, err := c.resolveNamespaceSelector(p, &probe.Spec.Targets.Ingress.NamespaceSelector)
+			if err != nil {
+				rejectFn(probe, err)
+				continue
+			}
+
+			if !matched {
+				level.Debug(c.logger).Log(
+					"msg", "skipping probe because no namespace matches its ingress namespace selector",
+					"probe", probeName,
+					"namespace", p.Namespace,
+					"prometheus", p.Name,
+				)
+				continue
+			}
+		}
+
+		if _, err = probe.Spec.ResolveAuth(); err != nil {
 			rejectFn(probe, err)
 			continue
 		}
@@ -2270,6 +2794,11 @@ func (c *Operator) selectProbes(ctx context.Context, p *monitoringv1.Prometheus,
 			continue
 		}
 
+		if err = validateBodySizeLimit(probe.Spec.BodySizeLimit); err != nil {
+			rejectFn(probe, err)
+			continue
+		}
+
 		for _, rl := range probe.Spec.MetricRelabelConfigs {
 			if rl.Action != "" {
 				if err = validateRelabelConfig(*p, *rl); err != nil {
@@ -2278,11 +2807,11 @@ func (c *Operator) selectProbes(ctx context.Context, p *monitoringv1.Prometheus,
 				}
 			}
 		}
-		if err = validateProberURL(probe.Spec.ProberSpec.URL); err != nil {
-			err := errors.Wrapf(err, "%s url specified in proberSpec is invalid, it should be of the format `hostname` or `hostname:port`", probe.Spec.ProberSpec.URL)
-			rejectFn(probe, err)
+		if err = probe.Spec.ProberSpec.Validate(); err != nil {
+			rejectFn(probe, errors.Wrap(err, "invalid proberSpec"))
 			continue
 		}
+
 		res[probeName] = probe
 	}
 
@@ -2300,6 +2829,189 @@ func (c *Operator) selectProbes(ctx context.Context, p *monitoringv1.Prometheus,
 	return res, nil
 }
 
+func (c *Operator) selectScrapeConfigs(ctx context.Context, p *monitoringv1.Prometheus, store *assets.Store) (map[string]*monitoringv1alpha1.ScrapeConfig, error) {
+	if c.sconInfs == nil || p.Spec.ScrapeConfigSelector == nil {
+		return nil, nil
+	}
+
+	namespaces := []string{}
+	// Selectors might overlap. Deduplicate them along the keyFunc.
+	scrapeConfigs := make(map[string]*monitoringv1alpha1.ScrapeConfig)
+
+	sConSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ScrapeConfigSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	// If 'ScrapeConfigNamespaceSelector' is nil only check own namespace.
+	if p.Spec.ScrapeConfigNamespaceSelector == nil {
+		namespaces = append(namespaces, p.Namespace)
+	} else {
+		sConNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ScrapeConfigNamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces, err = c.listMatchingNamespaces(sConNSSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	level.Debug(c.logger).Log("msg", "filtering namespaces to select ScrapeConfigs from", "namespaces", strings.Join(namespaces, ","), "namespace", p.Namespace, "prometheus", p.Name)
+
+	for _, ns := range namespaces {
+		err := c.sconInfs.ListAllByNamespace(ns, sConSelector, func(obj interface{}) {
+			if k, ok := c.keyFunc(obj); ok {
+				sc := obj.(*monitoringv1alpha1.ScrapeConfig).DeepCopy()
+				if err := k8sutil.AddTypeInformationToObject(sc); err != nil {
+					level.Error(c.logger).Log("msg", "failed to set ScrapeConfig type information", "namespace", ns, "err", err)
+					return
+				}
+				scrapeConfigs[k] = sc
+			}
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list scrape configs in namespace %s", ns)
+		}
+	}
+
+	var rejected int
+	res := make(map[string]*monitoringv1alpha1.ScrapeConfig, len(scrapeConfigs))
+
+	for scName, sc := range scrapeConfigs {
+		rejectFn := func(sc *monitoringv1alpha1.ScrapeConfig, err error) {
+			rejected++
+			level.Warn(c.logger).Log(
+				"msg", "skipping scrapeconfig",
+				"error", err.Error(),
+				"scrapeconfig", fmt.Sprintf("%s/%s", sc.GetNamespace(), sc.GetName()),
+				"namespace", p.Namespace,
+				"prometheus", p.Name,
+			)
+		}
+
+		if err = sc.Spec.Validate(); err != nil {
+			rejectFn(sc, err)
+			continue
+		}
+
+		if err = c.addScrapeConfigAssets(ctx, sc, store); err != nil {
+			rejectFn(sc, err)
+			continue
+		}
+
+		var scrapeInterval, scrapeTimeout monitoringv1.Duration
+		if sc.Spec.ScrapeInterval != nil {
+			scrapeInterval = *sc.Spec.ScrapeInterval
+		}
+		if sc.Spec.ScrapeTimeout != nil {
+			scrapeTimeout = *sc.Spec.ScrapeTimeout
+		}
+		if err = validateScrapeIntervalAndTimeout(p, scrapeInterval, scrapeTimeout); err != nil {
+			rejectFn(sc, err)
+			continue
+		}
+
+		if err = validateRelabelConfigs(p, sc.Spec.RelabelConfigs); err != nil {
+			rejectFn(sc, errors.Wrap(err, "relabelings"))
+			continue
+		}
+
+		if err = validateRelabelConfigs(p, sc.Spec.MetricRelabelConfigs); err != nil {
+			rejectFn(sc, errors.Wrap(err, "metricRelabelings"))
+			continue
+		}
+
+		res[scName] = sc
+	}
+
+	scKeys := make([]string, 0)
+	for k := range res {
+		scKeys = append(scKeys, k)
+	}
+	level.Debug(c.logger).Log("msg", "selected ScrapeConfigs", "scrapeconfigs", strings.Join(scKeys, ","), "namespace", p.Namespace, "prometheus", p.Name)
+
+	if pKey, ok := c.keyFunc(p); ok {
+		c.metrics.SetSelectedResources(pKey, monitoringv1alpha1.ScrapeConfigsKind, len(res))
+		c.metrics.SetRejectedResources(pKey, monitoringv1alpha1.ScrapeConfigsKind, rejected)
+	}
+
+	return res, nil
+}
+
+// addScrapeConfigAssets loads the credentials and TLS assets referenced by
+// the ScrapeConfig into the store.
+func (c *Operator) addScrapeConfigAssets(ctx context.Context, sc *monitoringv1alpha1.ScrapeConfig, store *assets.Store) error {
+	ns := sc.GetNamespace()
+
+	if err := store.AddBasicAuth(ctx, ns, sc.Spec.BasicAuth, fmt.Sprintf("scrapeconfig/%s/%s", ns, sc.GetName())); err != nil {
+		return err
+	}
+
+	if err := store.AddSafeAuthorizationCredentials(ctx, ns, sc.Spec.Authorization, fmt.Sprintf("scrapeconfig/auth/%s/%s", ns, sc.GetName())); err != nil {
+		return err
+	}
+
+	if err := store.AddSafeTLSConfig(ctx, ns, sc.Spec.TLSConfig); err != nil {
+		return err
+	}
+
+	for i, config := range sc.Spec.HTTPSDConfigs {
+		if err := store.AddBasicAuth(ctx, ns, config.BasicAuth, fmt.Sprintf("scrapeconfig/%s/%s/httpsdconfig/%d", ns, sc.GetName(), i)); err != nil {
+			return errors.Wrapf(err, "httpSDConfigs[%d]", i)
+		}
+
+		if err := store.AddSafeAuthorizationCredentials(ctx, ns, config.Authorization, fmt.Sprintf("scrapeconfig/auth/%s/%s/httpsdconfig/%d", ns, sc.GetName(), i)); err != nil {
+			return errors.Wrapf(err, "httpSDConfigs[%d]", i)
+		}
+
+		if err := store.AddSafeTLSConfig(ctx, ns, config.TLSConfig); err != nil {
+			return errors.Wrapf(err, "httpSDConfigs[%d]", i)
+		}
+	}
+
+	for i, config := range sc.Spec.ConsulSDConfigs {
+		key := fmt.Sprintf("scrapeconfig/%s/%s/consulsdconfig/%d", ns, sc.GetName(), i)
+
+		if config.TokenRef != nil {
+			if err := store.AddBearerToken(ctx, ns, *config.TokenRef, fmt.Sprintf("scrapeconfig/token/%s/%s/consulsdconfig/%d", ns, sc.GetName(), i)); err != nil {
+				return errors.Wrapf(err, "consulSDConfigs[%d]", i)
+			}
+		}
+
+		if err := store.AddBasicAuth(ctx, ns, config.BasicAuth, key); err != nil {
+			return errors.Wrapf(err, "consulSDConfigs[%d]", i)
+		}
+
+		if err := store.AddSafeAuthorizationCredentials(ctx, ns, config.Authorization, fmt.Sprintf("scrapeconfig/auth/%s/%s/consulsdconfig/%d", ns, sc.GetName(), i)); err != nil {
+			return errors.Wrapf(err, "consulSDConfigs[%d]", i)
+		}
+
+		if err := store.AddOAuth2(ctx, ns, config.OAuth2, key); err != nil {
+			return errors.Wrapf(err, "consulSDConfigs[%d]", i)
+		}
+
+		if err := store.AddSafeTLSConfig(ctx, ns, config.TLSConfig); err != nil {
+			return errors.Wrapf(err, "consulSDConfigs[%d]", i)
+		}
+	}
+
+	return nil
+}
+
+func validateBodySizeLimit(limit *monitoringv1.ByteSize) error {
+	if limit == nil {
+		return nil
+	}
+
+	if err := operator.ValidateSizeField(string(*limit)); err != nil {
+		return errors.Wrap(err, "invalid bodySizeLimit")
+	}
+
+	return nil
+}
+
 func testForArbitraryFSAccess(e monitoringv1.Endpoint) error {
 	if e.BearerTokenFile != "" {
 		return errors.New("it accesses file system via bearer token file which Prometheus specification prohibits")
@@ -2317,6 +3029,51 @@ func testForArbitraryFSAccess(e monitoringv1.Endpoint) error {
 	return nil
 }
 
+// resolveNamespaceSelector translates the label selector of the namespace
+// selector into the list of matching namespace names. It is a no-op when
+// `any` is true or when the Prometheus object ignores namespace selectors.
+// It returns false if no namespace matches the label selector.
+func (c *Operator) resolveNamespaceSelector(p *monitoringv1.Prometheus, nsel *monitoringv1.NamespaceSelector) (bool, error) {
+	if err := nsel.Validate(); err != nil {
+		if !monitoringv1.IsValidationWarning(err) {
+			return false, errors.Wrap(err, "invalid namespaceSelector")
+		}
+
+		level.Warn(c.logger).Log(
+			"msg", "namespace selector configuration warning",
+			"warning", err.Error(),
+			"namespace", p.Namespace,
+			"prometheus", p.Name,
+		)
+	}
+
+	ls := nsel.LabelSelector()
+	if ls == nil || nsel.Any || p.Spec.IgnoreNamespaceSelectors {
+		return true, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(ls)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid namespaceSelector")
+	}
+
+	namespaces, err := c.listMatchingNamespaces(selector)
+	if err != nil {
+		return false, err
+	}
+
+	if len(namespaces) == 0 {
+		return false, nil
+	}
+
+	sort.Strings(namespaces)
+	nsel.MatchNames = namespaces
+	nsel.MatchLabels = nil
+	nsel.MatchExpressions = nil
+
+	return true, nil
+}
+
 // listMatchingNamespaces lists all the namespaces that match the provided
 // selector.
 func (c *Operator) listMatchingNamespaces(selector labels.Selector) ([]string, error) {
@@ -2331,25 +3088,78 @@ func (c *Operator) listMatchingNamespaces(selector labels.Selector) ([]string, e
 }
 
 // validateRemoteWriteSpec checks that mutually exclusive configurations are not
-// included in the Prometheus remoteWrite configuration section.
+// included in the Prometheus remoteWrite configuration section. It delegates
+// the validation of the entry to monitoringv1.ValidateRemoteWrites() and
+// checks the fields which depend on the Prometheus version.
 // Reference:
 // https://github.com/prometheus/prometheus/blob/main/docs/configuration/configuration.md#remote_write
-func validateRemoteWriteSpec(spec monitoringv1.RemoteWriteSpec) error {
-	var nonNilFields []string
-	for k, v := range map[string]interface{}{
-		"basicAuth":     spec.BasicAuth,
-		"oauth2":        spec.OAuth2,
-		"authorization": spec.Authorization,
-		"sigv4":         spec.Sigv4,
-	} {
-		if reflect.ValueOf(v).IsNil() {
-			continue
+func validateRemoteWriteSpec(spec monitoringv1.RemoteWriteSpec, version semver.Version) error {
+	// The fields not supported by the Prometheus version are ignored by the
+	// config generator hence the version isn't passed to the API validation.
+	cpf := monitoringv1.CommonPrometheusFields{RemoteWrite: []monitoringv1.RemoteWriteSpec{spec}}
+	if errs := cpf.ValidateRemoteWrites(); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	if spec.MessageVersion != nil && *spec.MessageVersion == "V2.0" && version.LT(semver.MustParse("2.54.0")) {
+		return errors.Errorf("messageVersion %q is only supported from Prometheus version 2.54.0", *spec.MessageVersion)
+	}
+
+	if _, err := spec.QueueConfig.EffectiveRetryOnRateLimit(version.String()); err != nil {
+		return errors.Wrap(err, "invalid queueConfig")
+	}
+
+	var warnings []string
+	for i := range spec.WriteRelabelConfigs {
+		if rc := &spec.WriteRelabelConfigs[i]; dropsAllSeries(rc) {
+			warnings = append(warnings, fmt.Sprintf("writeRelabelConfigs[%d]: the %s relabeling drops all series", i, rc.Action))
 		}
-		nonNilFields = append(nonNilFields, fmt.Sprintf("%q", k))
 	}
 
-	if len(nonNilFields) > 1 {
-		return errors.Errorf("%s can't be set at the same time, at most one of them must be defined", strings.Join(nonNilFields, " and "))
+	if err := spec.QueueConfig.Validate(); monitoringv1.IsValidationWarning(err) {
+		warnings = append(warnings, fmt.Sprintf("queueConfig: %s", err))
+	}
+
+	if len(warnings) > 0 {
+		return monitoringv1.NewValidationWarning(warnings...)
+	}
+
+	return nil
+}
+
+// dropsAllSeries returns true when the relabel config obviously drops all
+// series: a keep action without source labels whose regex doesn't match the
+// empty string or a drop action whose regex matches any value.
+func dropsAllSeries(rc *monitoringv1.RelabelConfig) bool {
+	regex := rc.Regex
+	if regex == "" {
+		regex = "(.*)"
+	}
+
+	re, err := regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(rc.Action) {
+	case string(relabel.Keep):
+		return len(rc.SourceLabels) == 0 && !re.MatchString("")
+	case string(relabel.Drop):
+		return regex == "(.*)" || regex == ".*"
+	}
+
+	return false
+}
+
+func validateRelabelConfigs(p *monitoringv1.Prometheus, rcs []*monitoringv1.RelabelConfig) error {
+	for i, rc := range rcs {
+		if rc == nil || rc.Action == "" {
+			continue
+		}
+
+		if err := validateRelabelConfig(*p, *rc); err != nil {
+			return errors.Wrapf(err, "relabel config %d", i)
+		}
 	}
 
 	return nil
@@ -2368,8 +3178,8 @@ func validateRelabelConfig(p monitoringv1.Prometheus, rc monitoringv1.RelabelCon
 		return errors.Errorf("%s relabel action is only supported from Prometheus version 2.36.0", rc.Action)
 	}
 
-	if _, err := relabel.NewRegexp(rc.Regex); err != nil {
-		return errors.Wrapf(err, "invalid regex %s for relabel configuration", rc.Regex)
+	if err := rc.Validate(); err != nil {
+		return err
 	}
 
 	if rc.Modulus == 0 && rc.Action == string(relabel.HashMod) {
@@ -2414,22 +3224,6 @@ func validateRelabelConfig(p monitoringv1.Prometheus, rc monitoringv1.RelabelCon
 	return nil
 }
 
-func validateProberURL(url string) error {
-	hostPort := strings.Split(url, ":")
-
-	if !govalidator.IsHost(hostPort[0]) {
-		return errors.Errorf("invalid host: %q", hostPort[0])
-	}
-
-	// handling cases with url specified as host:port
-	if len(hostPort) > 1 {
-		if !govalidator.IsPort(hostPort[1]) {
-			return errors.Errorf("invalid port: %q", hostPort[1])
-		}
-	}
-	return nil
-}
-
 func validateScrapeIntervalAndTimeout(p *monitoringv1.Prometheus, scrapeInterval, scrapeTimeout monitoringv1.Duration) error {
 	if scrapeTimeout == "" {
 		return nil
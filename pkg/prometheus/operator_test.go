@@ -15,10 +15,13 @@
 package prometheus
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
 
+	"github.com/blang/semver/v4"
+	"github.com/go-kit/log"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"github.com/prometheus/prometheus/model/relabel"
@@ -26,6 +29,10 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
 
 	"github.com/kylelemons/godebug/pretty"
 )
@@ -210,12 +217,12 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			c := operator.Config{}
 
-			p1Hash, err := createSSetInputHash(tc.a, c, []string{}, nil, appsv1.StatefulSetSpec{})
+			p1Hash, err := createSSetInputHash(tc.a, c, []string{}, nil, nil, appsv1.StatefulSetSpec{})
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			p2Hash, err := createSSetInputHash(tc.b, c, []string{}, nil, appsv1.StatefulSetSpec{})
+			p2Hash, err := createSSetInputHash(tc.b, c, []string{}, nil, nil, appsv1.StatefulSetSpec{})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -231,7 +238,7 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 				t.Fatal("expected two Prometheus CRDs to produce the same hash but got different hash")
 			}
 
-			p2Hash, err = createSSetInputHash(tc.a, c, []string{}, nil, appsv1.StatefulSetSpec{Replicas: func(i int32) *int32 { return &i }(2)})
+			p2Hash, err = createSSetInputHash(tc.a, c, []string{}, nil, nil, appsv1.StatefulSetSpec{Replicas: func(i int32) *int32 { return &i }(2)})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -394,22 +401,34 @@ func TestValidateRemoteWriteConfig(t *testing.T) {
 	cases := []struct {
 		name      string
 		spec      monitoringv1.RemoteWriteSpec
+		version   string
 		expectErr bool
 	}{
 		{
 			name: "with_OAuth2",
 			spec: monitoringv1.RemoteWriteSpec{
-				OAuth2: &monitoringv1.OAuth2{},
+				URL: "http://example.com",
+				OAuth2: &monitoringv1.OAuth2{
+					ClientID: monitoringv1.SecretOrConfigMap{
+						Secret: &v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "oauth2"},
+							Key:                  "client-id",
+						},
+					},
+					TokenURL: "http://example.com/token",
+				},
 			},
 		}, {
 			name: "with_SigV4",
 			spec: monitoringv1.RemoteWriteSpec{
+				URL:   "http://example.com",
 				Sigv4: &monitoringv1.Sigv4{},
 			},
 		},
 		{
 			name: "with_OAuth2_and_SigV4",
 			spec: monitoringv1.RemoteWriteSpec{
+				URL:    "http://example.com",
 				OAuth2: &monitoringv1.OAuth2{},
 				Sigv4:  &monitoringv1.Sigv4{},
 			},
@@ -417,6 +436,7 @@ func TestValidateRemoteWriteConfig(t *testing.T) {
 		}, {
 			name: "with_OAuth2_and_BasicAuth",
 			spec: monitoringv1.RemoteWriteSpec{
+				URL:       "http://example.com",
 				OAuth2:    &monitoringv1.OAuth2{},
 				BasicAuth: &monitoringv1.BasicAuth{},
 			},
@@ -424,6 +444,7 @@ func TestValidateRemoteWriteConfig(t *testing.T) {
 		}, {
 			name: "with_BasicAuth_and_SigV4",
 			spec: monitoringv1.RemoteWriteSpec{
+				URL:       "http://example.com",
 				BasicAuth: &monitoringv1.BasicAuth{},
 				Sigv4:     &monitoringv1.Sigv4{},
 			},
@@ -431,17 +452,119 @@ func TestValidateRemoteWriteConfig(t *testing.T) {
 		}, {
 			name: "with_BasicAuth_and_SigV4_and_OAuth2",
 			spec: monitoringv1.RemoteWriteSpec{
+				URL:       "http://example.com",
 				BasicAuth: &monitoringv1.BasicAuth{},
 				Sigv4:     &monitoringv1.Sigv4{},
 				OAuth2:    &monitoringv1.OAuth2{},
 			},
 			expectErr: true,
+		}, {
+			name: "with_MessageVersion_V1.0",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:            "http://example.com",
+				MessageVersion: pointer.String("V1.0"),
+			},
+			version: "v2.40.0",
+		}, {
+			name: "with_MessageVersion_V2.0",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:            "http://example.com",
+				MessageVersion: pointer.String("V2.0"),
+			},
+			version: "v2.54.0",
+		}, {
+			name: "with_MessageVersion_V2.0_unsupported_version",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:            "http://example.com",
+				MessageVersion: pointer.String("V2.0"),
+			},
+			version:   "v2.53.0",
+			expectErr: true,
+		}, {
+			name: "with_invalid_MessageVersion",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:            "http://example.com",
+				MessageVersion: pointer.String("V3.0"),
+			},
+			expectErr: true,
+		}, {
+			name: "with_RetryOnRateLimit",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:         "http://example.com",
+				QueueConfig: &monitoringv1.QueueConfig{RetryOnRateLimit: true},
+			},
+			version: "v2.26.0",
+		}, {
+			name: "with_RetryOnRateLimit_unsupported_version",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:         "http://example.com",
+				QueueConfig: &monitoringv1.QueueConfig{RetryOnRateLimit: true},
+			},
+			version:   "v2.25.0",
+			expectErr: true,
+		}, {
+			name: "with_invalid_RemoteTimeout",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:           "http://example.com",
+				RemoteTimeout: "30 seconds",
+			},
+			expectErr: true,
+		}, {
+			name: "with_invalid_WriteRelabelConfigs_regex",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				WriteRelabelConfigs: []monitoringv1.RelabelConfig{
+					{Action: "keep", SourceLabels: []monitoringv1.LabelName{"__name__"}, Regex: "up("},
+				},
+			},
+			expectErr: true,
+		}, {
+			name: "with_negative_Capacity",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:         "http://example.com",
+				QueueConfig: &monitoringv1.QueueConfig{Capacity: -1},
+			},
+			expectErr: true,
+		}, {
+			name: "with_Capacity_lower_than_MaxSamplesPerSend",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:         "http://example.com",
+				QueueConfig: &monitoringv1.QueueConfig{Capacity: 500, MaxSamplesPerSend: 1000},
+			},
+			expectErr: true,
+		}, {
+			name:      "without_URL",
+			spec:      monitoringv1.RemoteWriteSpec{},
+			expectErr: true,
+		}, {
+			name: "with_URLs",
+			spec: monitoringv1.RemoteWriteSpec{
+				URLs: []string{"http://example.com/0", "http://example.com/1"},
+			},
+		}, {
+			name: "with_URL_and_URLs",
+			spec: monitoringv1.RemoteWriteSpec{
+				URL:  "http://example.com/0",
+				URLs: []string{"http://example.com/1"},
+			},
+			expectErr: true,
+		}, {
+			name: "with_duplicate_URLs",
+			spec: monitoringv1.RemoteWriteSpec{
+				URLs: []string{"http://example.com/0", "http://example.com/0"},
+			},
+			expectErr: true,
 		},
 	}
 	for _, c := range cases {
 		test := c
 		t.Run(test.name, func(t *testing.T) {
-			err := validateRemoteWriteSpec(test.spec)
+			version, err := semver.ParseTolerant(operator.StringValOrDefault(test.version, operator.DefaultPrometheusVersion))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateRemoteWriteSpec(test.spec, version)
 			if err != nil && !test.expectErr {
 				t.Fatalf("unexpected error occurred: %v", err)
 			}
@@ -452,6 +575,57 @@ func TestValidateRemoteWriteConfig(t *testing.T) {
 	}
 }
 
+func TestValidateRemoteWriteWarnings(t *testing.T) {
+	version := semver.MustParse("2.40.0")
+
+	for _, tc := range []struct {
+		name    string
+		relabel []monitoringv1.RelabelConfig
+		warning bool
+	}{
+		{
+			name: "keep with source labels",
+			relabel: []monitoringv1.RelabelConfig{
+				{Action: "keep", SourceLabels: []monitoringv1.LabelName{"__name__"}, Regex: "up"},
+			},
+		},
+		{
+			name: "drop with regex",
+			relabel: []monitoringv1.RelabelConfig{
+				{Action: "drop", SourceLabels: []monitoringv1.LabelName{"__name__"}, Regex: "go_.*"},
+			},
+		},
+		{
+			name: "keep without source labels",
+			relabel: []monitoringv1.RelabelConfig{
+				{Action: "keep", Regex: "up"},
+			},
+			warning: true,
+		},
+		{
+			name: "drop with default regex",
+			relabel: []monitoringv1.RelabelConfig{
+				{Action: "drop", SourceLabels: []monitoringv1.LabelName{"__name__"}},
+			},
+			warning: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRemoteWriteSpec(monitoringv1.RemoteWriteSpec{URL: "http://example.com", WriteRelabelConfigs: tc.relabel}, version)
+			if tc.warning {
+				if !monitoringv1.IsValidationWarning(err) {
+					t.Fatalf("expected a warning, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error occurred: %v", err)
+			}
+		})
+	}
+}
+
 func TestValidateRelabelConfig(t *testing.T) {
 	defaultRegexp, err := relabel.DefaultRelabelConfig.Regex.MarshalYAML()
 	if err != nil {
@@ -723,82 +897,6 @@ func TestValidateRelabelConfig(t *testing.T) {
 	}
 }
 
-func TestValidateProberUrl(t *testing.T) {
-	for _, tc := range []struct {
-		scenario    string
-		proberSpec  monitoringv1.ProberSpec
-		expectedErr bool
-	}{
-		{
-			scenario: "url starting with http",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "http://blackbox-exporter.example.com",
-			},
-			expectedErr: true,
-		},
-		{
-			scenario: "url starting with https",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "https://blackbox-exporter.example.com",
-			},
-			expectedErr: true,
-		},
-		{
-			scenario: "url starting with ftp",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "ftp://fileserver.com",
-			},
-			expectedErr: true,
-		},
-		{
-			scenario: "ip address as prober url",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "192.168.178.3",
-			},
-		},
-		{
-			scenario: "ip address:port as prober url",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "192.168.178.3:9090",
-			},
-		},
-		{
-			scenario: "dnsname as prober url",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "blackbox-exporter.example.com",
-			},
-		},
-		{
-			scenario: "dnsname:port as prober url",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "blackbox-exporter.example.com:8080",
-			},
-		},
-		{
-			scenario: "hostname as prober url",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "localhost",
-			},
-		},
-		{
-			scenario: "hostname starting with a digit as prober url",
-			proberSpec: monitoringv1.ProberSpec{
-				URL: "12-exporter.example.com",
-			},
-		},
-	} {
-		t.Run(fmt.Sprintf("case %s %s", tc.scenario, tc.proberSpec.URL), func(t *testing.T) {
-			err := validateProberURL(tc.proberSpec.URL)
-			if err != nil && !tc.expectedErr {
-				t.Fatalf("expected no error, got: %v", err)
-			}
-			if err == nil && tc.expectedErr {
-				t.Fatalf("expected an error, got nil")
-			}
-		})
-	}
-}
-
 func TestValidateScrapeIntervalAndTimeout(t *testing.T) {
 	for _, tc := range []struct {
 		scenario    string
@@ -885,3 +983,311 @@ func TestValidateScrapeIntervalAndTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceSelectionHasChanged(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		old      map[string]string
+		cur      map[string]string
+		nsel     monitoringv1.NamespaceSelector
+		expected bool
+	}{
+		{
+			name:     "added namespace matching the labels",
+			cur:      map[string]string{"tenant": "true"},
+			nsel:     monitoringv1.NamespaceSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			expected: true,
+		},
+		{
+			name: "added namespace not matching the labels",
+			cur:  map[string]string{"tenant": "false"},
+			nsel: monitoringv1.NamespaceSelector{MatchLabels: map[string]string{"tenant": "true"}},
+		},
+		{
+			name: "label removed from the namespace",
+			old:  map[string]string{"tenant": "true"},
+			cur:  map[string]string{},
+			nsel: monitoringv1.NamespaceSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tenant", Operator: metav1.LabelSelectorOpExists},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "unrelated label change",
+			old:  map[string]string{"tenant": "true"},
+			cur:  map[string]string{"tenant": "true", "team": "a"},
+			nsel: monitoringv1.NamespaceSelector{MatchLabels: map[string]string{"tenant": "true"}},
+		},
+		{
+			name: "match names only",
+			cur:  map[string]string{"tenant": "true"},
+			nsel: monitoringv1.NamespaceSelector{MatchNames: []string{"foo"}},
+		},
+		{
+			name: "any takes precedence over match labels",
+			cur:  map[string]string{"tenant": "true"},
+			nsel: monitoringv1.NamespaceSelector{Any: true, MatchLabels: map[string]string{"tenant": "true"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changed, err := namespaceSelectionHasChanged(tc.old, tc.cur, &tc.nsel)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			if changed != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, changed)
+			}
+		})
+	}
+}
+
+func TestResolveNamespaceSelector(t *testing.T) {
+	nsInf := cache.NewSharedIndexInformer(&cache.ListWatch{}, &v1.Namespace{}, 0, cache.Indexers{})
+	for _, ns := range []*v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b", Labels: map[string]string{"tenant": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{"tenant": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	} {
+		if err := nsInf.GetStore().Add(ns); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &Operator{logger: log.NewNopLogger(), nsMonInf: nsInf}
+
+	for _, tc := range []struct {
+		name     string
+		nsel     monitoringv1.NamespaceSelector
+		ignore   bool
+		expected monitoringv1.NamespaceSelector
+		matched  bool
+		err      bool
+	}{
+		{
+			name:     "match names only",
+			nsel:     monitoringv1.NamespaceSelector{MatchNames: []string{"foo"}},
+			expected: monitoringv1.NamespaceSelector{MatchNames: []string{"foo"}},
+			matched:  true,
+		},
+		{
+			name:     "match labels",
+			nsel:     monitoringv1.NamespaceSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			expected: monitoringv1.NamespaceSelector{MatchNames: []string{"tenant-a", "tenant-b"}},
+			matched:  true,
+		},
+		{
+			name: "match expressions take precedence over match names",
+			nsel: monitoringv1.NamespaceSelector{
+				MatchNames: []string{"foo"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tenant", Operator: metav1.LabelSelectorOpDoesNotExist},
+				},
+			},
+			expected: monitoringv1.NamespaceSelector{MatchNames: []string{"kube-system"}},
+			matched:  true,
+		},
+		{
+			name:     "any takes precedence over match labels",
+			nsel:     monitoringv1.NamespaceSelector{Any: true, MatchLabels: map[string]string{"tenant": "true"}},
+			expected: monitoringv1.NamespaceSelector{Any: true, MatchLabels: map[string]string{"tenant": "true"}},
+			matched:  true,
+		},
+		{
+			name:     "ignored namespace selectors",
+			nsel:     monitoringv1.NamespaceSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			ignore:   true,
+			expected: monitoringv1.NamespaceSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			matched:  true,
+		},
+		{
+			name: "no matching namespace",
+			nsel: monitoringv1.NamespaceSelector{MatchLabels: map[string]string{"tenant": "false"}},
+		},
+		{
+			name: "invalid label selector",
+			nsel: monitoringv1.NamespaceSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tenant", Operator: metav1.LabelSelectorOpIn},
+				},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						IgnoreNamespaceSelectors: tc.ignore,
+					},
+				},
+			}
+
+			nsel := tc.nsel
+			matched, err := c.resolveNamespaceSelector(p, &nsel)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if matched != tc.matched {
+				t.Fatalf("expected matched to be %v, got %v", tc.matched, matched)
+			}
+			if matched && !reflect.DeepEqual(nsel, tc.expected) {
+				t.Fatalf("expected namespace selector %v, got %v", tc.expected, nsel)
+			}
+		})
+	}
+}
+
+func TestValidateAdditionalScrapeConfigs(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  string
+		err  bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid scrape configs",
+			cfg: `- job_name: node
+  scrape_interval: 15s
+  static_configs:
+  - targets: ["node-exporter:9100"]
+- job_name: kube-state-metrics
+  kubernetes_sd_configs:
+  - role: endpoints
+`,
+		},
+		{
+			name: "not a list",
+			cfg:  "job_name: node\n",
+			err:  true,
+		},
+		{
+			name: "invalid relabel config",
+			cfg:  "- job_name: node\n  relabel_configs:\n  - action: keep\n    regex: \"(\"\n",
+			err:  true,
+		},
+		{
+			name: "missing job name",
+			cfg:  "- scrape_interval: 15s\n",
+			err:  true,
+		},
+		{
+			name: "invalid duration",
+			cfg:  "- job_name: node\n  scrape_interval: 15\n",
+			err:  true,
+		},
+		{
+			name: "empty scrape config",
+			cfg:  "- job_name: node\n-\n",
+			err:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAdditionalScrapeConfigs([]byte(tc.cfg))
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStatusShards(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		shards   *int32
+		ssets    []string
+		expected int32
+		err      bool
+	}{
+		{
+			name:     "nil shards",
+			ssets:    []string{"prometheus-test"},
+			expected: 1,
+		},
+		{
+			name:     "zero shards",
+			shards:   pointer.Int32(0),
+			ssets:    []string{"prometheus-test"},
+			expected: 1,
+		},
+		{
+			name:     "3 shards",
+			shards:   pointer.Int32(3),
+			ssets:    []string{"prometheus-test", "prometheus-test-shard-1", "prometheus-test-shard-2"},
+			expected: 3,
+		},
+		{
+			name:   "missing shard",
+			shards: pointer.Int32(2),
+			ssets:  []string{"prometheus-test"},
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Shards: tc.shards,
+					},
+				},
+			}
+
+			var objects []runtime.Object
+			for _, name := range tc.ssets {
+				objects = append(objects, &appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+					},
+					Spec: appsv1.StatefulSetSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"statefulset": name},
+						},
+					},
+				})
+			}
+
+			status, _, err := Status(context.Background(), fake.NewSimpleClientset(objects...), p)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if status.ShardsDesired != tc.expected {
+				t.Fatalf("expected %d desired shards, got %d", tc.expected, status.ShardsDesired)
+			}
+
+			if status.ShardsObserved != tc.expected {
+				t.Fatalf("expected %d observed shards, got %d", tc.expected, status.ShardsObserved)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"encoding/json"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	yamlv2 "gopkg.in/yaml.v2"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+// ScrapeGlobals holds the settings which influence the scrape configuration
+// generated for a monitor besides the monitor itself.
+type ScrapeGlobals struct {
+	// Prometheus is the Prometheus resource selecting the monitor. Its spec
+	// (version, shards, enforced limits, scrape classes, ...) is taken into
+	// account. A Prometheus resource with an empty spec is used if nil.
+	Prometheus *monitoringv1.Prometheus
+	// EndpointSliceSupported is true if the Kubernetes API supports
+	// EndpointSlice objects.
+	EndpointSliceSupported bool
+	// Store holds the credentials referenced by the monitor. An empty store
+	// is used if nil.
+	Store *assets.Store
+}
+
+// GenerateServiceMonitorScrapeConfig returns the scrape configuration that
+// the operator generates for the endpoint at the given index of the
+// ServiceMonitor. The configuration is generated by the same code path as
+// the Prometheus configuration, including the defaults and the relabelings
+// added by the operator.
+//
+// It can't be a method of the ServiceMonitor type because the API package
+// doesn't depend on the configuration generator.
+func GenerateServiceMonitorScrapeConfig(sm *monitoringv1.ServiceMonitor, endpointIndex int, globals ScrapeGlobals) (map[string]interface{}, error) {
+	if endpointIndex < 0 || endpointIndex >= len(sm.Spec.Endpoints) {
+		return nil, errors.Errorf("endpoint index %d out of range, the ServiceMonitor has %d endpoints", endpointIndex, len(sm.Spec.Endpoints))
+	}
+
+	p := globals.Prometheus
+	if p == nil {
+		p = &monitoringv1.Prometheus{}
+	}
+
+	store := globals.Store
+	if store == nil {
+		store = &assets.Store{}
+	}
+
+	cg, err := NewConfigGenerator(nil, p, globals.EndpointSliceSupported)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := int32(1)
+	if p.Spec.Shards != nil && *p.Spec.Shards > 1 {
+		shards = *p.Spec.Shards
+	}
+
+	cfg := cg.generateServiceMonitorConfig(sm, sm.Spec.Endpoints[endpointIndex], endpointIndex, p.Spec.APIServerConfig, store, shards)
+
+	b, err := yamlv2.Marshal(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal scrape config")
+	}
+
+	b, err = yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert scrape config")
+	}
+
+	var ret map[string]interface{}
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal scrape config")
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+func TestGenerateServiceMonitorScrapeConfig(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Shards:             pointer.Int32Ptr(2),
+				EnforcedLabelLimit: pointer.Uint64Ptr(50),
+			},
+		},
+	}
+
+	sm := &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sm",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "foo"},
+			},
+			Endpoints: []monitoringv1.Endpoint{
+				{Port: "web", Interval: "30s"},
+				{Port: "metrics", Path: "/custom"},
+			},
+		},
+	}
+
+	globals := ScrapeGlobals{Prometheus: p}
+
+	got, err := GenerateServiceMonitorScrapeConfig(sm, 1, globals)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got["job_name"] != "serviceMonitor/default/sm/1" {
+		t.Fatalf("unexpected job name %v", got["job_name"])
+	}
+
+	if got["metrics_path"] != "/custom" {
+		t.Fatalf("unexpected metrics path %v", got["metrics_path"])
+	}
+
+	// The helper must return the same scrape configuration as the one found
+	// in the complete Prometheus configuration.
+	cg := mustNewConfigGenerator(t, p)
+	b, err := cg.Generate(p, map[string]*monitoringv1.ServiceMonitor{"default/sm": sm}, nil, nil, nil, &assets.Store{}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = yaml.YAMLToJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		ScrapeConfigs []map[string]interface{} `json:"scrape_configs"`
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.ScrapeConfigs) != 2 {
+		t.Fatalf("expected 2 scrape configs, got %d", len(cfg.ScrapeConfigs))
+	}
+
+	if diff := cmp.Diff(cfg.ScrapeConfigs[1], got); diff != "" {
+		t.Fatalf("scrape configs don't match:\n%s", diff)
+	}
+
+	for _, i := range []int{-1, 2} {
+		if _, err := GenerateServiceMonitorScrapeConfig(sm, i, globals); err == nil {
+			t.Fatalf("expected error for endpoint index %d", i)
+		}
+	}
+}
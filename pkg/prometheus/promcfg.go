@@ -19,6 +19,7 @@ import (
 	"path"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/blang/semver/v4"
@@ -27,8 +28,10 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	namespacelabeler "github.com/prometheus-operator/prometheus-operator/pkg/namespace-labeler"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
@@ -73,6 +76,14 @@ func NewConfigGenerator(logger log.Logger, p *v1.Prometheus, endpointSliceSuppor
 
 	logger = log.WithSuffix(logger, "version", promVersion)
 
+	if p.Spec.ServiceDiscoveryRole != nil && *p.Spec.ServiceDiscoveryRole == v1.EndpointSliceRole {
+		if version.LT(semver.MustParse("2.21.0")) {
+			level.Warn(logger).Log("msg", "ignoring 'serviceDiscoveryRole' not supported by Prometheus, falling back to the endpoints role", "minimum_version", "2.21.0")
+		} else if !endpointSliceSupported {
+			level.Warn(logger).Log("msg", "the Kubernetes API doesn't support EndpointSlice objects, falling back to the endpoints role")
+		}
+	}
+
 	return &ConfigGenerator{
 		logger:                 logger,
 		version:                version,
@@ -192,6 +203,68 @@ func (cg *ConfigGenerator) AddLimitsToYAML(cfg yaml.MapSlice, k limitKey, limit
 	return cg.WithMinimumVersion(k.minVersion).AppendMapItem(cfg, k.prometheusField, getLimit(limit, enforcedLimit))
 }
 
+// addBodySizeLimitToYaml adds the body_size_limit field into scrape
+// configurations. When both the scrape object and the Prometheus object
+// define a limit, the smaller one applies.
+func (cg *ConfigGenerator) addBodySizeLimitToYaml(cfg yaml.MapSlice, limit *v1.ByteSize) yaml.MapSlice {
+	bodySizeLimit := cg.spec.EnforcedBodySizeLimit
+
+	switch {
+	case limit == nil || *limit == "":
+	case bodySizeLimit == "":
+		bodySizeLimit = *limit
+	default:
+		// Both values have been validated already.
+		l, _ := operator.ParseByteSize(*limit)
+		enforced, _ := operator.ParseByteSize(bodySizeLimit)
+		if l < enforced {
+			bodySizeLimit = *limit
+		}
+	}
+
+	if bodySizeLimit == "" {
+		return cfg
+	}
+
+	return cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", bodySizeLimit)
+}
+
+// addScrapeFallbackProtocolToYaml adds the fallback_scrape_protocol field
+// into scrape configurations. The value defined by the scrape object takes
+// precedence over the one defined by the scrape class which takes precedence
+// over the one defined by the Prometheus object.
+func (cg *ConfigGenerator) addScrapeFallbackProtocolToYaml(cfg yaml.MapSlice, protocol *v1.ScrapeProtocol, scrapeClassName *string) yaml.MapSlice {
+	if sc := cg.scrapeClass(scrapeClassName); protocol == nil && sc != nil {
+		protocol = sc.FallbackScrapeProtocol
+	}
+
+	if protocol == nil {
+		protocol = cg.spec.ScrapeFallbackProtocol
+	}
+
+	if protocol == nil {
+		return cfg
+	}
+
+	return cg.WithMinimumVersion("3.0.0").AppendMapItem(cfg, "fallback_scrape_protocol", *protocol)
+}
+
+// addConvertClassicHistogramsToNHCBToYaml adds the
+// convert_classic_histograms_to_nhcb field into scrape configurations. The
+// value defined by the scrape object takes precedence over the one defined by
+// the Prometheus object.
+func (cg *ConfigGenerator) addConvertClassicHistogramsToNHCBToYaml(cfg yaml.MapSlice, convert *bool) yaml.MapSlice {
+	if convert == nil {
+		convert = cg.spec.ConvertClassicHistogramsToNHCB
+	}
+
+	if convert == nil {
+		return cfg
+	}
+
+	return cg.WithMinimumVersion("3.0.0").AppendMapItem(cfg, "convert_classic_histograms_to_nhcb", *convert)
+}
+
 // AddHonorTimestamps adds the honor_timestamps field into scrape configurations.
 // honor_timestamps is false only when the user specified it or when the global
 // override applies.
@@ -221,7 +294,13 @@ func (cg *ConfigGenerator) AddHonorLabels(cfg yaml.MapSlice, honorLabels bool) y
 	return cg.AppendMapItem(cfg, "honor_labels", honorLabels)
 }
 
+// EndpointSliceSupported returns true if the ServiceMonitor targets are
+// discovered with the endpointslice role.
 func (cg *ConfigGenerator) EndpointSliceSupported() bool {
+	if cg.spec.ServiceDiscoveryRole != nil && *cg.spec.ServiceDiscoveryRole == v1.EndpointsRole {
+		return false
+	}
+
 	return cg.version.GTE(semver.MustParse("2.21.0")) && cg.endpointSliceSupported
 }
 
@@ -241,7 +320,7 @@ func stringMapToMapSlice(m map[string]string) yaml.MapSlice {
 	return res
 }
 
-func addSafeTLStoYaml(cfg yaml.MapSlice, namespace string, tls v1.SafeTLSConfig) yaml.MapSlice {
+func (cg *ConfigGenerator) addSafeTLStoYaml(cfg yaml.MapSlice, namespace string, tls v1.SafeTLSConfig) yaml.MapSlice {
 	pathForSelector := func(sel v1.SecretOrConfigMap) string {
 		return path.Join(tlsAssetsDir, assets.TLSAssetKeyFromSelector(namespace, sel).String())
 	}
@@ -260,16 +339,22 @@ func addSafeTLStoYaml(cfg yaml.MapSlice, namespace string, tls v1.SafeTLSConfig)
 	if tls.ServerName != "" {
 		tlsConfig = append(tlsConfig, yaml.MapItem{Key: "server_name", Value: tls.ServerName})
 	}
+	if tls.MinVersion != nil {
+		tlsConfig = cg.WithMinimumVersion("2.35.0").AppendMapItem(tlsConfig, "min_version", *tls.MinVersion)
+	}
+	if tls.MaxVersion != nil {
+		tlsConfig = cg.WithMinimumVersion("2.41.0").AppendMapItem(tlsConfig, "max_version", *tls.MaxVersion)
+	}
 	cfg = append(cfg, yaml.MapItem{Key: "tls_config", Value: tlsConfig})
 	return cfg
 }
 
-func addTLStoYaml(cfg yaml.MapSlice, namespace string, tls *v1.TLSConfig) yaml.MapSlice {
+func (cg *ConfigGenerator) addTLStoYaml(cfg yaml.MapSlice, namespace string, tls *v1.TLSConfig) yaml.MapSlice {
 	if tls == nil {
 		return cfg
 	}
 
-	tlsConfig := addSafeTLStoYaml(yaml.MapSlice{}, namespace, tls.SafeTLSConfig)[0].Value.(yaml.MapSlice)
+	tlsConfig := cg.addSafeTLStoYaml(yaml.MapSlice{}, namespace, tls.SafeTLSConfig)[0].Value.(yaml.MapSlice)
 	if tls.CAFile != "" {
 		tlsConfig = append(tlsConfig, yaml.MapItem{Key: "ca_file", Value: tls.CAFile})
 	}
@@ -284,6 +369,61 @@ func addTLStoYaml(cfg yaml.MapSlice, namespace string, tls *v1.TLSConfig) yaml.M
 	return cfg
 }
 
+// scrapeClass returns the scrape class with the given name or the default
+// scrape class if name is nil. It returns nil if no class matches.
+func (cg *ConfigGenerator) scrapeClass(name *string) *v1.ScrapeClass {
+	// The scrape objects referencing unknown scrape classes have already
+	// been rejected.
+	sc, _ := cg.spec.ResolveScrapeClass(name)
+	return sc
+}
+
+// attachMetadata returns the metadata to attach to the discovered targets:
+// the value defined by the scrape object if any, otherwise the value defined
+// by the scrape class.
+func (cg *ConfigGenerator) attachMetadata(scrapeClassName *string, attachMetadata *v1.AttachMetadata) *v1.AttachMetadata {
+	if attachMetadata != nil {
+		return attachMetadata
+	}
+
+	if sc := cg.scrapeClass(scrapeClassName); sc != nil {
+		return sc.AttachMetadata
+	}
+
+	return nil
+}
+
+// addScrapeClassBasicAuthToYaml adds the default basic authentication of the
+// scrape class to the scrape configuration unless the scrape object already
+// defines its own authentication.
+func (cg *ConfigGenerator) addScrapeClassBasicAuthToYaml(
+	cfg yaml.MapSlice,
+	scrapeClassName *string,
+	store *assets.Store,
+	hasAuth bool,
+) yaml.MapSlice {
+	if hasAuth {
+		return cfg
+	}
+
+	sc := cg.scrapeClass(scrapeClassName)
+	if sc == nil || sc.DefaultBasicAuth == nil {
+		return cfg
+	}
+
+	s, ok := store.BasicAuthAssets[fmt.Sprintf("scrapeClass/%s", sc.Name)]
+	if !ok {
+		return cfg
+	}
+
+	return append(cfg, yaml.MapItem{
+		Key: "basic_auth", Value: yaml.MapSlice{
+			{Key: "username", Value: s.Username},
+			{Key: "password", Value: s.Password},
+		},
+	})
+}
+
 func (cg *ConfigGenerator) addSafeAuthorizationToYaml(
 	cfg yaml.MapSlice,
 	assetStoreKey string,
@@ -294,12 +434,9 @@ func (cg *ConfigGenerator) addSafeAuthorizationToYaml(
 		return cfg
 	}
 
-	authCfg := yaml.MapSlice{}
-	if auth.Type == "" {
-		auth.Type = "Bearer"
+	authCfg := yaml.MapSlice{
+		{Key: "type", Value: auth.EffectiveType()},
 	}
-
-	authCfg = append(authCfg, yaml.MapItem{Key: "type", Value: strings.TrimSpace(auth.Type)})
 	if auth.Credentials != nil {
 		if s, ok := store.TokenAssets[assetStoreKey]; ok {
 			authCfg = append(authCfg, yaml.MapItem{Key: "credentials", Value: s})
@@ -362,7 +499,9 @@ func buildExternalLabels(p *v1.Prometheus) yaml.MapSlice {
 }
 
 // validateConfigInputs runs extra validation on the Prometheus fields which can't be done at the CRD schema validation level.
-func validateConfigInputs(p *v1.Prometheus) error {
+// Validation warnings are logged and don't prevent the configuration from
+// being generated.
+func (cg *ConfigGenerator) validateConfigInputs(p *v1.Prometheus) error {
 	// TODO(slashpai): Remove this validation after v0.57 since this is handled at CRD level
 	if p.Spec.EnforcedBodySizeLimit != "" {
 		if err := operator.ValidateSizeField(string(p.Spec.EnforcedBodySizeLimit)); err != nil {
@@ -396,9 +535,10 @@ func validateConfigInputs(p *v1.Prometheus) error {
 		if err := operator.ValidateDurationField(string(p.Spec.ScrapeTimeout)); err != nil {
 			return errors.Wrap(err, "invalid scrapeTimeout value specified")
 		}
-		if err := operator.CompareScrapeTimeoutToScrapeInterval(p.Spec.ScrapeTimeout, p.Spec.ScrapeInterval); err != nil {
-			return err
-		}
+	}
+
+	if err := p.Spec.CommonPrometheusFields.Validate(); err != nil {
+		return err
 	}
 
 	// TODO(slashpai): Remove this validation after v0.57 since this is handled at CRD level
@@ -447,6 +587,45 @@ func validateConfigInputs(p *v1.Prometheus) error {
 		}
 	}
 
+	if err := p.Spec.StatefulSetMetadata.Validate(); err != nil {
+		return errors.Wrap(err, "invalid statefulSetMetadata value specified")
+	}
+
+	if err := p.Spec.ServiceMetadata.Validate(); err != nil {
+		return errors.Wrap(err, "invalid serviceMetadata value specified")
+	}
+
+	if p.Spec.EnableOTLPReceiver != nil && *p.Spec.EnableOTLPReceiver && cg.version.LT(semver.MustParse("2.47.0")) {
+		return errors.Errorf("enableOTLPReceiver requires Prometheus >= 2.47.0, current version is %s", cg.version)
+	}
+
+	if err := p.Spec.ValidateScrapeClasses(); err != nil {
+		return errors.Wrap(err, "invalid scrapeClasses value specified")
+	}
+
+	if err := p.Spec.TracingConfig.Validate(); err != nil {
+		return errors.Wrap(err, "invalid tracingConfig value specified")
+	}
+
+	if err := p.Spec.Runtime.Validate(); err != nil {
+		return errors.Wrap(err, "invalid runtime value specified")
+	}
+
+	if p.Spec.OTLP != nil {
+		// Prometheus refuses empty and duplicated attribute names.
+		seen := make(map[string]struct{}, len(p.Spec.OTLP.PromoteResourceAttributes))
+		for i, attr := range p.Spec.OTLP.PromoteResourceAttributes {
+			if strings.TrimSpace(attr) == "" {
+				return errors.Errorf("invalid otlp.promoteResourceAttributes[%d] value %q: empty attribute name", i, attr)
+			}
+
+			if _, ok := seen[attr]; ok {
+				return errors.Errorf("invalid otlp.promoteResourceAttributes[%d] value %q: duplicated attribute name", i, attr)
+			}
+			seen[attr] = struct{}{}
+		}
+	}
+
 	// TODO(slashpai): Remove this validation after v0.60 since this is handled at CRD level
 	if p.Spec.Alerting != nil {
 		for i, ap := range p.Spec.Alerting.Alertmanagers {
@@ -455,6 +634,13 @@ func validateConfigInputs(p *v1.Prometheus) error {
 					return errors.Wrapf(err, "invalid alertmanagers[%d].timeout value specified", i)
 				}
 			}
+
+			if err := ap.Validate(); err != nil {
+				if !v1.IsValidationWarning(err) {
+					return errors.Wrapf(err, "invalid alertmanagers[%d] value specified", i)
+				}
+				level.Warn(cg.logger).Log("msg", fmt.Sprintf("alertmanagers[%d]: %s", i, err))
+			}
 		}
 	}
 
@@ -467,6 +653,7 @@ func (cg *ConfigGenerator) Generate(
 	sMons map[string]*v1.ServiceMonitor,
 	pMons map[string]*v1.PodMonitor,
 	probes map[string]*v1.Probe,
+	sCons map[string]*v1alpha1.ScrapeConfig,
 	store *assets.Store,
 	additionalScrapeConfigs []byte,
 	additionalAlertRelabelConfigs []byte,
@@ -474,7 +661,7 @@ func (cg *ConfigGenerator) Generate(
 	ruleConfigMapNames []string,
 ) ([]byte, error) {
 	// Validate Prometheus Config Inputs at Prometheus CRD level
-	if err := validateConfigInputs(p); err != nil {
+	if err := cg.validateConfigInputs(p); err != nil {
 		return nil, err
 	}
 
@@ -510,6 +697,14 @@ func (cg *ConfigGenerator) Generate(
 		globalItems = cg.WithMinimumVersion("2.16.0").AppendMapItem(globalItems, "query_log_file", queryLogFilePath(p))
 	}
 
+	if len(p.Spec.ScrapeProtocols) > 0 {
+		globalItems = cg.WithMinimumVersion("2.49.0").AppendMapItem(globalItems, "scrape_protocols", p.Spec.ScrapeProtocols)
+	}
+
+	if p.Spec.RuleQueryOffset != nil {
+		globalItems = cg.WithMinimumVersion("2.53.0").AppendMapItem(globalItems, "rule_query_offset", p.Spec.RuleQueryOffset)
+	}
+
 	cfg = append(cfg, yaml.MapItem{Key: "global", Value: globalItems})
 
 	if p.Spec.RuleSelector != nil {
@@ -553,6 +748,16 @@ func (cg *ConfigGenerator) Generate(
 	// Sorting ensures, that we always generate the config in the same order.
 	sort.Strings(probeIdentifiers)
 
+	sConIdentifiers := make([]string, len(sCons))
+	i = 0
+	for k := range sCons {
+		sConIdentifiers[i] = k
+		i++
+	}
+
+	// Sorting ensures, that we always generate the config in the same order.
+	sort.Strings(sConIdentifiers)
+
 	apiserverConfig := p.Spec.APIServerConfig
 	shards := int32(1)
 	if p.Spec.Shards != nil && *p.Spec.Shards > 1 {
@@ -597,6 +802,17 @@ func (cg *ConfigGenerator) Generate(
 		)
 	}
 
+	for _, identifier := range sConIdentifiers {
+		scrapeConfigs = append(scrapeConfigs,
+			cg.WithKeyVals("scrapeconfig", identifier).generateScrapeConfig(
+				sCons[identifier],
+				apiserverConfig,
+				store,
+				shards,
+			),
+		)
+	}
+
 	var addlScrapeConfigs []yaml.MapSlice
 	addlScrapeConfigs, err := cg.generateAdditionalScrapeConfigs(additionalScrapeConfigs, shards)
 	if err != nil {
@@ -626,9 +842,82 @@ func (cg *ConfigGenerator) Generate(
 		cfg = append(cfg, cg.generateRemoteReadConfig(p, store))
 	}
 
+	cfg = cg.appendOTLPConfig(cfg, p.Spec.OTLP)
+
+	cfg = cg.appendTracingConfig(cfg, p)
+
+	cfg = cg.appendRuntimeConfig(cfg, p.Spec.Runtime)
+
 	return yaml.Marshal(cfg)
 }
 
+func (cg *ConfigGenerator) appendRuntimeConfig(cfg yaml.MapSlice, runtime *v1.RuntimeConfig) yaml.MapSlice {
+	if runtime == nil || runtime.GoGC == nil {
+		return cfg
+	}
+
+	return cg.WithMinimumVersion("2.53.0").AppendMapItem(cfg, "runtime", yaml.MapSlice{
+		{Key: "gogc", Value: *runtime.GoGC},
+	})
+}
+
+func (cg *ConfigGenerator) appendTracingConfig(cfg yaml.MapSlice, p *v1.Prometheus) yaml.MapSlice {
+	tracing := p.Spec.TracingConfig
+	if tracing == nil {
+		return cfg
+	}
+
+	tracingCfg := yaml.MapSlice{
+		{Key: "endpoint", Value: tracing.Endpoint},
+	}
+
+	if tracing.ClientType != nil {
+		tracingCfg = append(tracingCfg, yaml.MapItem{Key: "client_type", Value: *tracing.ClientType})
+	}
+
+	if tracing.SamplingFraction != nil {
+		// The value has already been validated by validateConfigInputs().
+		samplingFraction, _ := strconv.ParseFloat(*tracing.SamplingFraction, 64)
+		tracingCfg = append(tracingCfg, yaml.MapItem{Key: "sampling_fraction", Value: samplingFraction})
+	}
+
+	if tracing.Insecure != nil {
+		tracingCfg = append(tracingCfg, yaml.MapItem{Key: "insecure", Value: *tracing.Insecure})
+	}
+
+	if len(tracing.Headers) > 0 {
+		tracingCfg = append(tracingCfg, yaml.MapItem{Key: "headers", Value: stringMapToMapSlice(tracing.Headers)})
+	}
+
+	if tracing.Compression != nil {
+		tracingCfg = append(tracingCfg, yaml.MapItem{Key: "compression", Value: *tracing.Compression})
+	}
+
+	if tracing.Timeout != nil {
+		tracingCfg = append(tracingCfg, yaml.MapItem{Key: "timeout", Value: *tracing.Timeout})
+	}
+
+	tracingCfg = cg.addTLStoYaml(tracingCfg, p.Namespace, tracing.TLSConfig)
+
+	return cg.WithMinimumVersion("2.34.0").AppendMapItem(cfg, "tracing", tracingCfg)
+}
+
+func (cg *ConfigGenerator) appendOTLPConfig(cfg yaml.MapSlice, otlp *v1.OTLPConfig) yaml.MapSlice {
+	if otlp == nil {
+		return cfg
+	}
+
+	var otlpCfg yaml.MapSlice
+	if len(otlp.PromoteResourceAttributes) > 0 {
+		otlpCfg = append(otlpCfg, yaml.MapItem{
+			Key:   "promote_resource_attributes",
+			Value: otlp.PromoteResourceAttributes,
+		})
+	}
+
+	return cg.WithMinimumVersion("2.55.0").AppendMapItem(cfg, "otlp", otlpCfg)
+}
+
 func (cg *ConfigGenerator) appendStorageSettingsConfig(cfg yaml.MapSlice, p *v1.Prometheus) (yaml.MapSlice, error) {
 	var (
 		storage   yaml.MapSlice
@@ -694,6 +983,12 @@ func (cg *ConfigGenerator) appendAlertingConfig(
 		})
 	}
 
+	if p.Spec.Alerting != nil {
+		for i := range p.Spec.Alerting.AlertRelabelConfigs {
+			alertRelabelConfigs = append(alertRelabelConfigs, generateRelabelConfig([]*v1.RelabelConfig{&p.Spec.Alerting.AlertRelabelConfigs[i]})...)
+		}
+	}
+
 	var additionalAlertRelabelConfigsYaml []yaml.MapSlice
 	if err := yaml.Unmarshal([]byte(additionalAlertRelabelConfigs), &additionalAlertRelabelConfigsYaml); err != nil {
 		return nil, errors.Wrap(err, "unmarshalling additional alerting relabel configs failed")
@@ -741,7 +1036,7 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	cfg = cg.AddHonorLabels(cfg, ep.HonorLabels)
 	cfg = cg.AddHonorTimestamps(cfg, ep.HonorTimestamps)
 
-	cfg = append(cfg, cg.generateK8SSDConfig(m.Spec.NamespaceSelector, m.Namespace, apiserverConfig, store, kubernetesSDRolePod, m.Spec.AttachMetadata))
+	cfg = append(cfg, cg.generateK8SSDConfig(m.Spec.NamespaceSelector, m.Namespace, apiserverConfig, store, kubernetesSDRolePod, cg.attachMetadata(m.Spec.ScrapeClassName, m.Spec.AttachMetadata)))
 
 	if ep.Interval != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: ep.Interval})
@@ -752,9 +1047,7 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	if ep.Path != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "metrics_path", Value: ep.Path})
 	}
-	if ep.ProxyURL != nil {
-		cfg = append(cfg, yaml.MapItem{Key: "proxy_url", Value: ep.ProxyURL})
-	}
+	cfg = cg.addProxyConfigToYaml(cfg, fmt.Sprintf("podMonitor/%s/%s/%d", m.Namespace, m.Name, i), store, ep.ProxyConfig)
 	if ep.Params != nil {
 		cfg = append(cfg, yaml.MapItem{Key: "params", Value: ep.Params})
 	}
@@ -767,8 +1060,13 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	if ep.EnableHttp2 != nil {
 		cfg = cg.WithMinimumVersion("2.35.0").AppendMapItem(cfg, "enable_http2", *ep.EnableHttp2)
 	}
+	if len(ep.ScrapeProtocols) > 0 {
+		cfg = cg.WithMinimumVersion("2.49.0").AppendMapItem(cfg, "scrape_protocols", ep.ScrapeProtocols)
+	}
+	cfg = cg.addScrapeFallbackProtocolToYaml(cfg, ep.ScrapeFallbackProtocol, m.Spec.ScrapeClassName)
+	cfg = cg.addConvertClassicHistogramsToNHCBToYaml(cfg, ep.ConvertClassicHistogramsToNHCB)
 	if ep.TLSConfig != nil {
-		cfg = addSafeTLStoYaml(cfg, m.Namespace, ep.TLSConfig.SafeTLSConfig)
+		cfg = cg.addSafeTLStoYaml(cfg, m.Namespace, ep.TLSConfig.SafeTLSConfig)
 	}
 
 	if ep.BearerTokenSecret.Name != "" {
@@ -789,10 +1087,13 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	}
 
 	assetKey := fmt.Sprintf("podMonitor/%s/%s/%d", m.Namespace, m.Name, i)
-	cfg = cg.addOAuth2ToYaml(cfg, ep.OAuth2, store.OAuth2Assets, assetKey)
+	cfg = cg.addOAuth2ToYaml(cfg, ep.OAuth2, store, m.Namespace, assetKey)
 
 	cfg = cg.addSafeAuthorizationToYaml(cfg, fmt.Sprintf("podMonitor/auth/%s/%s/%d", m.Namespace, m.Name, i), store, ep.Authorization)
 
+	hasAuth := ep.BearerTokenSecret.Name != "" || ep.BasicAuth != nil || ep.OAuth2 != nil || ep.Authorization != nil
+	cfg = cg.addScrapeClassBasicAuthToYaml(cfg, m.Spec.ScrapeClassName, store, hasAuth)
+
 	relabelings := initRelabelings()
 
 	if ep.FilterRunning == nil || *ep.FilterRunning {
@@ -859,17 +1160,16 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	} else if ep.TargetPort != nil { //nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
 		level.Warn(cg.logger).Log("msg", "'targetPort' is deprecated, use 'port' instead.")
 		//nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
-		if ep.TargetPort.StrVal != "" {
-			relabelings = append(relabelings, yaml.MapSlice{
-				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_container_port_name"}},
-				{Key: "regex", Value: ep.TargetPort.String()},
-			})
-		} else if ep.TargetPort.IntVal != 0 { //nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
+		if port := v1.PortAsString(*ep.TargetPort); port != "" {
+			sourceLabel := "__meta_kubernetes_pod_container_port_number"
+			if ep.TargetPort.Type == intstr.String { //nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
+				sourceLabel = "__meta_kubernetes_pod_container_port_name"
+			}
+
 			relabelings = append(relabelings, yaml.MapSlice{
 				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_container_port_number"}},
-				{Key: "regex", Value: ep.TargetPort.String()},
+				{Key: "source_labels", Value: []string{sourceLabel}},
+				{Key: "regex", Value: port},
 			})
 		}
 	}
@@ -924,17 +1224,17 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 			{Key: "target_label", Value: "endpoint"},
 			{Key: "replacement", Value: ep.Port},
 		})
-	} else if ep.TargetPort != nil && ep.TargetPort.String() != "" { //nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
+	} else if ep.TargetPort != nil && v1.PortAsString(*ep.TargetPort) != "" { //nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
 		relabelings = append(relabelings, yaml.MapSlice{
 			{Key: "target_label", Value: "endpoint"},
-			{Key: "replacement", Value: ep.TargetPort.String()}, //nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
+			{Key: "replacement", Value: v1.PortAsString(*ep.TargetPort)}, //nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
 		})
 	}
 
 	labeler := namespacelabeler.New(cg.spec.EnforcedNamespaceLabel, cg.spec.ExcludedFromEnforcement, false)
 	relabelings = append(relabelings, generateRelabelConfig(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, ep.RelabelConfigs))...)
 
-	relabelings = generateAddressShardingRelabelingRules(relabelings, shards)
+	relabelings = cg.generateAddressShardingRelabelingRules(relabelings, shards)
 	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
 
 	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, m.Spec.SampleLimit, cg.spec.EnforcedSampleLimit)
@@ -943,9 +1243,7 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, cg.spec.EnforcedLabelNameLengthLimit)
 	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, cg.spec.EnforcedLabelValueLengthLimit)
 
-	if cg.spec.EnforcedBodySizeLimit != "" {
-		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", cg.spec.EnforcedBodySizeLimit)
-	}
+	cfg = cg.addBodySizeLimitToYaml(cfg, ep.BodySizeLimit)
 
 	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: generateRelabelConfig(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, ep.MetricRelabelConfigs))})
 
@@ -995,6 +1293,8 @@ func (cg *ConfigGenerator) generateProbeConfig(
 			{Key: "module", Value: []string{m.Spec.Module}},
 		}})
 	}
+	cfg = cg.addScrapeFallbackProtocolToYaml(cfg, nil, m.Spec.ScrapeClassName)
+	cfg = cg.addConvertClassicHistogramsToNHCBToYaml(cfg, nil)
 
 	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, m.Spec.SampleLimit, cg.spec.EnforcedSampleLimit)
 	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, m.Spec.TargetLimit, cg.spec.EnforcedTargetLimit)
@@ -1002,9 +1302,7 @@ func (cg *ConfigGenerator) generateProbeConfig(
 	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, cg.spec.EnforcedLabelNameLengthLimit)
 	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, cg.spec.EnforcedLabelValueLengthLimit)
 
-	if cg.spec.EnforcedBodySizeLimit != "" {
-		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", cg.spec.EnforcedBodySizeLimit)
-	}
+	cfg = cg.addBodySizeLimitToYaml(cfg, m.Spec.BodySizeLimit)
 
 	relabelings := initRelabelings()
 
@@ -1114,44 +1412,13 @@ func (cg *ConfigGenerator) generateProbeConfig(
 		cfg = append(cfg, cg.generateK8SSDConfig(m.Spec.Targets.Ingress.NamespaceSelector, m.Namespace, apiserverConfig, store, kubernetesSDRoleIngress, nil))
 
 		// Relabelings for ingress SD.
-		relabelings = append(relabelings, []yaml.MapSlice{
-			{
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_ingress_scheme", "__address__", "__meta_kubernetes_ingress_path"}},
-				{Key: "separator", Value: ";"},
-				{Key: "regex", Value: "(.+);(.+);(.+)"},
-				{Key: "target_label", Value: "__param_target"},
-				{Key: "replacement", Value: "${1}://${2}${3}"},
-				{Key: "action", Value: "replace"},
-			},
-			{
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_namespace"}},
-				{Key: "target_label", Value: "namespace"},
-			},
-			{
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_ingress_name"}},
-				{Key: "target_label", Value: "ingress"},
-			},
-		}...)
+		relabelings = append(relabelings, generateIngressDefaultRelabelConfig(m.Spec.Targets.Ingress.DefaultRelabelings())...)
 
 		// Relabelings for prober.
-		relabelings = append(relabelings, []yaml.MapSlice{
-			{
-				{Key: "source_labels", Value: []string{"__address__"}},
-				{Key: "separator", Value: ";"},
-				{Key: "regex", Value: "(.*)"},
-				{Key: "target_label", Value: "__tmp_ingress_address"},
-				{Key: "replacement", Value: "$1"},
-				{Key: "action", Value: "replace"},
-			},
-			{
-				{Key: "source_labels", Value: []string{"__param_target"}},
-				{Key: "target_label", Value: "instance"},
-			},
-			{
-				{Key: "target_label", Value: "__address__"},
-				{Key: "replacement", Value: m.Spec.ProberSpec.URL},
-			},
-		}...)
+		relabelings = append(relabelings, yaml.MapSlice{
+			{Key: "target_label", Value: "__address__"},
+			{Key: "replacement", Value: m.Spec.ProberSpec.URL},
+		})
 
 		// Add configured relabelings.
 		relabelings = append(relabelings, generateRelabelConfig(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, m.Spec.Targets.Ingress.RelabelConfigs))...)
@@ -1162,7 +1429,7 @@ func (cg *ConfigGenerator) generateProbeConfig(
 	}
 
 	if m.Spec.TLSConfig != nil {
-		cfg = addSafeTLStoYaml(cfg, m.Namespace, m.Spec.TLSConfig.SafeTLSConfig)
+		cfg = cg.addSafeTLStoYaml(cfg, m.Namespace, m.Spec.TLSConfig.SafeTLSConfig)
 	}
 
 	if m.Spec.BearerTokenSecret.Name != "" {
@@ -1184,15 +1451,274 @@ func (cg *ConfigGenerator) generateProbeConfig(
 	}
 
 	assetKey := fmt.Sprintf("probe/%s/%s", m.Namespace, m.Name)
-	cfg = cg.addOAuth2ToYaml(cfg, m.Spec.OAuth2, store.OAuth2Assets, assetKey)
+	cfg = cg.addOAuth2ToYaml(cfg, m.Spec.OAuth2, store, m.Namespace, assetKey)
 
 	cfg = cg.addSafeAuthorizationToYaml(cfg, fmt.Sprintf("probe/auth/%s/%s", m.Namespace, m.Name), store, m.Spec.Authorization)
 
+	hasAuth := m.Spec.BearerTokenSecret.Name != "" || m.Spec.BasicAuth != nil || m.Spec.OAuth2 != nil || m.Spec.Authorization != nil
+	cfg = cg.addScrapeClassBasicAuthToYaml(cfg, m.Spec.ScrapeClassName, store, hasAuth)
+
 	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: generateRelabelConfig(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, m.Spec.MetricRelabelConfigs))})
 
 	return cfg
 }
 
+func (cg *ConfigGenerator) generateScrapeConfig(
+	sc *v1alpha1.ScrapeConfig,
+	apiserverConfig *v1.APIServerConfig,
+	store *assets.Store,
+	shards int32,
+) yaml.MapSlice {
+	jobName := fmt.Sprintf("scrapeConfig/%s/%s", sc.Namespace, sc.Name)
+	cfg := yaml.MapSlice{
+		{
+			Key:   "job_name",
+			Value: jobName,
+		},
+	}
+
+	if sc.Spec.MetricsPath != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "metrics_path", Value: *sc.Spec.MetricsPath})
+	}
+	if sc.Spec.ScrapeInterval != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: *sc.Spec.ScrapeInterval})
+	}
+	if sc.Spec.ScrapeTimeout != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: *sc.Spec.ScrapeTimeout})
+	}
+	if sc.Spec.Scheme != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "scheme", Value: strings.ToLower(*sc.Spec.Scheme)})
+	}
+
+	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, 0, cg.spec.EnforcedSampleLimit)
+	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, 0, cg.spec.EnforcedTargetLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, 0, cg.spec.EnforcedLabelLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, 0, cg.spec.EnforcedLabelNameLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, 0, cg.spec.EnforcedLabelValueLengthLimit)
+
+	if sc.Spec.BasicAuth != nil {
+		if s, ok := store.BasicAuthAssets[fmt.Sprintf("scrapeconfig/%s/%s", sc.Namespace, sc.Name)]; ok {
+			cfg = append(cfg, yaml.MapItem{
+				Key: "basic_auth", Value: yaml.MapSlice{
+					{Key: "username", Value: s.Username},
+					{Key: "password", Value: s.Password},
+				},
+			})
+		}
+	}
+
+	cfg = cg.addSafeAuthorizationToYaml(cfg, fmt.Sprintf("scrapeconfig/auth/%s/%s", sc.Namespace, sc.Name), store, sc.Spec.Authorization)
+
+	if sc.Spec.TLSConfig != nil {
+		cfg = cg.addSafeTLStoYaml(cfg, sc.Namespace, *sc.Spec.TLSConfig)
+	}
+
+	// Generate static_configs section.
+	if len(sc.Spec.StaticConfigs) > 0 {
+		configs := make([]yaml.MapSlice, len(sc.Spec.StaticConfigs))
+		for i, config := range sc.Spec.StaticConfigs {
+			configs[i] = yaml.MapSlice{
+				{Key: "targets", Value: config.Targets},
+			}
+
+			if config.Labels != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "labels", Value: stringMapToMapSlice(config.Labels)})
+			}
+		}
+		cfg = append(cfg, yaml.MapItem{Key: "static_configs", Value: configs})
+	}
+
+	// Generate file_sd_configs section.
+	if len(sc.Spec.FileSDConfigs) > 0 {
+		configs := make([]yaml.MapSlice, len(sc.Spec.FileSDConfigs))
+		for i, config := range sc.Spec.FileSDConfigs {
+			configs[i] = yaml.MapSlice{
+				{Key: "files", Value: config.Files},
+			}
+
+			if config.RefreshInterval != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "refresh_interval", Value: *config.RefreshInterval})
+			}
+		}
+		cfg = append(cfg, yaml.MapItem{Key: "file_sd_configs", Value: configs})
+	}
+
+	// Generate http_sd_configs section.
+	if len(sc.Spec.HTTPSDConfigs) > 0 {
+		configs := make([]yaml.MapSlice, len(sc.Spec.HTTPSDConfigs))
+		for i, config := range sc.Spec.HTTPSDConfigs {
+			configs[i] = yaml.MapSlice{
+				{Key: "url", Value: config.URL},
+			}
+
+			if config.RefreshInterval != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "refresh_interval", Value: *config.RefreshInterval})
+			}
+
+			if config.BasicAuth != nil {
+				if s, ok := store.BasicAuthAssets[fmt.Sprintf("scrapeconfig/%s/%s/httpsdconfig/%d", sc.Namespace, sc.Name, i)]; ok {
+					configs[i] = append(configs[i], yaml.MapItem{
+						Key: "basic_auth", Value: yaml.MapSlice{
+							{Key: "username", Value: s.Username},
+							{Key: "password", Value: s.Password},
+						},
+					})
+				}
+			}
+
+			configs[i] = cg.addSafeAuthorizationToYaml(configs[i], fmt.Sprintf("scrapeconfig/auth/%s/%s/httpsdconfig/%d", sc.Namespace, sc.Name, i), store, config.Authorization)
+
+			if config.TLSConfig != nil {
+				configs[i] = cg.addSafeTLStoYaml(configs[i], sc.Namespace, *config.TLSConfig)
+			}
+		}
+		cfg = cg.WithMinimumVersion("2.21.0").AppendMapItem(cfg, "http_sd_configs", configs)
+	}
+
+	// Generate kubernetes_sd_configs section.
+	if len(sc.Spec.KubernetesSDConfigs) > 0 {
+		configs := make([]yaml.MapSlice, 0, len(sc.Spec.KubernetesSDConfigs))
+		for _, config := range sc.Spec.KubernetesSDConfigs {
+			if config.Role == v1alpha1.KubernetesRoleEndpointSlice && cg.version.LT(semver.MustParse("2.21.0")) {
+				level.Warn(cg.logger).Log("msg", "ignoring kubernetes_sd_configs entry with the endpointslice role not supported by Prometheus", "minimum_version", "2.21.0", "scrapeconfig", sc.Namespace+"/"+sc.Name)
+				continue
+			}
+
+			// Reuse generateK8SSDConfig and unpack the generated
+			// kubernetes_sd_configs entry.
+			sdConfig := cg.generateK8SSDConfig(
+				config.NamespaceSelector,
+				sc.Namespace,
+				apiserverConfig,
+				store,
+				strings.ToLower(string(config.Role)),
+				nil,
+			).Value.([]yaml.MapSlice)[0]
+
+			if len(config.Selectors) > 0 {
+				selectors := make([]yaml.MapSlice, len(config.Selectors))
+				for j, s := range config.Selectors {
+					selectors[j] = yaml.MapSlice{
+						{Key: "role", Value: strings.ToLower(string(s.Role))},
+					}
+
+					if s.Label != "" {
+						selectors[j] = append(selectors[j], yaml.MapItem{Key: "label", Value: s.Label})
+					}
+
+					if s.Field != "" {
+						selectors[j] = append(selectors[j], yaml.MapItem{Key: "field", Value: s.Field})
+					}
+				}
+				sdConfig = append(sdConfig, yaml.MapItem{Key: "selectors", Value: selectors})
+			}
+
+			configs = append(configs, sdConfig)
+		}
+
+		if len(configs) > 0 {
+			cfg = append(cfg, yaml.MapItem{Key: "kubernetes_sd_configs", Value: configs})
+		}
+	}
+
+	// Generate consul_sd_configs section.
+	if len(sc.Spec.ConsulSDConfigs) > 0 {
+		configs := make([]yaml.MapSlice, len(sc.Spec.ConsulSDConfigs))
+		for i, config := range sc.Spec.ConsulSDConfigs {
+			assetKey := fmt.Sprintf("scrapeconfig/%s/%s/consulsdconfig/%d", sc.Namespace, sc.Name, i)
+
+			configs[i] = yaml.MapSlice{
+				{Key: "server", Value: config.Server},
+			}
+
+			if config.TokenRef != nil {
+				if s, ok := store.TokenAssets[fmt.Sprintf("scrapeconfig/token/%s/%s/consulsdconfig/%d", sc.Namespace, sc.Name, i)]; ok {
+					configs[i] = append(configs[i], yaml.MapItem{Key: "token", Value: s})
+				}
+			}
+
+			if config.Datacenter != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "datacenter", Value: *config.Datacenter})
+			}
+
+			if config.Namespace != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "namespace", Value: *config.Namespace})
+			}
+
+			if config.Partition != nil {
+				configs[i] = cg.WithMinimumVersion("2.41.0").AppendMapItem(configs[i], "partition", *config.Partition)
+			}
+
+			if config.Scheme != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "scheme", Value: strings.ToLower(*config.Scheme)})
+			}
+
+			if len(config.Services) > 0 {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "services", Value: config.Services})
+			}
+
+			if len(config.Tags) > 0 {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "tags", Value: config.Tags})
+			}
+
+			if config.TagSeparator != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "tag_separator", Value: *config.TagSeparator})
+			}
+
+			if len(config.NodeMeta) > 0 {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "node_meta", Value: stringMapToMapSlice(config.NodeMeta)})
+			}
+
+			if config.AllowStale != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "allow_stale", Value: *config.AllowStale})
+			}
+
+			if config.RefreshInterval != nil {
+				configs[i] = append(configs[i], yaml.MapItem{Key: "refresh_interval", Value: *config.RefreshInterval})
+			}
+
+			if config.BasicAuth != nil {
+				if s, ok := store.BasicAuthAssets[assetKey]; ok {
+					configs[i] = append(configs[i], yaml.MapItem{
+						Key: "basic_auth", Value: yaml.MapSlice{
+							{Key: "username", Value: s.Username},
+							{Key: "password", Value: s.Password},
+						},
+					})
+				}
+			}
+
+			configs[i] = cg.addSafeAuthorizationToYaml(configs[i], fmt.Sprintf("scrapeconfig/auth/%s/%s/consulsdconfig/%d", sc.Namespace, sc.Name, i), store, config.Authorization)
+
+			configs[i] = cg.addOAuth2ToYaml(configs[i], config.OAuth2, store, sc.Namespace, assetKey)
+
+			if config.FollowRedirects != nil {
+				configs[i] = cg.WithMinimumVersion("2.26.0").AppendMapItem(configs[i], "follow_redirects", *config.FollowRedirects)
+			}
+
+			if config.EnableHTTP2 != nil {
+				configs[i] = cg.WithMinimumVersion("2.35.0").AppendMapItem(configs[i], "enable_http2", *config.EnableHTTP2)
+			}
+
+			if config.TLSConfig != nil {
+				configs[i] = cg.addSafeTLStoYaml(configs[i], sc.Namespace, *config.TLSConfig)
+			}
+		}
+		cfg = append(cfg, yaml.MapItem{Key: "consul_sd_configs", Value: configs})
+	}
+
+	labeler := namespacelabeler.New(cg.spec.EnforcedNamespaceLabel, cg.spec.ExcludedFromEnforcement, false)
+
+	relabelings := initRelabelings()
+	relabelings = append(relabelings, generateRelabelConfig(labeler.GetRelabelingConfigs(sc.TypeMeta, sc.ObjectMeta, sc.Spec.RelabelConfigs))...)
+	relabelings = cg.generateAddressShardingRelabelingRules(relabelings, shards)
+	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
+
+	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: generateRelabelConfig(labeler.GetRelabelingConfigs(sc.TypeMeta, sc.ObjectMeta, sc.Spec.MetricRelabelConfigs))})
+
+	return cfg
+}
+
 func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	m *v1.ServiceMonitor,
 	ep v1.Endpoint,
@@ -1215,7 +1741,7 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 		role = kubernetesSDRoleEndpointSlice
 	}
 
-	cfg = append(cfg, cg.generateK8SSDConfig(m.Spec.NamespaceSelector, m.Namespace, apiserverConfig, store, role, nil))
+	cfg = append(cfg, cg.generateK8SSDConfig(m.Spec.NamespaceSelector, m.Namespace, apiserverConfig, store, role, cg.attachMetadata(m.Spec.ScrapeClassName, m.Spec.AttachMetadata)))
 
 	if ep.Interval != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: ep.Interval})
@@ -1226,9 +1752,7 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	if ep.Path != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "metrics_path", Value: ep.Path})
 	}
-	if ep.ProxyURL != nil {
-		cfg = append(cfg, yaml.MapItem{Key: "proxy_url", Value: ep.ProxyURL})
-	}
+	cfg = cg.addProxyConfigToYaml(cfg, fmt.Sprintf("serviceMonitor/%s/%s/%d", m.Namespace, m.Name, i), store, ep.ProxyConfig)
 	if ep.Params != nil {
 		cfg = append(cfg, yaml.MapItem{Key: "params", Value: ep.Params})
 	}
@@ -1241,15 +1765,24 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	if ep.EnableHttp2 != nil {
 		cfg = cg.WithMinimumVersion("2.35.0").AppendMapItem(cfg, "enable_http2", *ep.EnableHttp2)
 	}
+	if len(ep.ScrapeProtocols) > 0 {
+		cfg = cg.WithMinimumVersion("2.49.0").AppendMapItem(cfg, "scrape_protocols", ep.ScrapeProtocols)
+	}
+	cfg = cg.addScrapeFallbackProtocolToYaml(cfg, ep.ScrapeFallbackProtocol, m.Spec.ScrapeClassName)
+	cfg = cg.addConvertClassicHistogramsToNHCBToYaml(cfg, ep.ConvertClassicHistogramsToNHCB)
 	assetKey := fmt.Sprintf("serviceMonitor/%s/%s/%d", m.Namespace, m.Name, i)
-	cfg = cg.addOAuth2ToYaml(cfg, ep.OAuth2, store.OAuth2Assets, assetKey)
+	cfg = cg.addOAuth2ToYaml(cfg, ep.OAuth2, store, m.Namespace, assetKey)
 
-	cfg = addTLStoYaml(cfg, m.Namespace, ep.TLSConfig)
+	cfg = cg.addTLStoYaml(cfg, m.Namespace, ep.TLSConfig)
 
 	if ep.BearerTokenFile != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "bearer_token_file", Value: ep.BearerTokenFile})
 	}
 
+	if ep.BearerTokenProjected != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "bearer_token_file", Value: path.Join(projectedTokensDir, assets.ProjectedTokenKey(ep.BearerTokenProjected))})
+	}
+
 	if ep.BearerTokenSecret.Name != "" {
 		if s, ok := store.TokenAssets[fmt.Sprintf("serviceMonitor/%s/%s/%d", m.Namespace, m.Name, i)]; ok {
 			cfg = append(cfg, yaml.MapItem{Key: "bearer_token", Value: s})
@@ -1269,6 +1802,9 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 
 	cfg = cg.addSafeAuthorizationToYaml(cfg, fmt.Sprintf("serviceMonitor/auth/%s/%s/%d", m.Namespace, m.Name, i), store, ep.Authorization)
 
+	hasAuth := ep.BearerTokenFile != "" || ep.BearerTokenProjected != nil || ep.BearerTokenSecret.Name != "" || ep.BasicAuth != nil || ep.OAuth2 != nil || ep.Authorization != nil
+	cfg = cg.addScrapeClassBasicAuthToYaml(cfg, m.Spec.ScrapeClassName, store, hasAuth)
+
 	relabelings := initRelabelings()
 
 	// Filter targets by services selected by the monitor.
@@ -1330,17 +1866,16 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 			{Key: "regex", Value: ep.Port},
 		})
 	} else if ep.TargetPort != nil {
-		if ep.TargetPort.StrVal != "" {
-			relabelings = append(relabelings, yaml.MapSlice{
-				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_container_port_name"}},
-				{Key: "regex", Value: ep.TargetPort.String()},
-			})
-		} else if ep.TargetPort.IntVal != 0 {
+		if port := v1.PortAsString(*ep.TargetPort); port != "" {
+			sourceLabel := "__meta_kubernetes_pod_container_port_number"
+			if ep.TargetPort.Type == intstr.String {
+				sourceLabel = "__meta_kubernetes_pod_container_port_name"
+			}
+
 			relabelings = append(relabelings, yaml.MapSlice{
 				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_container_port_number"}},
-				{Key: "regex", Value: ep.TargetPort.String()},
+				{Key: "source_labels", Value: []string{sourceLabel}},
+				{Key: "regex", Value: port},
 			})
 		}
 	}
@@ -1428,17 +1963,17 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 			{Key: "target_label", Value: "endpoint"},
 			{Key: "replacement", Value: ep.Port},
 		})
-	} else if ep.TargetPort != nil && ep.TargetPort.String() != "" {
+	} else if ep.TargetPort != nil && v1.PortAsString(*ep.TargetPort) != "" {
 		relabelings = append(relabelings, yaml.MapSlice{
 			{Key: "target_label", Value: "endpoint"},
-			{Key: "replacement", Value: ep.TargetPort.String()},
+			{Key: "replacement", Value: v1.PortAsString(*ep.TargetPort)},
 		})
 	}
 
 	labeler := namespacelabeler.New(cg.spec.EnforcedNamespaceLabel, cg.spec.ExcludedFromEnforcement, false)
 	relabelings = append(relabelings, generateRelabelConfig(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, ep.RelabelConfigs))...)
 
-	relabelings = generateAddressShardingRelabelingRules(relabelings, shards)
+	relabelings = cg.generateAddressShardingRelabelingRules(relabelings, shards)
 	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
 
 	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, m.Spec.SampleLimit, cg.spec.EnforcedSampleLimit)
@@ -1447,9 +1982,7 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, cg.spec.EnforcedLabelNameLengthLimit)
 	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, cg.spec.EnforcedLabelValueLengthLimit)
 
-	if cg.spec.EnforcedBodySizeLimit != "" {
-		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", cg.spec.EnforcedBodySizeLimit)
-	}
+	cfg = cg.addBodySizeLimitToYaml(cfg, ep.BodySizeLimit)
 
 	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: generateRelabelConfig(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, ep.MetricRelabelConfigs))})
 
@@ -1466,17 +1999,28 @@ func getLimit(user uint64, enforced *uint64) uint64 {
 	return user
 }
 
-func generateAddressShardingRelabelingRules(relabelings []yaml.MapSlice, shards int32) []yaml.MapSlice {
-	return generateAddressShardingRelabelingRulesWithSourceLabel(relabelings, shards, "__address__")
+// generateAddressShardingRelabelingRules appends the sharding relabelings
+// hashing the labels defined by `shardingLabels` or `__address__` by default.
+func (cg *ConfigGenerator) generateAddressShardingRelabelingRules(relabelings []yaml.MapSlice, shards int32) []yaml.MapSlice {
+	rcs := cg.spec.ShardingRelabelConfigs(0, shards)
+	if len(rcs) == 0 {
+		return relabelings
+	}
+
+	// The configuration is shared by all the shards, the shard index is
+	// substituted from the SHARD environment variable of each pod.
+	rcs[len(rcs)-1].Regex = "$(SHARD)"
+
+	return append(relabelings, generateRelabelConfig(rcs)...)
 }
 
 func generateAddressShardingRelabelingRulesForProbes(relabelings []yaml.MapSlice, shards int32) []yaml.MapSlice {
-	return generateAddressShardingRelabelingRulesWithSourceLabel(relabelings, shards, "__param_target")
+	return generateAddressShardingRelabelingRulesWithSourceLabels(relabelings, shards, "__param_target")
 }
 
-func generateAddressShardingRelabelingRulesWithSourceLabel(relabelings []yaml.MapSlice, shards int32, shardLabel string) []yaml.MapSlice {
+func generateAddressShardingRelabelingRulesWithSourceLabels(relabelings []yaml.MapSlice, shards int32, shardLabels ...string) []yaml.MapSlice {
 	return append(relabelings, yaml.MapSlice{
-		{Key: "source_labels", Value: []string{shardLabel}},
+		{Key: "source_labels", Value: shardLabels},
 		{Key: "target_label", Value: "__tmp_hash"},
 		{Key: "modulus", Value: shards},
 		{Key: "action", Value: "hashmod"},
@@ -1526,6 +2070,45 @@ func generateRelabelConfig(rc []*v1.RelabelConfig) []yaml.MapSlice {
 	return cfg
 }
 
+// generateIngressDefaultRelabelConfig renders the default relabelings of
+// the ingress targets. Unlike generateRelabelConfig, the regex comes before
+// the target label to keep the generated configuration unchanged for
+// existing probes.
+func generateIngressDefaultRelabelConfig(rc []*v1.RelabelConfig) []yaml.MapSlice {
+	var cfg []yaml.MapSlice
+
+	for _, c := range rc {
+		relabeling := yaml.MapSlice{}
+
+		if len(c.SourceLabels) > 0 {
+			relabeling = append(relabeling, yaml.MapItem{Key: "source_labels", Value: c.SourceLabels})
+		}
+
+		if c.Separator != "" {
+			relabeling = append(relabeling, yaml.MapItem{Key: "separator", Value: c.Separator})
+		}
+
+		if c.Regex != "" {
+			relabeling = append(relabeling, yaml.MapItem{Key: "regex", Value: c.Regex})
+		}
+
+		if c.TargetLabel != "" {
+			relabeling = append(relabeling, yaml.MapItem{Key: "target_label", Value: c.TargetLabel})
+		}
+
+		if c.Replacement != "" {
+			relabeling = append(relabeling, yaml.MapItem{Key: "replacement", Value: c.Replacement})
+		}
+
+		if c.Action != "" {
+			relabeling = append(relabeling, yaml.MapItem{Key: "action", Value: strings.ToLower(c.Action)})
+		}
+
+		cfg = append(cfg, relabeling)
+	}
+	return cfg
+}
+
 // GetNamespacesFromNamespaceSelector gets a list of namespaces to select based on
 // the given namespace selector, the given default namespace, and whether to ignore namespace selectors
 func (cg *ConfigGenerator) getNamespacesFromNamespaceSelector(nsel v1.NamespaceSelector, namespace string) []string {
@@ -1594,12 +2177,20 @@ func (cg *ConfigGenerator) generateK8SSDConfig(
 
 		k8sSDConfig = cg.addAuthorizationToYaml(k8sSDConfig, "apiserver/auth", store, apiserverConfig.Authorization)
 
+		k8sSDConfig = cg.addProxyConfigToYaml(k8sSDConfig, "apiserver", store, apiserverConfig.ProxyConfig)
+
 		// TODO: If we want to support secret refs for k8s service discovery tls
 		// config as well, make sure to path the right namespace here.
-		k8sSDConfig = addTLStoYaml(k8sSDConfig, "", apiserverConfig.TLSConfig)
+		k8sSDConfig = cg.addTLStoYaml(k8sSDConfig, "", apiserverConfig.TLSConfig)
 	}
 	if attachMetadata != nil {
-		k8sSDConfig = cg.WithMinimumVersion("2.35.0").AppendMapItem(k8sSDConfig, "attach_metadata", yaml.MapSlice{
+		// Attaching node metadata is supported for the pod role since
+		// v2.35.0 and for the endpoints/endpointslice roles since v2.37.0.
+		minVersion := "2.35.0"
+		if role != kubernetesSDRolePod {
+			minVersion = "2.37.0"
+		}
+		k8sSDConfig = cg.WithMinimumVersion(minVersion).AppendMapItem(k8sSDConfig, "attach_metadata", yaml.MapSlice{
 			{Key: "node", Value: attachMetadata.Node},
 		})
 	}
@@ -1638,7 +2229,7 @@ func (cg *ConfigGenerator) generateAlertmanagerConfig(alerting *v1.AlertingSpec,
 
 		// TODO: If we want to support secret refs for alertmanager config tls
 		// config as well, make sure to path the right namespace here.
-		cfg = addTLStoYaml(cfg, "", am.TLSConfig)
+		cfg = cg.addTLStoYaml(cfg, "", am.TLSConfig)
 
 		cfg = append(cfg, cg.generateK8SSDConfig(v1.NamespaceSelector{}, am.Namespace, apiserverConfig, store, kubernetesSDRoleEndpoint, nil))
 
@@ -1646,6 +2237,12 @@ func (cg *ConfigGenerator) generateAlertmanagerConfig(alerting *v1.AlertingSpec,
 			cfg = append(cfg, yaml.MapItem{Key: "bearer_token_file", Value: am.BearerTokenFile})
 		}
 
+		if am.BearerTokenSecret != nil {
+			if s, ok := store.TokenAssets[fmt.Sprintf("alertmanager/%d", i)]; ok {
+				cfg = append(cfg, yaml.MapItem{Key: "bearer_token", Value: s})
+			}
+		}
+
 		cfg = cg.addSafeAuthorizationToYaml(cfg, fmt.Sprintf("alertmanager/auth/%d", i), store, am.Authorization)
 
 		if am.APIVersion == "v1" || am.APIVersion == "v2" {
@@ -1660,17 +2257,16 @@ func (cg *ConfigGenerator) generateAlertmanagerConfig(alerting *v1.AlertingSpec,
 			{Key: "regex", Value: am.Name},
 		})
 
-		if am.Port.StrVal != "" {
-			relabelings = append(relabelings, yaml.MapSlice{
-				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_endpoint_port_name"}},
-				{Key: "regex", Value: am.Port.String()},
-			})
-		} else if am.Port.IntVal != 0 {
+		if port := v1.PortAsString(am.Port); port != "" {
+			sourceLabel := "__meta_kubernetes_pod_container_port_number"
+			if am.Port.Type == intstr.String {
+				sourceLabel = "__meta_kubernetes_endpoint_port_name"
+			}
+
 			relabelings = append(relabelings, yaml.MapSlice{
 				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_container_port_number"}},
-				{Key: "regex", Value: am.Port.String()},
+				{Key: "source_labels", Value: []string{sourceLabel}},
+				{Key: "regex", Value: port},
 			})
 		}
 
@@ -1716,7 +2312,7 @@ func (cg *ConfigGenerator) generateAdditionalScrapeConfigs(
 				relabelings = append(relabelings, relabeling)
 			}
 		}
-		relabelings = generateAddressShardingRelabelingRules(relabelings, shards)
+		relabelings = cg.generateAddressShardingRelabelingRules(relabelings, shards)
 		addlScrapeConfig = append(addlScrapeConfig, otherConfigItems...)
 		addlScrapeConfig = append(addlScrapeConfig, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
 		addlScrapeConfigs = append(addlScrapeConfigs, addlScrapeConfig)
@@ -1776,14 +2372,20 @@ func (cg *ConfigGenerator) generateRemoteReadConfig(
 			cfg = append(cfg, yaml.MapItem{Key: "bearer_token_file", Value: spec.BearerTokenFile})
 		}
 
-		cfg = cg.addOAuth2ToYaml(cfg, spec.OAuth2, store.OAuth2Assets, fmt.Sprintf("remoteRead/%d", i))
+		cfg = cg.addOAuth2ToYaml(cfg, spec.OAuth2, store, p.ObjectMeta.Namespace, fmt.Sprintf("remoteRead/%d", i))
 
-		cfg = addTLStoYaml(cfg, p.ObjectMeta.Namespace, spec.TLSConfig)
+		cfg = cg.addTLStoYaml(cfg, p.ObjectMeta.Namespace, spec.TLSConfig)
 
 		cfg = cg.addAuthorizationToYaml(cfg, fmt.Sprintf("remoteRead/auth/%d", i), store, spec.Authorization)
 
-		if spec.ProxyURL != "" {
-			cfg = append(cfg, yaml.MapItem{Key: "proxy_url", Value: spec.ProxyURL})
+		cfg = cg.addProxyConfigToYaml(cfg, fmt.Sprintf("remoteRead/%d", i), store, spec.ProxyConfig())
+
+		if spec.FollowRedirects != nil {
+			cfg = cg.WithMinimumVersion("2.26.0").AppendMapItem(cfg, "follow_redirects", *spec.FollowRedirects)
+		}
+
+		if spec.EnableHTTP2 != nil {
+			cfg = cg.WithMinimumVersion("2.35.0").AppendMapItem(cfg, "enable_http2", *spec.EnableHTTP2)
 		}
 
 		cfgs = append(cfgs, cfg)
@@ -1798,14 +2400,15 @@ func (cg *ConfigGenerator) generateRemoteReadConfig(
 func (cg *ConfigGenerator) addOAuth2ToYaml(
 	cfg yaml.MapSlice,
 	oauth2 *v1.OAuth2,
-	tlsAssets map[string]assets.OAuth2Credentials,
+	store *assets.Store,
+	namespace string,
 	assetKey string,
 ) yaml.MapSlice {
 	if oauth2 == nil {
 		return cfg
 	}
 
-	tlsAsset, ok := tlsAssets[assetKey]
+	tlsAsset, ok := store.OAuth2Assets[assetKey]
 	if !ok {
 		return cfg
 	}
@@ -1825,9 +2428,66 @@ func (cg *ConfigGenerator) addOAuth2ToYaml(
 		oauth2Cfg = append(oauth2Cfg, yaml.MapItem{Key: "endpoint_params", Value: oauth2.EndpointParams})
 	}
 
+	oauth2Cfg = cg.WithMinimumVersion("2.43.0").addProxyConfigToYaml(oauth2Cfg, "oauth2/"+assetKey, store, oauth2.ProxyConfig)
+
+	if oauth2.TLSConfig != nil {
+		oauth2Cfg = cg.WithMinimumVersion("2.43.0").AppendMapItem(oauth2Cfg, "tls_config", cg.addSafeTLStoYaml(yaml.MapSlice{}, namespace, *oauth2.TLSConfig)[0].Value)
+	}
+
 	return cg.WithMinimumVersion("2.27.0").AppendMapItem(cfg, "oauth2", oauth2Cfg)
 }
 
+func (cg *ConfigGenerator) addProxyConfigToYaml(
+	cfg yaml.MapSlice,
+	assetKey string,
+	store *assets.Store,
+	proxyConfig v1.ProxyConfig,
+) yaml.MapSlice {
+	if proxyConfig.ProxyURL != nil && *proxyConfig.ProxyURL != "" {
+		cfg = cg.AppendMapItem(cfg, "proxy_url", *proxyConfig.ProxyURL)
+	}
+
+	if proxyConfig.NoProxy != nil && *proxyConfig.NoProxy != "" {
+		cfg = cg.WithMinimumVersion("2.43.0").AppendMapItem(cfg, "no_proxy", *proxyConfig.NoProxy)
+	}
+
+	if proxyConfig.ProxyFromEnvironment != nil {
+		cfg = cg.WithMinimumVersion("2.43.0").AppendMapItem(cfg, "proxy_from_environment", *proxyConfig.ProxyFromEnvironment)
+	}
+
+	if len(proxyConfig.ProxyConnectHeader) == 0 {
+		return cfg
+	}
+
+	headers, ok := store.ProxyConnectHeaderAssets[assetKey]
+	if !ok {
+		return cfg
+	}
+
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	proxyConnectHeader := make(yaml.MapSlice, 0, len(keys))
+	for _, k := range keys {
+		proxyConnectHeader = append(proxyConnectHeader, yaml.MapItem{Key: k, Value: headers[k]})
+	}
+
+	return cg.WithMinimumVersion("2.43.0").AppendMapItem(cfg, "proxy_connect_header", proxyConnectHeader)
+}
+
+// remoteWriteProtobufMessage returns the name of the protobuf message
+// corresponding to the given remote write message version.
+func remoteWriteProtobufMessage(messageVersion string) string {
+	if messageVersion == "V2.0" {
+		return "io.prometheus.write.v2.Request"
+	}
+
+	return "prometheus.WriteRequest"
+}
+
 func (cg *ConfigGenerator) generateRemoteWriteConfig(
 	p *v1.Prometheus,
 	store *assets.Store,
@@ -1836,6 +2496,12 @@ func (cg *ConfigGenerator) generateRemoteWriteConfig(
 	cfgs := []yaml.MapSlice{}
 
 	for i, spec := range p.Spec.RemoteWrite {
+		// Disabled entries are skipped but the index is preserved so that the
+		// assets of the other entries are still found.
+		if spec.Enabled != nil && !*spec.Enabled {
+			continue
+		}
+
 		//defaults
 		if spec.RemoteTimeout == "" {
 			spec.RemoteTimeout = "30s"
@@ -1857,6 +2523,10 @@ func (cg *ConfigGenerator) generateRemoteWriteConfig(
 			cfg = cg.WithMinimumVersion("2.27.0").AppendMapItem(cfg, "send_exemplars", spec.SendExemplars)
 		}
 
+		if spec.MessageVersion != nil {
+			cfg = cg.WithMinimumVersion("2.54.0").AppendMapItem(cfg, "protobuf_message", remoteWriteProtobufMessage(*spec.MessageVersion))
+		}
+
 		if spec.WriteRelabelConfigs != nil {
 			relabelings := []yaml.MapSlice{}
 			for _, c := range spec.WriteRelabelConfigs {
@@ -1915,14 +2585,20 @@ func (cg *ConfigGenerator) generateRemoteWriteConfig(
 			cfg = append(cfg, yaml.MapItem{Key: "bearer_token_file", Value: spec.BearerTokenFile})
 		}
 
-		cfg = cg.addOAuth2ToYaml(cfg, spec.OAuth2, store.OAuth2Assets, fmt.Sprintf("remoteWrite/%d", i))
+		cfg = cg.addOAuth2ToYaml(cfg, spec.OAuth2, store, p.ObjectMeta.Namespace, fmt.Sprintf("remoteWrite/%d", i))
 
-		cfg = addTLStoYaml(cfg, p.ObjectMeta.Namespace, spec.TLSConfig)
+		cfg = cg.addTLStoYaml(cfg, p.ObjectMeta.Namespace, spec.TLSConfig)
 
 		cfg = cg.addAuthorizationToYaml(cfg, fmt.Sprintf("remoteWrite/auth/%d", i), store, spec.Authorization)
 
-		if spec.ProxyURL != "" {
-			cfg = append(cfg, yaml.MapItem{Key: "proxy_url", Value: spec.ProxyURL})
+		cfg = cg.addProxyConfigToYaml(cfg, fmt.Sprintf("remoteWrite/%d", i), store, spec.ProxyConfig())
+
+		if spec.FollowRedirects != nil {
+			cfg = cg.WithMinimumVersion("2.26.0").AppendMapItem(cfg, "follow_redirects", *spec.FollowRedirects)
+		}
+
+		if spec.EnableHTTP2 != nil {
+			cfg = cg.WithMinimumVersion("2.35.0").AppendMapItem(cfg, "enable_http2", *spec.EnableHTTP2)
 		}
 
 		if spec.Sigv4 != nil {
@@ -1986,6 +2662,10 @@ func (cg *ConfigGenerator) generateRemoteWriteConfig(
 				queueConfig = cg.WithMinimumVersion("2.26.0").AppendMapItem(queueConfig, "retry_on_http_429", spec.QueueConfig.RetryOnRateLimit)
 			}
 
+			if spec.QueueConfig.SampleAgeLimit != nil {
+				queueConfig = cg.WithMinimumVersion("2.50.0").AppendMapItem(queueConfig, "sample_age_limit", *spec.QueueConfig.SampleAgeLimit)
+			}
+
 			cfg = append(cfg, yaml.MapItem{Key: "queue_config", Value: queueConfig})
 		}
 
@@ -1998,7 +2678,24 @@ func (cg *ConfigGenerator) generateRemoteWriteConfig(
 			cfg = cg.WithMinimumVersion("2.23.0").AppendMapItem(cfg, "metadata_config", metadataConfig)
 		}
 
-		cfgs = append(cfgs, cfg)
+		if len(spec.URLs) == 0 {
+			cfgs = append(cfgs, cfg)
+			continue
+		}
+
+		// Expand the URLs into one queue per URL sharing the same settings.
+		for j, u := range spec.URLs {
+			c := append(yaml.MapSlice{}, cfg...)
+			for k := range c {
+				switch c[k].Key {
+				case "url":
+					c[k].Value = u
+				case "name":
+					c[k].Value = fmt.Sprintf("%s-%d", spec.Name, j)
+				}
+			}
+			cfgs = append(cfgs, c)
+		}
 	}
 
 	return yaml.MapItem{
@@ -29,6 +29,7 @@ import (
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"gopkg.in/yaml.v2"
@@ -241,6 +242,7 @@ scrape_configs: []
 				map[string]*monitoringv1.ServiceMonitor{},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -446,6 +448,84 @@ func TestNamespaceSetCorrectlyForPodMonitor(t *testing.T) {
 	}
 }
 
+func TestServiceMonitorAttachMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		version  string
+		role     string
+		expected string
+	}{
+		{
+			name:    "endpoints role",
+			version: "v2.37.0",
+			role:    kubernetesSDRoleEndpoint,
+			expected: `kubernetes_sd_configs:
+- role: endpoints
+  attach_metadata:
+    node: true
+`,
+		},
+		{
+			name:    "endpointslice role",
+			version: "v2.37.0",
+			role:    kubernetesSDRoleEndpointSlice,
+			expected: `kubernetes_sd_configs:
+- role: endpointslice
+  attach_metadata:
+    node: true
+`,
+		},
+		{
+			name:    "unsupported Prometheus version",
+			version: "v2.36.0",
+			role:    kubernetesSDRoleEndpoint,
+			expected: `kubernetes_sd_configs:
+- role: endpoints
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sm := &monitoringv1.ServiceMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testservicemonitor1",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					NamespaceSelector: monitoringv1.NamespaceSelector{
+						Any: true,
+					},
+					AttachMetadata: &monitoringv1.AttachMetadata{
+						Node: true,
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(
+				t,
+				&monitoringv1.Prometheus{
+					Spec: monitoringv1.PrometheusSpec{
+						CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+							Version: tc.version,
+						},
+					},
+				},
+			)
+
+			c := cg.generateK8SSDConfig(sm.Spec.NamespaceSelector, sm.Namespace, nil, nil, tc.role, sm.Spec.AttachMetadata)
+
+			s, err := yaml.Marshal(yaml.MapSlice{c})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := string(s)
+			if tc.expected != result {
+				t.Fatalf("Unexpected result.\n\nGot:\n\n%s\n\nExpected:\n\n%s\n\n", result, tc.expected)
+			}
+		})
+	}
+}
+
 func TestProbeStaticTargetsConfigGeneration(t *testing.T) {
 	p := &monitoringv1.Prometheus{
 		ObjectMeta: metav1.ObjectMeta{
@@ -508,6 +588,7 @@ func TestProbeStaticTargetsConfigGeneration(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -627,6 +708,7 @@ func TestProbeStaticTargetsConfigGenerationWithLabelEnforce(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -738,6 +820,7 @@ func TestProbeStaticTargetsConfigGenerationWithJobName(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -843,6 +926,7 @@ func TestProbeStaticTargetsConfigGenerationWithoutModule(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -956,6 +1040,7 @@ func TestProbeIngressSDConfigGeneration(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1103,6 +1188,7 @@ func TestProbeIngressSDConfigGenerationWithShards(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1249,6 +1335,7 @@ func TestProbeIngressSDConfigGenerationWithLabelEnforce(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1459,6 +1546,7 @@ func TestAlertmanagerBearerToken(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1539,6 +1627,7 @@ func TestAlertmanagerAPIVersion(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1620,6 +1709,7 @@ func TestAlertmanagerTimeoutConfig(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1707,6 +1797,7 @@ func TestAdditionalScrapeConfigs(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 			&assets.Store{},
 			[]byte(`- job_name: prometheus
   scrape_interval: 15s
@@ -1853,6 +1944,67 @@ scrape_configs:
 	}
 }
 
+func TestAlertRelabelConfigs(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			Alerting: &monitoringv1.AlertingSpec{
+				Alertmanagers: []monitoringv1.AlertmanagerEndpoints{
+					{
+						Name:      "alertmanager-main",
+						Namespace: "default",
+						Port:      intstr.FromString("web"),
+					},
+				},
+				AlertRelabelConfigs: []monitoringv1.RelabelConfig{
+					{
+						TargetLabel: "team",
+						Replacement: "infra",
+					},
+				},
+			},
+		},
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+
+	cfg, err := cg.Generate(
+		p,
+		nil,
+		nil,
+		nil,
+		nil,
+		&assets.Store{},
+		nil,
+		[]byte(`- action: drop
+  source_labels: [__meta_kubernetes_node_name]
+  regex: spot-(.+)
+`),
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  - target_label: team
+    replacement: infra
+  - action: drop
+    source_labels:
+    - __meta_kubernetes_node_name
+    regex: spot-(.+)
+`
+	if !strings.Contains(string(cfg), expected) {
+		t.Fatalf("expected Prometheus configuration to contain:\n%s\nFull config:\n%s", expected, cfg)
+	}
+}
+
 func TestAdditionalAlertRelabelConfigs(t *testing.T) {
 	p := &monitoringv1.Prometheus{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1879,6 +2031,7 @@ func TestAdditionalAlertRelabelConfigs(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		[]byte(`- action: drop
@@ -1993,6 +2146,7 @@ func TestNoEnforcedNamespaceLabelServiceMonitor(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2072,15 +2226,6 @@ scrape_configs:
   - target_label: job
     replacement: crio
     action: replace
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs:
   - source_labels:
     - __name__
@@ -2159,6 +2304,7 @@ func TestServiceMonitorWithEndpointSliceEnable(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2241,15 +2387,6 @@ scrape_configs:
     action: replace
   - target_label: ns-key
     replacement: default
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs:
   - source_labels:
     - pod_name
@@ -2322,6 +2459,7 @@ func TestEnforcedNamespaceLabelPodMonitor(t *testing.T) {
 			},
 		},
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2389,15 +2527,6 @@ scrape_configs:
     action: replace
   - target_label: ns-key
     replacement: pod-monitor-ns
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs:
   - source_labels:
     - pod_name
@@ -2481,6 +2610,7 @@ func TestEnforcedNamespaceLabelOnExcludedPodMonitor(t *testing.T) {
 			},
 		},
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2546,15 +2676,6 @@ scrape_configs:
     regex: (.*)
     replacement: $1
     action: replace
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs:
   - source_labels:
     - pod_name
@@ -2633,6 +2754,7 @@ func TestEnforcedNamespaceLabelServiceMonitor(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2715,15 +2837,6 @@ scrape_configs:
     action: replace
   - target_label: ns-key
     replacement: default
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs:
   - source_labels:
     - pod_name
@@ -2811,6 +2924,7 @@ func TestEnforcedNamespaceLabelOnExcludedServiceMonitor(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2886,15 +3000,6 @@ scrape_configs:
     regex: (.*)
     replacement: $1
     action: replace
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs:
   - source_labels:
     - pod_name
@@ -2936,6 +3041,7 @@ func TestAdditionalAlertmanagers(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3033,6 +3139,7 @@ func TestSettingHonorTimestampsInServiceMonitor(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3109,15 +3216,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -3172,6 +3270,7 @@ func TestSettingHonorTimestampsInPodMonitor(t *testing.T) {
 			},
 		},
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3233,15 +3332,6 @@ scrape_configs:
     replacement: default/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -3298,6 +3388,7 @@ func TestHonorTimestampsOverriding(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3374,15 +3465,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -3438,6 +3520,7 @@ func TestSettingHonorLabels(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3513,15 +3596,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -3577,6 +3651,7 @@ func TestHonorLabelsOverriding(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3652,15 +3727,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -3716,6 +3782,7 @@ func TestTargetLabels(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3791,15 +3858,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -4005,6 +4063,7 @@ oauth2:
 				tt.sMons,
 				tt.pMons,
 				tt.probes,
+				nil,
 				&assets.Store{
 					BasicAuthAssets: map[string]assets.BasicAuthCredentials{},
 					OAuth2Assets:    tt.oauth2Credentials,
@@ -4028,20 +4087,104 @@ oauth2:
 	}
 }
 
-func TestPodTargetLabels(t *testing.T) {
-	p := &monitoringv1.Prometheus{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "test",
-			Namespace: "default",
-		},
-		Spec: monitoringv1.PrometheusSpec{
-			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
-				ServiceMonitorSelector: &metav1.LabelSelector{
-					MatchLabels: map[string]string{
-						"group": "group1",
-					},
+func TestOAuth2TLSConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected bool
+	}{
+		{name: "supported version", version: "v2.43.0", expected: true},
+		{name: "unsupported version", version: "v2.42.0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
 				},
-			},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+						RemoteWrite: []monitoringv1.RemoteWriteSpec{
+							{
+								URL: "http://example.com",
+								OAuth2: &monitoringv1.OAuth2{
+									ClientID: monitoringv1.SecretOrConfigMap{
+										Secret: &v1.SecretKeySelector{
+											LocalObjectReference: v1.LocalObjectReference{Name: "oauth2"},
+											Key:                  "client_id",
+										},
+									},
+									ClientSecret: v1.SecretKeySelector{
+										LocalObjectReference: v1.LocalObjectReference{Name: "oauth2"},
+										Key:                  "client_secret",
+									},
+									TokenURL: "https://idp.example.com/token",
+									TLSConfig: &monitoringv1.SafeTLSConfig{
+										CA: monitoringv1.SecretOrConfigMap{
+											ConfigMap: &v1.ConfigMapKeySelector{
+												LocalObjectReference: v1.LocalObjectReference{Name: "idp-ca"},
+												Key:                  "ca.crt",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(
+				p,
+				nil,
+				nil,
+				nil,
+				nil,
+				&assets.Store{
+					OAuth2Assets: map[string]assets.OAuth2Credentials{
+						"remoteWrite/0": {
+							ClientID:     "client",
+							ClientSecret: "secret",
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := `    token_url: https://idp.example.com/token
+    tls_config:
+      insecure_skip_verify: false
+      ca_file: /etc/prometheus/certs/configmap_default_idp-ca_ca.crt
+`
+			if got := strings.Contains(string(cfg), expected); got != tc.expected {
+				t.Fatalf("expected OAuth2 TLS config to be rendered: %v, got:\n%s", tc.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestPodTargetLabels(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				ServiceMonitorSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"group": "group1",
+					},
+				},
+			},
 		},
 	}
 
@@ -4071,6 +4214,7 @@ func TestPodTargetLabels(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -4146,15 +4290,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -4209,6 +4344,7 @@ func TestPodTargetLabelsFromPodMonitor(t *testing.T) {
 			},
 		},
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -4269,15 +4405,6 @@ scrape_configs:
     replacement: default/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -4322,6 +4449,7 @@ func TestEmptyEndointPorts(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -4388,15 +4516,6 @@ scrape_configs:
     - __meta_kubernetes_service_name
     target_label: job
     replacement: ${1}
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -4468,6 +4587,7 @@ func generateTestConfig(t *testing.T, version string) ([]byte, error) {
 		makeServiceMonitors(),
 		makePodMonitors(),
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -4954,15 +5074,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -5021,15 +5132,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   sample_limit: %d
   metric_relabel_configs: []
 `
@@ -5112,6 +5214,7 @@ scrape_configs:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -5187,15 +5290,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -5254,15 +5348,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   target_limit: %d
   metric_relabel_configs: []
 `
@@ -5373,6 +5458,7 @@ scrape_configs:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -5477,6 +5563,66 @@ remote_read:
   authorization:
     type: Bearer
     credentials: secret
+`,
+		},
+		{
+			version: "v2.35.0",
+			remoteRead: monitoringv1.RemoteReadSpec{
+				URL:             "http://example.com",
+				FollowRedirects: pointer.BoolPtr(false),
+				EnableHTTP2:     pointer.BoolPtr(false),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_read:
+- url: http://example.com
+  remote_timeout: 30s
+  follow_redirects: false
+  enable_http2: false
+`,
+		},
+		{
+			version: "v2.26.0",
+			remoteRead: monitoringv1.RemoteReadSpec{
+				URL:             "http://example.com",
+				FollowRedirects: pointer.BoolPtr(false),
+				EnableHTTP2:     pointer.BoolPtr(false),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_read:
+- url: http://example.com
+  remote_timeout: 30s
+  follow_redirects: false
+`,
+		},
+		{
+			version: "v2.25.0",
+			remoteRead: monitoringv1.RemoteReadSpec{
+				URL:             "http://example.com",
+				FollowRedirects: pointer.BoolPtr(false),
+				EnableHTTP2:     pointer.BoolPtr(false),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_read:
+- url: http://example.com
+  remote_timeout: 30s
 `,
 		},
 		{
@@ -5518,6 +5664,7 @@ remote_read:
 				nil,
 				nil,
 				nil,
+				nil,
 				&assets.Store{
 					BasicAuthAssets: map[string]assets.BasicAuthCredentials{},
 					OAuth2Assets: map[string]assets.OAuth2Credentials{
@@ -5559,6 +5706,66 @@ func TestRemoteWriteConfig(t *testing.T) {
 		expected    string
 		expectedErr error
 	}{
+		{
+			version: "v2.35.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:             "http://example.com",
+				FollowRedirects: pointer.BoolPtr(false),
+				EnableHTTP2:     pointer.BoolPtr(false),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+  follow_redirects: false
+  enable_http2: false
+`,
+		},
+		{
+			version: "v2.26.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:             "http://example.com",
+				FollowRedirects: pointer.BoolPtr(false),
+				EnableHTTP2:     pointer.BoolPtr(false),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+  follow_redirects: false
+`,
+		},
+		{
+			version: "v2.25.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:             "http://example.com",
+				FollowRedirects: pointer.BoolPtr(false),
+				EnableHTTP2:     pointer.BoolPtr(false),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+`,
+		},
 		{
 			version: "v2.22.0",
 			remoteWrite: monitoringv1.RemoteWriteSpec{
@@ -5943,43 +6150,123 @@ remote_write:
     retry_on_http_429: true
 `,
 		},
-	} {
-		t.Run(fmt.Sprintf("version=%s", tc.version), func(t *testing.T) {
-			prometheus := monitoringv1.Prometheus{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test",
-					Namespace: "default",
-				},
-				Spec: monitoringv1.PrometheusSpec{
-					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
-						Version: tc.version,
-						ServiceMonitorSelector: &metav1.LabelSelector{
-							MatchLabels: map[string]string{
-								"group": "group1",
-							},
-						},
-						RemoteWrite: []monitoringv1.RemoteWriteSpec{tc.remoteWrite},
-						Secrets:     []string{"sigv4-secret"},
-					},
-				},
-			}
-
-			store := &assets.Store{
-				BasicAuthAssets: map[string]assets.BasicAuthCredentials{},
-				OAuth2Assets: map[string]assets.OAuth2Credentials{
-					"remoteWrite/0": {
-						ClientID:     "client-id",
-						ClientSecret: "client-secret",
-					},
-				},
-				TokenAssets: map[string]assets.Token{
-					"remoteWrite/auth/0": assets.Token("secret"),
-				}}
-			if tc.remoteWrite.Sigv4 != nil && tc.remoteWrite.Sigv4.AccessKey != nil {
-				store.SigV4Assets = map[string]assets.SigV4Credentials{
-					"remoteWrite/0": {
-						AccessKeyID: "access-key",
-						SecretKeyID: "secret-key",
+		{
+			version: "v2.53.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:            "http://example.com",
+				MessageVersion: pointer.StringPtr("V2.0"),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+`,
+		},
+		{
+			version: "v2.54.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:            "http://example.com",
+				MessageVersion: pointer.StringPtr("V2.0"),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+  protobuf_message: io.prometheus.write.v2.Request
+`,
+		},
+		{
+			version: "v2.49.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				QueueConfig: &monitoringv1.QueueConfig{
+					SampleAgeLimit: (*monitoringv1.Duration)(pointer.StringPtr("1h")),
+				},
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+  queue_config: {}
+`,
+		},
+		{
+			version: "v2.50.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				QueueConfig: &monitoringv1.QueueConfig{
+					SampleAgeLimit: (*monitoringv1.Duration)(pointer.StringPtr("1h")),
+				},
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+  queue_config:
+    sample_age_limit: 1h
+`,
+		},
+	} {
+		t.Run(fmt.Sprintf("version=%s", tc.version), func(t *testing.T) {
+			prometheus := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+						ServiceMonitorSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"group": "group1",
+							},
+						},
+						RemoteWrite: []monitoringv1.RemoteWriteSpec{tc.remoteWrite},
+						Secrets:     []string{"sigv4-secret"},
+					},
+				},
+			}
+
+			store := &assets.Store{
+				BasicAuthAssets: map[string]assets.BasicAuthCredentials{},
+				OAuth2Assets: map[string]assets.OAuth2Credentials{
+					"remoteWrite/0": {
+						ClientID:     "client-id",
+						ClientSecret: "client-secret",
+					},
+				},
+				TokenAssets: map[string]assets.Token{
+					"remoteWrite/auth/0": assets.Token("secret"),
+				}}
+			if tc.remoteWrite.Sigv4 != nil && tc.remoteWrite.Sigv4.AccessKey != nil {
+				store.SigV4Assets = map[string]assets.SigV4Credentials{
+					"remoteWrite/0": {
+						AccessKeyID: "access-key",
+						SecretKeyID: "secret-key",
 					},
 				}
 			}
@@ -5990,6 +6277,7 @@ remote_write:
 				nil,
 				nil,
 				nil,
+				nil,
 				store,
 				nil,
 				nil,
@@ -6068,15 +6356,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -6135,15 +6414,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   label_limit: %d
   metric_relabel_configs: []
 `
@@ -6256,6 +6526,7 @@ scrape_configs:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -6316,15 +6587,6 @@ scrape_configs:
     replacement: default/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -6368,15 +6630,6 @@ scrape_configs:
     replacement: default/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   label_name_length_limit: %d
   metric_relabel_configs: []
 `
@@ -6488,6 +6741,7 @@ scrape_configs:
 					"testpodmonitor1": &podMonitor,
 				},
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -6701,6 +6955,7 @@ scrape_configs:
 				map[string]*monitoringv1.Probe{
 					"testprobe1": &probe,
 				},
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -6721,6 +6976,8 @@ scrape_configs:
 }
 
 func TestBodySizeLimits(t *testing.T) {
+	byteSizePtr := func(s monitoringv1.ByteSize) *monitoringv1.ByteSize { return &s }
+
 	expectNoLimit := `global:
   evaluation_interval: 30s
   scrape_interval: 30s
@@ -6776,15 +7033,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -6843,15 +7091,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   body_size_limit: %s
   metric_relabel_configs: []
 `
@@ -6859,6 +7098,7 @@ scrape_configs:
 	for _, tc := range []struct {
 		version               string
 		enforcedBodySizeLimit monitoringv1.ByteSize
+		bodySizeLimit         *monitoringv1.ByteSize
 		expected              string
 		expectedErr           error
 	}{
@@ -6877,6 +7117,28 @@ scrape_configs:
 			enforcedBodySizeLimit: "",
 			expected:              expectNoLimit,
 		},
+		{
+			version:       "v2.27.0",
+			bodySizeLimit: byteSizePtr("10MB"),
+			expected:      expectNoLimit,
+		},
+		{
+			version:       "v2.28.0",
+			bodySizeLimit: byteSizePtr("10MB"),
+			expected:      fmt.Sprintf(expectLimit, "10MB"),
+		},
+		{
+			version:               "v2.28.0",
+			enforcedBodySizeLimit: "1000MB",
+			bodySizeLimit:         byteSizePtr("1GB"),
+			expected:              fmt.Sprintf(expectLimit, "1000MB"),
+		},
+		{
+			version:               "v2.28.0",
+			enforcedBodySizeLimit: "1GB",
+			bodySizeLimit:         byteSizePtr("512MB"),
+			expected:              fmt.Sprintf(expectLimit, "512MB"),
+		},
 		{
 			version:               "v2.28.0",
 			enforcedBodySizeLimit: "100",
@@ -6931,8 +7193,9 @@ scrape_configs:
 				Spec: monitoringv1.ServiceMonitorSpec{
 					Endpoints: []monitoringv1.Endpoint{
 						{
-							Port:     "web",
-							Interval: "30s",
+							Port:          "web",
+							Interval:      "30s",
+							BodySizeLimit: tc.bodySizeLimit,
 						},
 					},
 				},
@@ -6946,6 +7209,7 @@ scrape_configs:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -7008,6 +7272,7 @@ func TestMatchExpressionsServiceMonitor(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -7078,15 +7343,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -7153,15 +7409,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -7221,15 +7468,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 	expectedWithRedirectsEnabled := `global:
@@ -7288,15 +7526,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -7393,6 +7622,7 @@ scrape_configs:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -7454,15 +7684,6 @@ scrape_configs:
     replacement: pod-monitor-ns/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -7507,15 +7728,6 @@ scrape_configs:
     replacement: pod-monitor-ns/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 	expectedWithRedirectsEnabled := `global:
@@ -7559,15 +7771,6 @@ scrape_configs:
     replacement: pod-monitor-ns/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -7664,6 +7867,7 @@ scrape_configs:
 					"testpodmonitor1": &podMonitor,
 				},
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -7740,15 +7944,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -7808,15 +8003,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 	expectedWithHTTP2Enabled := `global:
@@ -7875,15 +8061,6 @@ scrape_configs:
     replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -7980,6 +8157,7 @@ scrape_configs:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -8041,6 +8219,7 @@ func TestPodMonitorPhaseFilter(t *testing.T) {
 			},
 		},
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -8086,15 +8265,6 @@ scrape_configs:
     replacement: default/testpodmonitor1
   - target_label: endpoint
     replacement: test
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -8147,15 +8317,6 @@ scrape_configs:
     replacement: pod-monitor-ns/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -8200,15 +8361,6 @@ scrape_configs:
     replacement: pod-monitor-ns/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 	expectedWithHTTP2Enabled := `global:
@@ -8252,15 +8404,6 @@ scrape_configs:
     replacement: pod-monitor-ns/testpodmonitor1
   - target_label: endpoint
     replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs: []
 `
 
@@ -8357,6 +8500,7 @@ scrape_configs:
 					"testpodmonitor1": &podMonitor,
 				},
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -8464,6 +8608,7 @@ scrape_configs: []
 				nil,
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -8569,6 +8714,7 @@ storage:
 				nil,
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -8589,100 +8735,570 @@ storage:
 	}
 }
 
-func TestGenerateRelabelConfig(t *testing.T) {
-	p := &monitoringv1.Prometheus{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "test",
-			Namespace: "test-relabel",
+func TestOTLPConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		version     string
+		otlp        *monitoringv1.OTLPConfig
+		expectedErr bool
+		expected    string
+	}{
+		{
+			name:    "OTLP config < v2.55.0",
+			version: "v2.54.0",
+			otlp: &monitoringv1.OTLPConfig{
+				PromoteResourceAttributes: []string{"service.name"},
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+`,
 		},
-		Spec: monitoringv1.PrometheusSpec{},
-	}
+		{
+			name:    "OTLP config >= v2.55.0",
+			version: "v2.55.0",
+			otlp: &monitoringv1.OTLPConfig{
+				PromoteResourceAttributes: []string{"service.name", "k8s.namespace.name"},
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+otlp:
+  promote_resource_attributes:
+  - service.name
+  - k8s.namespace.name
+`,
+		},
+		{
+			name:    "invalid resource attribute",
+			version: "v2.55.0",
+			otlp: &monitoringv1.OTLPConfig{
+				PromoteResourceAttributes: []string{""},
+			},
+			expectedErr: true,
+		},
+		{
+			name:    "blank resource attribute",
+			version: "v2.55.0",
+			otlp: &monitoringv1.OTLPConfig{
+				PromoteResourceAttributes: []string{"service.name", "  "},
+			},
+			expectedErr: true,
+		},
+		{
+			name:    "duplicated resource attribute",
+			version: "v2.55.0",
+			otlp: &monitoringv1.OTLPConfig{
+				PromoteResourceAttributes: []string{"service.name", "service.name"},
+			},
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+					},
+					OTLP: tc.otlp,
+				},
+			}
+			cg := mustNewConfigGenerator(t, p)
 
-	cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(
+				p,
+				nil,
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
 
-	cfg, err := cg.Generate(
-		p,
-		map[string]*monitoringv1.ServiceMonitor{
-			"test": {
+			actualConfig := string(cfg)
+
+			if tc.expected != actualConfig {
+				t.Logf("\n%s", pretty.Compare(tc.expected, actualConfig))
+				t.Fatal("expected OTLP configuration doesn't match with actual configuration")
+			}
+		})
+	}
+}
+
+func TestEnableOTLPReceiver(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		version     string
+		enabled     bool
+		expectedErr bool
+	}{
+		{
+			name:    "disabled with unsupported version",
+			version: "v2.46.0",
+		},
+		{
+			name:        "enabled with unsupported version",
+			version:     "v2.46.0",
+			enabled:     true,
+			expectedErr: true,
+		},
+		{
+			name:    "enabled with feature flag",
+			version: "v2.47.0",
+			enabled: true,
+		},
+		{
+			name:    "enabled with Prometheus v3",
+			version: "v3.0.0",
+			enabled: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test",
 					Namespace: "default",
 				},
-				Spec: monitoringv1.ServiceMonitorSpec{
-					Selector: metav1.LabelSelector{
-						MatchLabels: map[string]string{
-							"foo": "bar",
-						},
-					},
-					Endpoints: []monitoringv1.Endpoint{
-						{
-							Port:     "https-metrics",
-							Interval: "30s",
-							MetricRelabelConfigs: []*monitoringv1.RelabelConfig{
-								{
-									Action:       "Drop",
-									Regex:        "container_fs*",
-									SourceLabels: []monitoringv1.LabelName{"__name__"},
-								},
-							},
-							RelabelConfigs: []*monitoringv1.RelabelConfig{
-								{
-									Action:       "Uppercase",
-									SourceLabels: []monitoringv1.LabelName{"instance"},
-									TargetLabel:  "instance",
-								},
-								{
-									Action:       "Replace",
-									Regex:        "(.+)(?::d+)",
-									Replacement:  "$1:9537",
-									SourceLabels: []monitoringv1.LabelName{"__address__"},
-									TargetLabel:  "__address__",
-								},
-								{
-									Action:      "Replace",
-									Replacement: "crio",
-									TargetLabel: "job",
-								},
-							},
-						},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version:            tc.version,
+						EnableOTLPReceiver: pointer.BoolPtr(tc.enabled),
 					},
 				},
-			},
-		},
-		nil,
-		nil,
-		&assets.Store{},
-		nil,
-		nil,
-		nil,
-		nil,
-	)
-	if err != nil {
-		t.Fatal(err)
+			}
+			cg := mustNewConfigGenerator(t, p)
+
+			_, err := cg.Generate(
+				p,
+				nil,
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if tc.expectedErr && err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !tc.expectedErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
 	}
+}
 
-	expected := `global:
+func TestTracingConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		version  string
+		tracing  *monitoringv1.PrometheusTracingConfig
+		expected string
+	}{
+		{
+			name:    "tracing config < v2.34.0",
+			version: "v2.33.0",
+			tracing: &monitoringv1.PrometheusTracingConfig{
+				Endpoint: "otel-collector:4317",
+			},
+			expected: `global:
   evaluation_interval: 30s
   scrape_interval: 30s
   external_labels:
-    prometheus: test-relabel/test
+    prometheus: default/test
     prometheus_replica: $(POD_NAME)
-scrape_configs:
-- job_name: serviceMonitor/default/test/0
-  honor_labels: false
-  kubernetes_sd_configs:
-  - role: endpoints
-    namespaces:
-      names:
-      - default
+scrape_configs: []
+`,
+		},
+		{
+			name:    "minimal tracing config",
+			version: "v2.34.0",
+			tracing: &monitoringv1.PrometheusTracingConfig{
+				Endpoint: "otel-collector:4317",
+			},
+			expected: `global:
+  evaluation_interval: 30s
   scrape_interval: 30s
-  relabel_configs:
-  - source_labels:
-    - job
-    target_label: __tmp_prometheus_job_name
-  - action: keep
-    source_labels:
-    - __meta_kubernetes_service_label_foo
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+tracing:
+  endpoint: otel-collector:4317
+`,
+		},
+		{
+			name:    "full tracing config",
+			version: "v2.39.0",
+			tracing: &monitoringv1.PrometheusTracingConfig{
+				Endpoint:         "otel-collector:4318",
+				ClientType:       pointer.StringPtr("http"),
+				SamplingFraction: pointer.StringPtr("0.5"),
+				Insecure:         pointer.BoolPtr(true),
+				Headers: map[string]string{
+					"custom": "header",
+				},
+				Compression: pointer.StringPtr("gzip"),
+				Timeout:     (*monitoringv1.Duration)(pointer.StringPtr("10s")),
+				TLSConfig: &monitoringv1.TLSConfig{
+					CAFile: "/etc/ca.crt",
+				},
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+tracing:
+  endpoint: otel-collector:4318
+  client_type: http
+  sampling_fraction: 0.5
+  insecure: true
+  headers:
+    custom: header
+  compression: gzip
+  timeout: 10s
+  tls_config:
+    insecure_skip_verify: false
+    ca_file: /etc/ca.crt
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+					},
+					TracingConfig: tc.tracing,
+				},
+			}
+			cg := mustNewConfigGenerator(t, p)
+
+			cfg, err := cg.Generate(
+				p,
+				nil,
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actualConfig := string(cfg)
+
+			if tc.expected != actualConfig {
+				t.Logf("\n%s", pretty.Compare(tc.expected, actualConfig))
+				t.Fatal("expected tracing configuration doesn't match with actual configuration")
+			}
+		})
+	}
+}
+
+func TestScrapeClassBasicAuth(t *testing.T) {
+	store := &assets.Store{
+		BasicAuthAssets: map[string]assets.BasicAuthCredentials{
+			"scrapeClass/default": {
+				Username: "default-user",
+				Password: "default-pass",
+			},
+			"scrapeClass/other": {
+				Username: "other-user",
+				Password: "other-pass",
+			},
+		},
+	}
+	basicAuth := &monitoringv1.BasicAuth{
+		Username: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "creds"},
+			Key:                  "username",
+		},
+		Password: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "creds"},
+			Key:                  "password",
+		},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		scrapeClasses   []monitoringv1.ScrapeClass
+		scrapeClassName *string
+		hasAuth         bool
+		expected        string
+	}{
+		{
+			name:     "no scrape class",
+			expected: "{}\n",
+		},
+		{
+			name: "default scrape class",
+			scrapeClasses: []monitoringv1.ScrapeClass{
+				{Name: "default", Default: pointer.BoolPtr(true), DefaultBasicAuth: basicAuth},
+				{Name: "other", DefaultBasicAuth: basicAuth},
+			},
+			expected: `basic_auth:
+  username: default-user
+  password: default-pass
+`,
+		},
+		{
+			name: "explicit scrape class",
+			scrapeClasses: []monitoringv1.ScrapeClass{
+				{Name: "default", Default: pointer.BoolPtr(true), DefaultBasicAuth: basicAuth},
+				{Name: "other", DefaultBasicAuth: basicAuth},
+			},
+			scrapeClassName: pointer.StringPtr("other"),
+			expected: `basic_auth:
+  username: other-user
+  password: other-pass
+`,
+		},
+		{
+			name: "no default scrape class",
+			scrapeClasses: []monitoringv1.ScrapeClass{
+				{Name: "other", DefaultBasicAuth: basicAuth},
+			},
+			expected: "{}\n",
+		},
+		{
+			name: "scrape object with authentication",
+			scrapeClasses: []monitoringv1.ScrapeClass{
+				{Name: "default", Default: pointer.BoolPtr(true), DefaultBasicAuth: basicAuth},
+			},
+			hasAuth:  true,
+			expected: "{}\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := mustNewConfigGenerator(
+				t,
+				&monitoringv1.Prometheus{
+					Spec: monitoringv1.PrometheusSpec{
+						CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+							ScrapeClasses: tc.scrapeClasses,
+						},
+					},
+				},
+			)
+
+			cfg := cg.addScrapeClassBasicAuthToYaml(yaml.MapSlice{}, tc.scrapeClassName, store, tc.hasAuth)
+			b, err := yaml.Marshal(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.expected != string(b) {
+				t.Logf("\n%s", pretty.Compare(tc.expected, string(b)))
+				t.Fatal("expected basic auth configuration doesn't match with actual configuration")
+			}
+		})
+	}
+}
+
+func TestScrapeClassDefaults(t *testing.T) {
+	classProtocol := monitoringv1.OpenMetricsText1_0_0
+	globalProtocol := monitoringv1.PrometheusText0_0_4
+	endpointProtocol := monitoringv1.PrometheusProto
+
+	for _, tc := range []struct {
+		name             string
+		scrapeClassName  *string
+		attachMetadata   *monitoringv1.AttachMetadata
+		protocol         *monitoringv1.ScrapeProtocol
+		expectedMetadata *monitoringv1.AttachMetadata
+		expectedProtocol monitoringv1.ScrapeProtocol
+	}{
+		{
+			name:             "default scrape class",
+			expectedMetadata: &monitoringv1.AttachMetadata{Node: true},
+			expectedProtocol: classProtocol,
+		},
+		{
+			name:             "scrape object settings",
+			attachMetadata:   &monitoringv1.AttachMetadata{Node: false},
+			protocol:         &endpointProtocol,
+			expectedMetadata: &monitoringv1.AttachMetadata{Node: false},
+			expectedProtocol: endpointProtocol,
+		},
+		{
+			name:             "scrape class without settings",
+			scrapeClassName:  pointer.StringPtr("other"),
+			expectedProtocol: globalProtocol,
+		},
+		{
+			name:             "unknown scrape class",
+			scrapeClassName:  pointer.StringPtr("unknown"),
+			expectedProtocol: globalProtocol,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := mustNewConfigGenerator(
+				t,
+				&monitoringv1.Prometheus{
+					Spec: monitoringv1.PrometheusSpec{
+						CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+							Version:                "v3.0.0",
+							ScrapeFallbackProtocol: &globalProtocol,
+							ScrapeClasses: []monitoringv1.ScrapeClass{
+								{
+									Name:                   "default",
+									Default:                pointer.BoolPtr(true),
+									AttachMetadata:         &monitoringv1.AttachMetadata{Node: true},
+									FallbackScrapeProtocol: &classProtocol,
+								},
+								{Name: "other"},
+							},
+						},
+					},
+				},
+			)
+
+			if diff := cmp.Diff(tc.expectedMetadata, cg.attachMetadata(tc.scrapeClassName, tc.attachMetadata)); diff != "" {
+				t.Fatalf("unexpected attach metadata (-want +got):\n%s", diff)
+			}
+
+			cfg := cg.addScrapeFallbackProtocolToYaml(yaml.MapSlice{}, tc.protocol, tc.scrapeClassName)
+			expected := yaml.MapSlice{{Key: "fallback_scrape_protocol", Value: tc.expectedProtocol}}
+			if diff := cmp.Diff(expected, cfg); diff != "" {
+				t.Fatalf("unexpected configuration (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateRelabelConfig(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-relabel",
+		},
+		Spec: monitoringv1.PrometheusSpec{},
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+
+	cfg, err := cg.Generate(
+		p,
+		map[string]*monitoringv1.ServiceMonitor{
+			"test": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"foo": "bar",
+						},
+					},
+					Endpoints: []monitoringv1.Endpoint{
+						{
+							Port:     "https-metrics",
+							Interval: "30s",
+							MetricRelabelConfigs: []*monitoringv1.RelabelConfig{
+								{
+									Action:       "Drop",
+									Regex:        "container_fs*",
+									SourceLabels: []monitoringv1.LabelName{"__name__"},
+								},
+							},
+							RelabelConfigs: []*monitoringv1.RelabelConfig{
+								{
+									Action:       "Uppercase",
+									SourceLabels: []monitoringv1.LabelName{"instance"},
+									TargetLabel:  "instance",
+								},
+								{
+									Action:       "Replace",
+									Regex:        "(.+)(?::d+)",
+									Replacement:  "$1:9537",
+									SourceLabels: []monitoringv1.LabelName{"__address__"},
+									TargetLabel:  "__address__",
+								},
+								{
+									Action:      "Replace",
+									Replacement: "crio",
+									TargetLabel: "job",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		nil,
+		nil,
+		nil,
+		&assets.Store{},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: test-relabel/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs:
+- job_name: serviceMonitor/default/test/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: endpoints
+    namespaces:
+      names:
+      - default
+  scrape_interval: 30s
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_service_label_foo
     - __meta_kubernetes_service_labelpresent_foo
     regex: (bar);true
   - action: keep
@@ -8734,15 +9350,6 @@ scrape_configs:
   - target_label: job
     replacement: crio
     action: replace
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
   metric_relabel_configs:
   - source_labels:
     - __name__
@@ -8760,3 +9367,1218 @@ scrape_configs:
 func getInt64Pointer(i int64) *int64 {
 	return &i
 }
+
+func TestProxyConfig(t *testing.T) {
+	store := &assets.Store{
+		ProxyConnectHeaderAssets: map[string]assets.ProxyConnectHeader{
+			"serviceMonitor/default/test/0": {
+				"X-Foo":               []string{"foo"},
+				"Proxy-Authorization": []string{"secret"},
+			},
+		},
+	}
+	proxyConnectHeader := map[string][]v1.SecretKeySelector{
+		"X-Foo": {
+			{
+				LocalObjectReference: v1.LocalObjectReference{Name: "proxy"},
+				Key:                  "foo",
+			},
+		},
+		"Proxy-Authorization": {
+			{
+				LocalObjectReference: v1.LocalObjectReference{Name: "proxy"},
+				Key:                  "auth",
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		version     string
+		proxyConfig monitoringv1.ProxyConfig
+		expected    string
+	}{
+		{
+			name:     "empty",
+			version:  "v2.43.0",
+			expected: "{}\n",
+		},
+		{
+			name:    "proxy url",
+			version: "v2.43.0",
+			proxyConfig: monitoringv1.ProxyConfig{
+				ProxyURL:           pointer.StringPtr("http://proxy:8080"),
+				NoProxy:            pointer.StringPtr("10.0.0.0/8,example.com"),
+				ProxyConnectHeader: proxyConnectHeader,
+			},
+			expected: `proxy_url: http://proxy:8080
+no_proxy: 10.0.0.0/8,example.com
+proxy_connect_header:
+  Proxy-Authorization:
+  - secret
+  X-Foo:
+  - foo
+`,
+		},
+		{
+			name:    "proxy from environment",
+			version: "v2.43.0",
+			proxyConfig: monitoringv1.ProxyConfig{
+				ProxyFromEnvironment: pointer.BoolPtr(true),
+			},
+			expected: `proxy_from_environment: true
+`,
+		},
+		{
+			name:    "unsupported Prometheus version",
+			version: "v2.42.0",
+			proxyConfig: monitoringv1.ProxyConfig{
+				ProxyURL:           pointer.StringPtr("http://proxy:8080"),
+				NoProxy:            pointer.StringPtr("10.0.0.0/8,example.com"),
+				ProxyConnectHeader: proxyConnectHeader,
+			},
+			expected: `proxy_url: http://proxy:8080
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := mustNewConfigGenerator(
+				t,
+				&monitoringv1.Prometheus{
+					Spec: monitoringv1.PrometheusSpec{
+						CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+							Version: tc.version,
+						},
+					},
+				},
+			)
+
+			cfg := cg.addProxyConfigToYaml(yaml.MapSlice{}, "serviceMonitor/default/test/0", store, tc.proxyConfig)
+			b, err := yaml.Marshal(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.expected != string(b) {
+				t.Logf("\n%s", pretty.Compare(tc.expected, string(b)))
+				t.Fatal("expected proxy configuration doesn't match with actual configuration")
+			}
+		})
+	}
+}
+
+func TestScrapeProtocols(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected []string
+	}{
+		{
+			name:    "supported Prometheus version",
+			version: "v2.49.0",
+			expected: []string{
+				`global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+  scrape_protocols:
+  - PrometheusProto
+  - OpenMetricsText1.0.0
+`,
+				`  scrape_protocols:
+  - PrometheusText0.0.4
+`,
+			},
+		},
+		{
+			name:    "unsupported Prometheus version",
+			version: "v2.48.0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+						ScrapeProtocols: []monitoringv1.ScrapeProtocol{
+							monitoringv1.PrometheusProto,
+							monitoringv1.OpenMetricsText1_0_0,
+						},
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(
+				p,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testservicemonitor1",
+							Namespace: "default",
+						},
+						Spec: monitoringv1.ServiceMonitorSpec{
+							Endpoints: []monitoringv1.Endpoint{
+								{
+									Port:            "web",
+									ScrapeProtocols: []monitoringv1.ScrapeProtocol{monitoringv1.PrometheusText0_0_4},
+								},
+							},
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tc.expected) == 0 && strings.Contains(string(cfg), "scrape_protocols") {
+				t.Fatalf("expected no scrape_protocols, got:\n%s", cfg)
+			}
+
+			for _, expected := range tc.expected {
+				if !strings.Contains(string(cfg), expected) {
+					t.Fatalf("expected config to contain:\n%s\ngot:\n%s", expected, cfg)
+				}
+			}
+		})
+	}
+}
+
+func TestScrapeFallbackProtocol(t *testing.T) {
+	global := monitoringv1.PrometheusText0_0_4
+	override := monitoringv1.OpenMetricsText1_0_0
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected []string
+	}{
+		{
+			name:    "supported Prometheus version",
+			version: "v3.0.0",
+			expected: []string{
+				"  fallback_scrape_protocol: PrometheusText0.0.4\n",
+				"  fallback_scrape_protocol: OpenMetricsText1.0.0\n",
+			},
+		},
+		{
+			name:    "unsupported Prometheus version",
+			version: "v2.55.0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version:                tc.version,
+						ScrapeFallbackProtocol: &global,
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(
+				p,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testservicemonitor1",
+							Namespace: "default",
+						},
+						Spec: monitoringv1.ServiceMonitorSpec{
+							Endpoints: []monitoringv1.Endpoint{
+								{Port: "web"},
+							},
+						},
+					},
+				},
+				map[string]*monitoringv1.PodMonitor{
+					"testpodmonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testpodmonitor1",
+							Namespace: "default",
+						},
+						Spec: monitoringv1.PodMonitorSpec{
+							PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
+								{Port: "web", ScrapeFallbackProtocol: &override},
+							},
+						},
+					},
+				},
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tc.expected) == 0 && strings.Contains(string(cfg), "fallback_scrape_protocol") {
+				t.Fatalf("expected no fallback_scrape_protocol, got:\n%s", cfg)
+			}
+
+			for _, expected := range tc.expected {
+				if !strings.Contains(string(cfg), expected) {
+					t.Fatalf("expected config to contain:\n%s\ngot:\n%s", expected, cfg)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertClassicHistogramsToNHCB(t *testing.T) {
+	boolTrue, boolFalse := true, false
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected []string
+	}{
+		{
+			name:    "supported Prometheus version",
+			version: "v3.0.0",
+			expected: []string{
+				"  convert_classic_histograms_to_nhcb: true\n",
+				"  convert_classic_histograms_to_nhcb: false\n",
+			},
+		},
+		{
+			name:    "unsupported Prometheus version",
+			version: "v2.55.0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version:                        tc.version,
+						ConvertClassicHistogramsToNHCB: &boolTrue,
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(
+				p,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testservicemonitor1",
+							Namespace: "default",
+						},
+						Spec: monitoringv1.ServiceMonitorSpec{
+							Endpoints: []monitoringv1.Endpoint{
+								{Port: "web"},
+							},
+						},
+					},
+				},
+				map[string]*monitoringv1.PodMonitor{
+					"testpodmonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testpodmonitor1",
+							Namespace: "default",
+						},
+						Spec: monitoringv1.PodMonitorSpec{
+							PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
+								{Port: "web", ConvertClassicHistogramsToNHCB: &boolFalse},
+							},
+						},
+					},
+				},
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tc.expected) == 0 && strings.Contains(string(cfg), "convert_classic_histograms_to_nhcb") {
+				t.Fatalf("expected no convert_classic_histograms_to_nhcb, got:\n%s", cfg)
+			}
+
+			for _, expected := range tc.expected {
+				if !strings.Contains(string(cfg), expected) {
+					t.Fatalf("expected config to contain:\n%s\ngot:\n%s", expected, cfg)
+				}
+			}
+		})
+	}
+}
+
+func TestBearerTokenProjected(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+
+	pt := &monitoringv1.ProjectedToken{Audience: "oidc-proxy"}
+
+	cg := mustNewConfigGenerator(t, p)
+	cfg, err := cg.Generate(
+		p,
+		map[string]*monitoringv1.ServiceMonitor{
+			"testservicemonitor1": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testservicemonitor1",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					Endpoints: []monitoringv1.Endpoint{
+						{
+							Port:                 "web",
+							BearerTokenProjected: pt,
+						},
+					},
+				},
+			},
+		},
+		nil,
+		nil,
+		nil,
+		&assets.Store{},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "bearer_token_file: /etc/prometheus/projected-tokens/" + assets.ProjectedTokenKey(pt) + "\n"
+	if !strings.Contains(string(cfg), expected) {
+		t.Fatalf("expected %q, got:\n%s", expected, cfg)
+	}
+}
+
+func TestRuleQueryOffset(t *testing.T) {
+	offset := monitoringv1.Duration("10s")
+
+	for _, tc := range []struct {
+		version  string
+		expected bool
+	}{
+		{version: "v2.52.0", expected: false},
+		{version: "v2.53.0", expected: true},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+					},
+					RuleQueryOffset: &offset,
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(p, nil, nil, nil, nil, &assets.Store{}, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := strings.Contains(string(cfg), "  rule_query_offset: 10s\n"); got != tc.expected {
+				t.Fatalf("expected rule_query_offset: %v, got:\n%s", tc.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestRuntimeConfig(t *testing.T) {
+	goGC := func(i int32) *int32 { return &i }
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		runtime  *monitoringv1.RuntimeConfig
+		expected bool
+		err      bool
+	}{
+		{name: "no runtime config", version: "v2.53.0"},
+		{name: "empty runtime config", version: "v2.53.0", runtime: &monitoringv1.RuntimeConfig{}},
+		{name: "unsupported version", version: "v2.52.0", runtime: &monitoringv1.RuntimeConfig{GoGC: goGC(50)}},
+		{name: "supported version", version: "v2.53.0", runtime: &monitoringv1.RuntimeConfig{GoGC: goGC(50)}, expected: true},
+		{name: "invalid goGC", version: "v2.53.0", runtime: &monitoringv1.RuntimeConfig{GoGC: goGC(0)}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+						Runtime: tc.runtime,
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(p, nil, nil, nil, nil, &assets.Store{}, nil, nil, nil, nil)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := strings.Contains(string(cfg), "runtime:\n  gogc: 50\n"); got != tc.expected {
+				t.Fatalf("expected runtime config: %v, got:\n%s", tc.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestServiceDiscoveryRole(t *testing.T) {
+	endpoints := monitoringv1.EndpointsRole
+	endpointSlice := monitoringv1.EndpointSliceRole
+
+	for _, tc := range []struct {
+		name                   string
+		role                   *monitoringv1.ServiceDiscoveryRole
+		version                string
+		endpointSliceSupported bool
+		expected               string
+	}{
+		{
+			name:                   "default with endpointslice support",
+			endpointSliceSupported: true,
+			expected:               "endpointslice",
+		},
+		{
+			name:     "default without endpointslice support",
+			expected: "endpoints",
+		},
+		{
+			name:                   "endpoints role",
+			role:                   &endpoints,
+			endpointSliceSupported: true,
+			expected:               "endpoints",
+		},
+		{
+			name:                   "endpointslice role",
+			role:                   &endpointSlice,
+			endpointSliceSupported: true,
+			expected:               "endpointslice",
+		},
+		{
+			name:                   "endpointslice role with unsupported Prometheus version",
+			role:                   &endpointSlice,
+			version:                "v2.20.0",
+			endpointSliceSupported: true,
+			expected:               "endpoints",
+		},
+		{
+			name:     "endpointslice role without endpointslice support",
+			role:     &endpointSlice,
+			expected: "endpoints",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version:              tc.version,
+						ServiceDiscoveryRole: tc.role,
+					},
+				},
+			}
+
+			logger := level.NewFilter(log.NewLogfmtLogger(os.Stderr), level.AllowWarn())
+			cg, err := NewConfigGenerator(log.With(logger, "test", t.Name()), p, tc.endpointSliceSupported)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := cg.Generate(
+				p,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testservicemonitor1",
+							Namespace: "default",
+						},
+						Spec: monitoringv1.ServiceMonitorSpec{
+							Endpoints: []monitoringv1.Endpoint{{Port: "web"}},
+						},
+					},
+				},
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := "- role: " + tc.expected + "\n"; !strings.Contains(string(cfg), expected) {
+				t.Fatalf("expected %q, got:\n%s", expected, cfg)
+			}
+		})
+	}
+}
+
+func TestShardingLabels(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		shardingLabels []monitoringv1.LabelName
+		expected       string
+	}{
+		{
+			name: "default",
+			expected: `  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 2
+    action: hashmod
+`,
+		},
+		{
+			name:           "custom labels",
+			shardingLabels: []monitoringv1.LabelName{"__meta_kubernetes_namespace", "__meta_kubernetes_pod_name"},
+			expected: `  - source_labels:
+    - __meta_kubernetes_namespace
+    - __meta_kubernetes_pod_name
+    target_label: __tmp_hash
+    modulus: 2
+    action: hashmod
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Shards:         pointer.Int32Ptr(2),
+						ShardingLabels: tc.shardingLabels,
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(
+				p,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testservicemonitor1",
+							Namespace: "default",
+						},
+						Spec: monitoringv1.ServiceMonitorSpec{
+							Endpoints: []monitoringv1.Endpoint{{Port: "web"}},
+						},
+					},
+				},
+				map[string]*monitoringv1.PodMonitor{
+					"testpodmonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testpodmonitor1",
+							Namespace: "default",
+						},
+						Spec: monitoringv1.PodMonitorSpec{
+							PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{{Port: "web"}},
+						},
+					},
+				},
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if n := strings.Count(string(cfg), tc.expected); n != 2 {
+				t.Fatalf("expected sharding relabeling %q twice, found %d times:\n%s", tc.expected, n, cfg)
+			}
+		})
+	}
+}
+
+func TestRemoteWriteEnabled(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	p.Spec.RemoteWrite = []monitoringv1.RemoteWriteSpec{
+		{
+			URL:  "http://example.com/0",
+			Name: "first",
+		},
+		{
+			URL:     "http://example.com/1",
+			Name:    "second",
+			Enabled: pointer.BoolPtr(false),
+		},
+		{
+			URL:     "http://example.com/2",
+			Name:    "third",
+			Enabled: pointer.BoolPtr(true),
+			BasicAuth: &monitoringv1.BasicAuth{
+				Username: v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "auth"},
+					Key:                  "username",
+				},
+				Password: v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "auth"},
+					Key:                  "password",
+				},
+			},
+		},
+	}
+
+	store := &assets.Store{
+		BasicAuthAssets: map[string]assets.BasicAuthCredentials{
+			"remoteWrite/2": {
+				Username: "foo",
+				Password: "bar",
+			},
+		},
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+	cfg, err := cg.Generate(p, nil, nil, nil, nil, store, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com/0
+  remote_timeout: 30s
+  name: first
+- url: http://example.com/2
+  remote_timeout: 30s
+  name: third
+  basic_auth:
+    username: foo
+    password: bar
+`
+	if diff := cmp.Diff(expected, string(cfg)); diff != "" {
+		t.Logf("\n%s", diff)
+		t.Fatal("expected Prometheus configuration and actual configuration do not match")
+	}
+}
+
+func TestRemoteWriteURLs(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	p.Spec.RemoteWrite = []monitoringv1.RemoteWriteSpec{
+		{
+			URLs:          []string{"http://example.com/0", "http://example.com/1"},
+			Name:          "ingest",
+			RemoteTimeout: "10s",
+		},
+		{
+			URL: "http://example.com/2",
+		},
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+	cfg, err := cg.Generate(p, nil, nil, nil, nil, &assets.Store{}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: http://example.com/0
+  remote_timeout: 10s
+  name: ingest-0
+- url: http://example.com/1
+  remote_timeout: 10s
+  name: ingest-1
+- url: http://example.com/2
+  remote_timeout: 30s
+`
+	if diff := cmp.Diff(expected, string(cfg)); diff != "" {
+		t.Logf("\n%s", diff)
+		t.Fatal("expected Prometheus configuration and actual configuration do not match")
+	}
+}
+
+func TestTLSConfigMinMaxVersion(t *testing.T) {
+	tls12, tls13 := monitoringv1.TLSVersion12, monitoringv1.TLSVersion13
+
+	for _, tc := range []struct {
+		version  string
+		expected string
+	}{
+		{
+			version: "v2.34.0",
+			expected: `  tls_config:
+    insecure_skip_verify: false
+`,
+		},
+		{
+			version: "v2.35.0",
+			expected: `  tls_config:
+    insecure_skip_verify: false
+    min_version: TLS12
+`,
+		},
+		{
+			version: "v2.41.0",
+			expected: `  tls_config:
+    insecure_skip_verify: false
+    min_version: TLS12
+    max_version: TLS13
+`,
+		},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+			}
+			p.Spec.Version = tc.version
+			p.Spec.RemoteWrite = []monitoringv1.RemoteWriteSpec{
+				{
+					URL: "https://example.com",
+					TLSConfig: &monitoringv1.TLSConfig{
+						SafeTLSConfig: monitoringv1.SafeTLSConfig{
+							MinVersion: &tls12,
+							MaxVersion: &tls13,
+						},
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(p, nil, nil, nil, nil, &assets.Store{}, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+remote_write:
+- url: https://example.com
+  remote_timeout: 30s
+` + tc.expected
+			if diff := cmp.Diff(expected, string(cfg)); diff != "" {
+				t.Logf("\n%s", diff)
+				t.Fatal("expected Prometheus configuration and actual configuration do not match")
+			}
+		})
+	}
+}
+
+func TestAlertmanagerBearerTokenSecret(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			Alerting: &monitoringv1.AlertingSpec{
+				Alertmanagers: []monitoringv1.AlertmanagerEndpoints{
+					{
+						Name:      "alertmanager-main",
+						Namespace: "default",
+						Port:      intstr.FromString("web"),
+						BearerTokenSecret: &v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "alertmanager-token"},
+							Key:                  "token",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	store := &assets.Store{
+		TokenAssets: map[string]assets.Token{
+			"alertmanager/0": assets.Token("secret-token"),
+		},
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+	cfg, err := cg.Generate(p, nil, nil, nil, nil, store, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers:
+  - path_prefix: /
+    scheme: http
+    kubernetes_sd_configs:
+    - role: endpoints
+      namespaces:
+        names:
+        - default
+    bearer_token: secret-token
+    relabel_configs:
+    - action: keep
+      source_labels:
+      - __meta_kubernetes_service_name
+      regex: alertmanager-main
+    - action: keep
+      source_labels:
+      - __meta_kubernetes_endpoint_port_name
+      regex: web
+`
+	if diff := cmp.Diff(expected, string(cfg)); diff != "" {
+		t.Logf("\n%s", diff)
+		t.Fatal("expected Prometheus configuration and actual configuration do not match")
+	}
+}
+
+func TestScrapeConfigServiceDiscovery(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				ScrapeConfigSelector: &metav1.LabelSelector{},
+				Version:              operator.DefaultPrometheusVersion,
+			},
+		},
+	}
+
+	secretKey := func(name, key string) v1.SecretKeySelector {
+		return v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	}
+	refreshInterval := monitoringv1.Duration("1m")
+
+	cg := mustNewConfigGenerator(t, p)
+	cfg, err := cg.Generate(
+		p,
+		nil,
+		nil,
+		nil,
+		map[string]*monitoringv1alpha1.ScrapeConfig{
+			"ns1/consul": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "consul",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.ScrapeConfigSpec{
+					ConsulSDConfigs: []monitoringv1alpha1.ConsulSDConfig{
+						{
+							Server:          "consul.example.com:8500",
+							TokenRef:        func() *v1.SecretKeySelector { s := secretKey("consul", "token"); return &s }(),
+							Datacenter:      pointer.String("dc1"),
+							Scheme:          pointer.String("HTTPS"),
+							Services:        []string{"web", "db"},
+							Tags:            []string{"prod"},
+							NodeMeta:        map[string]string{"rack": "r1"},
+							AllowStale:      pointer.Bool(true),
+							RefreshInterval: &refreshInterval,
+							BasicAuth: &monitoringv1.BasicAuth{
+								Username: secretKey("consul", "username"),
+								Password: secretKey("consul", "password"),
+							},
+							TLSConfig: &monitoringv1.SafeTLSConfig{
+								CA: monitoringv1.SecretOrConfigMap{
+									Secret: func() *v1.SecretKeySelector { s := secretKey("consul", "ca"); return &s }(),
+								},
+							},
+						},
+					},
+					RelabelConfigs: []*monitoringv1.RelabelConfig{
+						{
+							SourceLabels: []monitoringv1.LabelName{"__meta_consul_service"},
+							TargetLabel:  "service",
+						},
+					},
+				},
+			},
+			"ns1/kubernetes": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubernetes",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.ScrapeConfigSpec{
+					KubernetesSDConfigs: []monitoringv1alpha1.KubernetesSDConfig{
+						{
+							Role: monitoringv1alpha1.KubernetesRoleEndpointSlice,
+							Selectors: []monitoringv1alpha1.K8SSelectorConfig{
+								{
+									Role:  monitoringv1alpha1.KubernetesRolePod,
+									Label: "app=foo",
+								},
+							},
+						},
+						{
+							Role: monitoringv1alpha1.KubernetesRoleNode,
+							NamespaceSelector: monitoringv1.NamespaceSelector{
+								Any: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		&assets.Store{
+			BasicAuthAssets: map[string]assets.BasicAuthCredentials{
+				"scrapeconfig/ns1/consul/consulsdconfig/0": {
+					Username: "consul-user",
+					Password: "consul-password",
+				},
+			},
+			OAuth2Assets: map[string]assets.OAuth2Credentials{},
+			TokenAssets: map[string]assets.Token{
+				"scrapeconfig/token/ns1/consul/consulsdconfig/0": "consul-token",
+			},
+		},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+scrape_configs:
+- job_name: scrapeConfig/ns1/consul
+  consul_sd_configs:
+  - server: consul.example.com:8500
+    token: consul-token
+    datacenter: dc1
+    scheme: https
+    services:
+    - web
+    - db
+    tags:
+    - prod
+    node_meta:
+      rack: r1
+    allow_stale: true
+    refresh_interval: 1m
+    basic_auth:
+      username: consul-user
+      password: consul-password
+    tls_config:
+      insecure_skip_verify: false
+      ca_file: /etc/prometheus/certs/secret_ns1_consul_ca
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - source_labels:
+    - __meta_consul_service
+    target_label: service
+  metric_relabel_configs: []
+- job_name: scrapeConfig/ns1/kubernetes
+  kubernetes_sd_configs:
+  - role: endpointslice
+    namespaces:
+      names:
+      - ns1
+    selectors:
+    - role: pod
+      label: app=foo
+  - role: node
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  metric_relabel_configs: []
+`
+
+	if diff := cmp.Diff(expected, string(cfg)); diff != "" {
+		t.Fatalf("Unexpected result got(-) want(+)\n%s\n", diff)
+	}
+}
+
+func TestScrapeConfigServiceDiscoveryVersions(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected map[string]bool
+	}{
+		{
+			name:    "v2.20.0",
+			version: "v2.20.0",
+			expected: map[string]bool{
+				"role: endpointslice": false,
+				"role: node":          true,
+				"partition: p1":       false,
+			},
+		},
+		{
+			name:    "v2.40.0",
+			version: "v2.40.0",
+			expected: map[string]bool{
+				"role: endpointslice": true,
+				"role: node":          true,
+				"partition: p1":       false,
+			},
+		},
+		{
+			name:    "v2.41.0",
+			version: "v2.41.0",
+			expected: map[string]bool{
+				"role: endpointslice": true,
+				"role: node":          true,
+				"partition: p1":       true,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						ScrapeConfigSelector: &metav1.LabelSelector{},
+						Version:              tc.version,
+					},
+				},
+			}
+
+			cg := mustNewConfigGenerator(t, p)
+			cfg, err := cg.Generate(
+				p,
+				nil,
+				nil,
+				nil,
+				map[string]*monitoringv1alpha1.ScrapeConfig{
+					"ns1/sd": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "sd",
+							Namespace: "ns1",
+						},
+						Spec: monitoringv1alpha1.ScrapeConfigSpec{
+							ConsulSDConfigs: []monitoringv1alpha1.ConsulSDConfig{
+								{
+									Server:    "consul.example.com:8500",
+									Partition: pointer.String("p1"),
+								},
+							},
+							KubernetesSDConfigs: []monitoringv1alpha1.KubernetesSDConfig{
+								{Role: monitoringv1alpha1.KubernetesRoleEndpointSlice},
+								{Role: monitoringv1alpha1.KubernetesRoleNode},
+							},
+						},
+					},
+				},
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for s, expected := range tc.expected {
+				if got := strings.Contains(string(cfg), s); got != expected {
+					t.Fatalf("expected %q to be rendered: %v, got:\n%s", s, expected, cfg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v2"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// ApplyRelabelConfigs evaluates the relabeling rules against the given label
+// set without running Prometheus. The rules are rendered the same way as in
+// the generated configuration and then processed by the Prometheus relabeling
+// engine, so defaults and validation match what Prometheus would apply.
+//
+// It returns the resulting label set and true if the sample is kept, or nil
+// and false if it is dropped.
+func ApplyRelabelConfigs(rcs []*monitoringv1.RelabelConfig, sample map[string]string) (map[string]string, bool, error) {
+	b, err := yaml.Marshal(generateRelabelConfig(rcs))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to marshal relabel configs")
+	}
+
+	var cfgs []*relabel.Config
+	if err := yaml.Unmarshal(b, &cfgs); err != nil {
+		return nil, false, errors.Wrap(err, "invalid relabel configs")
+	}
+
+	lset := relabel.Process(labels.FromMap(sample), cfgs...)
+	if lset == nil {
+		return nil, false, nil
+	}
+
+	return lset.Map(), true, nil
+}
+
+// PreviewRemoteWriteRelabeling evaluates the write relabel configs of the
+// remote write spec against the given sample. See ApplyRelabelConfigs.
+func PreviewRemoteWriteRelabeling(spec *monitoringv1.RemoteWriteSpec, sample map[string]string) (map[string]string, bool, error) {
+	rcs := make([]*monitoringv1.RelabelConfig, 0, len(spec.WriteRelabelConfigs))
+	for i := range spec.WriteRelabelConfigs {
+		rcs = append(rcs, &spec.WriteRelabelConfigs[i])
+	}
+
+	return ApplyRelabelConfigs(rcs, sample)
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"reflect"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestPreviewRemoteWriteRelabeling(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		rcs      []monitoringv1.RelabelConfig
+		sample   map[string]string
+		expected map[string]string
+		kept     bool
+		err      bool
+	}{
+		{
+			name:     "no relabel configs",
+			sample:   map[string]string{"__name__": "up", "job": "node"},
+			expected: map[string]string{"__name__": "up", "job": "node"},
+			kept:     true,
+		},
+		{
+			name: "drop matching series",
+			rcs: []monitoringv1.RelabelConfig{
+				{SourceLabels: []monitoringv1.LabelName{"__name__"}, Regex: "go_.*", Action: "drop"},
+			},
+			sample: map[string]string{"__name__": "go_goroutines", "job": "node"},
+			kept:   false,
+		},
+		{
+			name: "keep non matching series",
+			rcs: []monitoringv1.RelabelConfig{
+				{SourceLabels: []monitoringv1.LabelName{"__name__"}, Regex: "go_.*", Action: "drop"},
+			},
+			sample:   map[string]string{"__name__": "up", "job": "node"},
+			expected: map[string]string{"__name__": "up", "job": "node"},
+			kept:     true,
+		},
+		{
+			name: "default replace action",
+			rcs: []monitoringv1.RelabelConfig{
+				{SourceLabels: []monitoringv1.LabelName{"job"}, TargetLabel: "service"},
+			},
+			sample:   map[string]string{"__name__": "up", "job": "node"},
+			expected: map[string]string{"__name__": "up", "job": "node", "service": "node"},
+			kept:     true,
+		},
+		{
+			name: "uppercase action is normalized",
+			rcs: []monitoringv1.RelabelConfig{
+				{Regex: "job", Action: "LabelDrop"},
+			},
+			sample:   map[string]string{"__name__": "up", "job": "node"},
+			expected: map[string]string{"__name__": "up"},
+			kept:     true,
+		},
+		{
+			name: "invalid regex",
+			rcs: []monitoringv1.RelabelConfig{
+				{SourceLabels: []monitoringv1.LabelName{"__name__"}, Regex: "(", Action: "drop"},
+			},
+			sample: map[string]string{"__name__": "up"},
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &monitoringv1.RemoteWriteSpec{WriteRelabelConfigs: tc.rcs}
+
+			lset, kept, err := PreviewRemoteWriteRelabeling(spec, tc.sample)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if kept != tc.kept {
+				t.Fatalf("expected kept to be %v, got %v", tc.kept, kept)
+			}
+			if !reflect.DeepEqual(lset, tc.expected) {
+				t.Fatalf("expected labels %v, got %v", tc.expected, lset)
+			}
+		})
+	}
+}
@@ -22,15 +22,18 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/ghodss/yaml"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/pkg/errors"
 	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"github.com/prometheus/prometheus/model/rulefmt"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	namespacelabeler "github.com/prometheus-operator/prometheus-operator/pkg/namespace-labeler"
@@ -169,6 +172,12 @@ func (c *Operator) selectRuleNamespaces(p *monitoringv1.Prometheus) ([]string, e
 func (c *Operator) selectRules(p *monitoringv1.Prometheus, namespaces []string) (map[string]string, error) {
 	rules := map[string]string{}
 
+	promVersion := operator.StringValOrDefault(p.Spec.Version, operator.DefaultPrometheusVersion)
+	version, err := semver.ParseTolerant(promVersion)
+	if err != nil {
+		return rules, errors.Wrap(err, "failed to parse Prometheus version")
+	}
+
 	ruleSelector, err := metav1.LabelSelectorAsSelector(p.Spec.RuleSelector)
 	if err != nil {
 		return rules, errors.Wrap(err, "convert rule label selector to selector")
@@ -205,11 +214,18 @@ func (c *Operator) selectRules(p *monitoringv1.Prometheus, namespaces []string)
 				return
 			}
 
-			content, err := GenerateContent(promRule.Spec, c.logger)
+			logger := log.With(c.logger, "namespace", promRule.Namespace, "prometheusrule", promRule.Name)
+			dropUnsupportedRuleFields(&promRule.Spec, version, logger)
+
+			content, err := GenerateContent(promRule.Spec, logger)
 			if err != nil {
 				marshalErr = err
 				return
 			}
+
+			if err := promRule.Spec.ValidateIntervals(p.Spec.EvaluationInterval, p.Spec.ScrapeInterval); monitoringv1.IsValidationWarning(err) {
+				level.Warn(logger).Log("msg", "rule groups evaluated more often than the targets are scraped", "warning", err.Error())
+			}
 			rules[fmt.Sprintf("%v-%v-%v.yaml", promRule.Namespace, promRule.Name, promRule.UID)] = content
 		})
 		if err != nil {
@@ -333,6 +349,17 @@ func prometheusRuleConfigMapName(prometheusName string) string {
 
 // GenerateContent takes a PrometheusRuleSpec and generates the rule content
 func GenerateContent(promRule monitoringv1.PrometheusRuleSpec, logger log.Logger) (string, error) {
+	promRule = *promRule.DeepCopy()
+	// The unit tests aren't part of the Prometheus rule file format.
+	promRule.Tests = nil
+	for i := range promRule.Groups {
+		for j := range promRule.Groups[i].Rules {
+			r := &promRule.Groups[i].Rules[j]
+			// Always render the expression as a string.
+			r.Expr = intstr.FromString(r.Expression())
+		}
+	}
+
 	content, err := yaml.Marshal(promRule)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to marshal content")
@@ -346,11 +373,92 @@ func GenerateContent(promRule monitoringv1.PrometheusRuleSpec, logger log.Logger
 		}
 		return "", errors.New(m)
 	}
+
+	if dups := promRule.DuplicateAlertNames(); len(dups) > 0 {
+		level.Warn(logger).Log("msg", "alerting rules defined in more than one group", "alerts", strings.Join(dups, ","))
+	}
+
 	return string(content), nil
 }
 
-// ValidateRule takes PrometheusRuleSpec and validates it using the upstream prometheus rule validator
+// ruleFieldsVersions defines the minimum versions of a component evaluating
+// rules which support the optional rule fields.
+type ruleFieldsVersions struct {
+	component     string
+	limit         string
+	keepFiringFor string
+	queryOffset   string
+}
+
+var (
+	prometheusRuleFieldsVersions = ruleFieldsVersions{
+		component:     "Prometheus",
+		limit:         "2.31.0",
+		keepFiringFor: "2.42.0",
+		queryOffset:   "2.53.0",
+	}
+	thanosRuleFieldsVersions = ruleFieldsVersions{
+		component:     "Thanos",
+		limit:         "0.24.0",
+		keepFiringFor: "0.34.0",
+		queryOffset:   "0.38.0",
+	}
+)
+
+// dropUnsupportedRuleFields removes the rule fields which aren't supported by
+// the given Prometheus version.
+func dropUnsupportedRuleFields(promRule *monitoringv1.PrometheusRuleSpec, version semver.Version, logger log.Logger) {
+	dropRuleFields(promRule, version, prometheusRuleFieldsVersions, logger)
+}
+
+// DropUnsupportedThanosRuleFields removes the rule fields which aren't
+// supported by the given Thanos version.
+func DropUnsupportedThanosRuleFields(promRule *monitoringv1.PrometheusRuleSpec, version semver.Version, logger log.Logger) {
+	dropRuleFields(promRule, version, thanosRuleFieldsVersions, logger)
+}
+
+func dropRuleFields(promRule *monitoringv1.PrometheusRuleSpec, version semver.Version, fv ruleFieldsVersions, logger log.Logger) {
+	for i := range promRule.Groups {
+		g := &promRule.Groups[i]
+
+		if g.Limit != nil && version.LT(semver.MustParse(fv.limit)) {
+			level.Warn(logger).Log("msg", fmt.Sprintf("ignoring 'limit' not supported by %s", fv.component), "version", version, "minimum_version", fv.limit, "group", g.Name)
+			g.Limit = nil
+		}
+
+		if g.QueryOffset != nil && version.LT(semver.MustParse(fv.queryOffset)) {
+			level.Warn(logger).Log("msg", fmt.Sprintf("ignoring 'query_offset' not supported by %s", fv.component), "version", version, "minimum_version", fv.queryOffset, "group", g.Name)
+			g.QueryOffset = nil
+		}
+
+		for j := range g.Rules {
+			r := &g.Rules[j]
+			if r.KeepFiringFor == nil || version.GTE(semver.MustParse(fv.keepFiringFor)) {
+				continue
+			}
+
+			level.Warn(logger).Log("msg", fmt.Sprintf("ignoring 'keep_firing_for' not supported by %s", fv.component), "version", version, "minimum_version", fv.keepFiringFor, "rule", r.Alert)
+			r.KeepFiringFor = nil
+		}
+	}
+}
+
+// ValidateRule takes PrometheusRuleSpec and validates it semantically first,
+// then using the upstream prometheus rule validator.
 func ValidateRule(promRule monitoringv1.PrometheusRuleSpec) []error {
+	if err := promRule.Validate(); err != nil {
+		var verr *monitoringv1.PrometheusRuleValidationError
+		if !errors.As(err, &verr) {
+			return []error{err}
+		}
+
+		errs := make([]error, 0, len(verr.Errors()))
+		for _, e := range verr.Errors() {
+			errs = append(errs, e)
+		}
+		return errs
+	}
+
 	for i, group := range promRule.Groups {
 		if group.PartialResponseStrategy == "" {
 			continue
@@ -366,6 +474,18 @@ func ValidateRule(promRule monitoringv1.PrometheusRuleSpec) []error {
 		// is not aware of the partial_response_strategy field
 		promRule.Groups[i].PartialResponseStrategy = ""
 	}
+
+	// The unit tests aren't part of the Prometheus rule file format.
+	promRule.Tests = nil
+
+	for i := range promRule.Groups {
+		// reset these as the vendored prometheus rule validator
+		// is not aware of the query_offset and keep_firing_for fields
+		promRule.Groups[i].QueryOffset = nil
+		for j := range promRule.Groups[i].Rules {
+			promRule.Groups[i].Rules[j].KeepFiringFor = nil
+		}
+	}
 	content, err := yaml.Marshal(promRule)
 	if err != nil {
 		return []error{errors.Wrap(err, "failed to marshal content")}
@@ -19,6 +19,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/go-kit/log"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -35,6 +36,7 @@ func TestMakeRulesConfigMaps(t *testing.T) {
 	t.Run("shouldRejectRuleWithInvalidLabels", shouldRejectRuleWithInvalidLabels)
 	t.Run("shouldRejectRuleWithInvalidExpression", shouldRejectRuleWithInvalidExpression)
 	t.Run("shouldRejectRuleWithInvalidPartialResponseStrategyValue", shouldRejectRuleWithInvalidPartialResponseStrategyValue)
+	t.Run("shouldRenderIntegerExpressionAsString", shouldRenderIntegerExpressionAsString)
 
 }
 
@@ -120,6 +122,30 @@ func shouldRejectRuleWithInvalidExpression(t *testing.T) {
 	}
 }
 
+func shouldRenderIntegerExpressionAsString(t *testing.T) {
+	rules := monitoringv1.PrometheusRuleSpec{Groups: []monitoringv1.RuleGroup{
+		{
+			Name: "group",
+			Rules: []monitoringv1.Rule{
+				{
+					Record: "one",
+					Expr:   intstr.FromInt(1),
+				},
+			},
+		},
+	}}
+	content, err := GenerateContent(rules, log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout)))
+	if err != nil {
+		t.Fatalf("expected no errors when parsing rule with integer expression, got %v", err)
+	}
+	if !strings.Contains(content, `expr: "1"`) {
+		t.Fatalf("expected integer expression to be rendered as a string, got:\n%s", content)
+	}
+	if rules.Groups[0].Rules[0].Expr.Type != intstr.Int {
+		t.Fatalf("expected the input rules not to be modified")
+	}
+}
+
 func shouldRejectRuleWithInvalidPartialResponseStrategyValue(t *testing.T) {
 	rules := monitoringv1.PrometheusRuleSpec{Groups: []monitoringv1.RuleGroup{
 		{
@@ -190,3 +216,277 @@ func shouldSplitUpLargeSmallIntoTwo(t *testing.T) {
 		t.Fatal("expected ConfigMap data to match rule file content")
 	}
 }
+
+func TestKeepFiringFor(t *testing.T) {
+	keepFiringFor := monitoringv1.Duration("10m")
+
+	for _, tc := range []struct {
+		version  string
+		expected bool
+	}{
+		{version: "v2.41.0", expected: false},
+		{version: "v2.42.0", expected: true},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			spec := monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{
+						Name: "group",
+						Rules: []monitoringv1.Rule{
+							{
+								Alert:         "Down",
+								Expr:          intstr.FromString("up == 0"),
+								KeepFiringFor: &keepFiringFor,
+							},
+						},
+					},
+				},
+			}
+
+			dropUnsupportedRuleFields(&spec, semver.MustParse(strings.TrimPrefix(tc.version, "v")), log.NewNopLogger())
+
+			content, err := GenerateContent(spec, log.NewNopLogger())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got := strings.Contains(content, "keep_firing_for: 10m"); got != tc.expected {
+				t.Fatalf("expected keep_firing_for to be rendered: %v, got content:\n%s", tc.expected, content)
+			}
+		})
+	}
+}
+
+func TestRuleGroupLimitAndQueryOffset(t *testing.T) {
+	limit := int32(1000)
+	queryOffset := monitoringv1.Duration("1m")
+
+	for _, tc := range []struct {
+		version  string
+		expected []string
+		dropped  []string
+	}{
+		{
+			version: "2.30.0",
+			dropped: []string{"limit: 1000", "query_offset: 1m"},
+		},
+		{
+			version:  "2.31.0",
+			expected: []string{"limit: 1000"},
+			dropped:  []string{"query_offset: 1m"},
+		},
+		{
+			version:  "2.53.0",
+			expected: []string{"limit: 1000", "query_offset: 1m"},
+		},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			spec := monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{
+						Name:        "group",
+						Limit:       &limit,
+						QueryOffset: &queryOffset,
+						Rules: []monitoringv1.Rule{
+							{
+								Record: "job:up:sum",
+								Expr:   intstr.FromString("sum by (job) (up)"),
+							},
+						},
+					},
+				},
+			}
+
+			dropUnsupportedRuleFields(&spec, semver.MustParse(tc.version), log.NewNopLogger())
+
+			content, err := GenerateContent(spec, log.NewNopLogger())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, s := range tc.expected {
+				if !strings.Contains(content, s) {
+					t.Fatalf("expected %q in content:\n%s", s, content)
+				}
+			}
+			for _, s := range tc.dropped {
+				if strings.Contains(content, s) {
+					t.Fatalf("expected %q not to be in content:\n%s", s, content)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleTestsNotRendered(t *testing.T) {
+	spec := monitoringv1.PrometheusRuleSpec{
+		Groups: []monitoringv1.RuleGroup{
+			{
+				Name: "group",
+				Rules: []monitoringv1.Rule{
+					{
+						Alert: "Down",
+						Expr:  intstr.FromString("up == 0"),
+					},
+				},
+			},
+		},
+		Tests: []monitoringv1.RuleTestGroup{
+			{
+				InputSeries: []monitoringv1.RuleTestSeries{
+					{Series: `up{job="node"}`, Values: "0x10"},
+				},
+				AlertRuleTests: []monitoringv1.AlertRuleTestCase{
+					{
+						EvalTime:  "5m",
+						AlertName: "Down",
+						ExpAlerts: []monitoringv1.ExpectedAlert{
+							{ExpLabels: map[string]string{"job": "node"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if errs := ValidateRule(spec); len(errs) != 0 {
+		t.Fatalf("expected no error, got %v", errs)
+	}
+
+	content, err := GenerateContent(spec, log.NewNopLogger())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if strings.Contains(content, "tests") || strings.Contains(content, "input_series") {
+		t.Fatalf("expected the tests not to be rendered, got content:\n%s", content)
+	}
+
+	if len(spec.Tests) != 1 {
+		t.Fatalf("expected the input spec to be unchanged")
+	}
+}
+
+func TestValidateRuleInvalidExpression(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		expr string
+		err  bool
+	}{
+		{
+			name: "valid expression",
+			expr: `rate(http_requests_total[5m]) > 0`,
+		},
+		{
+			name: "unclosed parenthesis",
+			expr: `rate(http_requests_total[5m] > 0`,
+			err:  true,
+		},
+		{
+			name: "invalid range",
+			expr: `rate(http_requests_total[5x])`,
+			err:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{
+						Name: "group",
+						Rules: []monitoringv1.Rule{
+							{
+								Alert: "Alert",
+								Expr:  intstr.FromString(tc.expr),
+							},
+						},
+					},
+				},
+			}
+
+			// The API types only check that the expression isn't empty.
+			if errs := spec.Groups[0].Rules[0].Validate(); len(errs) != 0 {
+				t.Fatalf("expected no error from Rule.Validate(), got %v", errs)
+			}
+
+			errs := ValidateRule(spec)
+			if tc.err {
+				if len(errs) == 0 {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if len(errs) != 0 {
+				t.Fatalf("expected no error, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestDropUnsupportedThanosRuleFields(t *testing.T) {
+	limit := int32(1000)
+	keepFiringFor := monitoringv1.Duration("10m")
+	queryOffset := monitoringv1.Duration("1m")
+
+	for _, tc := range []struct {
+		version  string
+		expected []string
+		dropped  []string
+	}{
+		{
+			version: "0.23.0",
+			dropped: []string{"limit: 1000", "keep_firing_for: 10m", "query_offset: 1m"},
+		},
+		{
+			version:  "0.28.0",
+			expected: []string{"limit: 1000"},
+			dropped:  []string{"keep_firing_for: 10m", "query_offset: 1m"},
+		},
+		{
+			version:  "0.34.0",
+			expected: []string{"limit: 1000", "keep_firing_for: 10m"},
+			dropped:  []string{"query_offset: 1m"},
+		},
+		{
+			version:  "0.38.0",
+			expected: []string{"limit: 1000", "keep_firing_for: 10m", "query_offset: 1m"},
+		},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			spec := monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{
+						Name:        "group",
+						Limit:       &limit,
+						QueryOffset: &queryOffset,
+						Rules: []monitoringv1.Rule{
+							{
+								Alert:         "Down",
+								Expr:          intstr.FromString("up == 0"),
+								KeepFiringFor: &keepFiringFor,
+							},
+						},
+					},
+				},
+			}
+
+			DropUnsupportedThanosRuleFields(&spec, semver.MustParse(tc.version), log.NewNopLogger())
+
+			content, err := GenerateContent(spec, log.NewNopLogger())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, s := range tc.expected {
+				if !strings.Contains(content, s) {
+					t.Fatalf("expected %q in content:\n%s", s, content)
+				}
+			}
+			for _, s := range tc.dropped {
+				if strings.Contains(content, s) {
+					t.Fatalf("expected %q not to be in content:\n%s", s, content)
+				}
+			}
+		})
+	}
+}
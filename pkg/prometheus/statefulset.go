@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/prometheus-operator/prometheus-operator/pkg/webconfig"
@@ -27,6 +28,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/strings/slices"
 
 	"github.com/blang/semver/v4"
 	"github.com/go-kit/log"
@@ -47,6 +49,7 @@ const (
 	confOutDir                      = "/etc/prometheus/config_out"
 	webConfigDir                    = "/etc/prometheus/web_config"
 	tlsAssetsDir                    = "/etc/prometheus/certs"
+	projectedTokensDir              = "/etc/prometheus/projected-tokens"
 	rulesDir                        = "/etc/prometheus/rules"
 	secretsDir                      = "/etc/prometheus/secrets/"
 	configmapsDir                   = "/etc/prometheus/configmaps/"
@@ -56,6 +59,9 @@ const (
 	defaultPortName                 = "web"
 	defaultQueryLogDirectory        = "/var/log/prometheus"
 	defaultQueryLogVolume           = "query-log-file"
+	// Maximum duration for flushing the remote write queues on shutdown
+	// when remoteWriteDrainOnShutdown is enabled.
+	remoteWriteDrainFlushDeadline = 300
 )
 
 var (
@@ -66,8 +72,8 @@ var (
 	managedByOperatorLabels           = map[string]string{
 		managedByOperatorLabel: managedByOperatorLabelValue,
 	}
-	shardLabelName                = "operator.prometheus.io/shard"
-	prometheusNameLabelName       = "operator.prometheus.io/name"
+	shardLabelName                = monitoringv1.PrometheusShardLabelName
+	prometheusNameLabelName       = monitoringv1.PrometheusNameLabelName
 	probeTimeoutSeconds     int32 = 3
 )
 
@@ -104,6 +110,7 @@ func makeStatefulSet(
 	inputHash string,
 	shard int32,
 	tlsAssetSecrets []string,
+	projectedTokens map[string]monitoringv1.ProjectedToken,
 ) (*appsv1.StatefulSet, error) {
 	promVersion := operator.StringValOrDefault(p.Spec.Version, operator.DefaultPrometheusVersion)
 	parsedVersion, err := semver.ParseTolerant(promVersion)
@@ -123,7 +130,7 @@ func makeStatefulSet(
 		p.Spec.Replicas = &intZero
 	}
 
-	spec, err := makeStatefulSetSpec(logger, p, config, shard, ruleConfigMapNames, tlsAssetSecrets, parsedVersion)
+	spec, err := makeStatefulSetSpec(logger, p, config, shard, ruleConfigMapNames, tlsAssetSecrets, projectedTokens, parsedVersion)
 	if err != nil {
 		return nil, errors.Wrap(err, "make StatefulSet spec")
 	}
@@ -141,6 +148,14 @@ func makeStatefulSet(
 	for key, value := range p.ObjectMeta.Labels {
 		labels[key] = value
 	}
+	if p.Spec.StatefulSetMetadata != nil {
+		for key, value := range p.Spec.StatefulSetMetadata.Labels {
+			labels[key] = value
+		}
+		for key, value := range p.Spec.StatefulSetMetadata.Annotations {
+			annotations[key] = value
+		}
+	}
 	labels[shardLabelName] = fmt.Sprintf("%d", shard)
 	labels[prometheusNameLabelName] = p.Name
 
@@ -262,6 +277,16 @@ func makeStatefulSetService(p *monitoringv1.Prometheus, config operator.Config)
 		p.Spec.PortName = defaultPortName
 	}
 
+	labels := map[string]string{}
+	var annotations map[string]string
+	if p.Spec.ServiceMetadata != nil {
+		for key, value := range p.Spec.ServiceMetadata.Labels {
+			labels[key] = value
+		}
+		annotations = p.Spec.ServiceMetadata.Annotations
+	}
+	labels["operated-prometheus"] = "true"
+
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: governingServiceName,
@@ -273,9 +298,8 @@ func makeStatefulSetService(p *monitoringv1.Prometheus, config operator.Config)
 					UID:        p.GetUID(),
 				},
 			},
-			Labels: config.Labels.Merge(map[string]string{
-				"operated-prometheus": "true",
-			}),
+			Labels:      config.Labels.Merge(labels),
+			Annotations: annotations,
 		},
 		Spec: v1.ServiceSpec{
 			ClusterIP: "None",
@@ -310,11 +334,20 @@ func makeStatefulSetSpec(
 	shard int32,
 	ruleConfigMapNames []string,
 	tlsAssetSecrets []string,
+	projectedTokens map[string]monitoringv1.ProjectedToken,
 	version semver.Version,
 ) (*appsv1.StatefulSetSpec, error) {
 	// Prometheus may take quite long to shut down to checkpoint existing data.
 	// Allow up to 10 minutes for clean termination.
 	terminationGracePeriod := int64(600)
+	drainRemoteWrite := p.Spec.RemoteWriteDrainOnShutdown != nil && *p.Spec.RemoteWriteDrainOnShutdown && len(p.Spec.RemoteWrite) > 0
+	if p.Spec.TerminationGracePeriodSeconds != nil {
+		terminationGracePeriod = *p.Spec.TerminationGracePeriodSeconds
+	} else if drainRemoteWrite {
+		// Leave the time to flush the remote write queues on top of the
+		// time needed to shut down the TSDB.
+		terminationGracePeriod += remoteWriteDrainFlushDeadline
+	}
 
 	prometheusImagePath, err := operator.BuildImagePath(
 		operator.StringPtrValOrDefault(p.Spec.Image, ""),
@@ -327,7 +360,7 @@ func makeStatefulSetSpec(
 		return nil, err
 	}
 
-	if version.Major != 2 {
+	if version.Major != 2 && version.Major != 3 {
 		return nil, errors.Errorf("unsupported Prometheus major version %s", version)
 	}
 
@@ -372,7 +405,7 @@ func makeStatefulSetSpec(
 		monitoringv1.Argument{Name: "web.enable-lifecycle"},
 	)
 
-	if version.Minor >= 4 {
+	if version.GTE(semver.MustParse("2.4.0")) {
 		if p.Spec.Rules.Alert.ForOutageTolerance != "" {
 			promArgs = append(promArgs, monitoringv1.Argument{Name: "rules.alert.for-outage-tolerance", Value: p.Spec.Rules.Alert.ForOutageTolerance})
 		}
@@ -389,7 +422,7 @@ func makeStatefulSetSpec(
 			promArgs = append(promArgs, monitoringv1.Argument{Name: "query.lookback-delta", Value: *p.Spec.Query.LookbackDelta})
 		}
 
-		if version.Minor >= 5 {
+		if version.GTE(semver.MustParse("2.5.0")) {
 			if p.Spec.Query.MaxSamples != nil && *p.Spec.Query.MaxSamples > 0 {
 				promArgs = append(promArgs, monitoringv1.Argument{Name: "query.max-samples", Value: fmt.Sprintf("%d", *p.Spec.Query.MaxSamples)})
 			}
@@ -421,8 +454,26 @@ func makeStatefulSetSpec(
 		}
 	}
 
-	if len(p.Spec.EnableFeatures) > 0 {
-		promArgs = append(promArgs, monitoringv1.Argument{Name: "enable-feature", Value: strings.Join(p.Spec.EnableFeatures[:], ",")})
+	enabledFeatures := append([]string{}, p.Spec.EnableFeatures...)
+	enableOTLPReceiver := p.Spec.OTLP != nil
+	if p.Spec.EnableOTLPReceiver != nil {
+		enableOTLPReceiver = *p.Spec.EnableOTLPReceiver
+	}
+	if enableOTLPReceiver {
+		switch {
+		case version.GTE(semver.MustParse("3.0.0")):
+			promArgs = append(promArgs, monitoringv1.Argument{Name: "web.enable-otlp-receiver"})
+		case version.GTE(semver.MustParse("2.47.0")):
+			if !slices.Contains(enabledFeatures, "otlp-write-receiver") {
+				enabledFeatures = append(enabledFeatures, "otlp-write-receiver")
+			}
+		default:
+			level.Warn(logger).Log("msg", "ignoring 'otlp' not supported by Prometheus", "version", version, "minimum_version", "2.47.0")
+		}
+	}
+
+	if len(enabledFeatures) > 0 {
+		promArgs = append(promArgs, monitoringv1.Argument{Name: "enable-feature", Value: strings.Join(enabledFeatures, ",")})
 	}
 
 	if p.Spec.ExternalURL != "" {
@@ -452,19 +503,74 @@ func makeStatefulSetSpec(
 		}
 	}
 
-	if version.GTE(semver.MustParse("2.8.0")) && p.Spec.AllowOverlappingBlocks {
-		promArgs = append(promArgs, monitoringv1.Argument{Name: "storage.tsdb.allow-overlapping-blocks"})
+	if p.Spec.WALReplayConcurrency != nil {
+		if *p.Spec.WALReplayConcurrency < 1 {
+			return nil, errors.Errorf("invalid walReplayConcurrency %d: must be greater than or equal to 1", *p.Spec.WALReplayConcurrency)
+		}
+
+		if version.GTE(semver.MustParse("2.45.0")) {
+			promArgs = append(promArgs, monitoringv1.Argument{Name: "storage.tsdb.wal-replay-concurrency", Value: fmt.Sprintf("%d", *p.Spec.WALReplayConcurrency)})
+		} else {
+			level.Warn(logger).Log("msg", "ignoring 'walReplayConcurrency' not supported by Prometheus", "version", version, "minimum_version", "2.45.0")
+		}
+	}
+
+	if p.Spec.AllowOverlappingBlocks {
+		switch {
+		case version.GTE(semver.MustParse("2.39.0")):
+			level.Warn(logger).Log("msg", "ignoring 'allowOverlappingBlocks' since Prometheus always allows overlapping blocks", "version", version)
+		case version.GTE(semver.MustParse("2.8.0")):
+			promArgs = append(promArgs, monitoringv1.Argument{Name: "storage.tsdb.allow-overlapping-blocks"})
+		}
 	}
 
-	var ports []v1.ContainerPort
-	if p.Spec.ListenLocal {
-		promArgs = append(promArgs, monitoringv1.Argument{Name: "web.listen-address", Value: "127.0.0.1:9090"})
-	} else {
-		ports = []v1.ContainerPort{
-			{
-				Name:          p.Spec.PortName,
-				ContainerPort: 9090,
-				Protocol:      v1.ProtocolTCP,
+	if p.Spec.ListenLocal || p.Spec.EffectiveListenPort() != 9090 {
+		promArgs = append(promArgs, monitoringv1.Argument{Name: "web.listen-address", Value: p.Spec.EffectiveListenAddress()})
+	}
+	ports := p.Spec.ContainerPorts()
+
+	// When draining the remote write queues on shutdown, Prometheus is told
+	// to quit from the preStop hook and it may flush the queues for up to
+	// the flush deadline (bounded by the termination grace period).
+	// There's no need for an additional readiness condition: Kubernetes
+	// removes a terminating pod from the Service endpoints as soon as its
+	// deletion starts and the remote write traffic is initiated by
+	// Prometheus itself. On rollouts, the StatefulSet controller waits for
+	// the new pod to pass the /-/ready readiness probe which only succeeds
+	// after the WAL replay.
+	var lifecycle *v1.Lifecycle
+	if drainRemoteWrite {
+		flushDeadline := int64(remoteWriteDrainFlushDeadline)
+		if flushDeadline > terminationGracePeriod {
+			flushDeadline = terminationGracePeriod
+		}
+		promArgs = append(promArgs, monitoringv1.Argument{Name: "storage.remote.flush-deadline", Value: fmt.Sprintf("%ds", flushDeadline)})
+
+		quitURL := url.URL{
+			Scheme: "http",
+			Host:   fmt.Sprintf("localhost:%d", p.Spec.EffectiveListenPort()),
+			Path:   path.Clean(webRoutePrefix + "/-/quit"),
+		}
+		curlOpts, wgetOpts := "-X POST --fail", "-q -O /dev/null --post-data=''"
+		if p.Spec.Web != nil && p.Spec.Web.TLSConfig != nil {
+			quitURL.Scheme = "https"
+			curlOpts += " --insecure"
+			wgetOpts += " --no-check-certificate"
+		}
+
+		lifecycle = &v1.Lifecycle{
+			PreStop: &v1.LifecycleHandler{
+				Exec: &v1.ExecAction{
+					Command: []string{
+						"sh",
+						"-c",
+						fmt.Sprintf(
+							`if [ -x "$(command -v curl)" ]; then exec curl %s %s; elif [ -x "$(command -v wget)" ]; then exec wget %s %s; else exit 1; fi`,
+							curlOpts, quitURL.String(),
+							wgetOpts, quitURL.String(),
+						),
+					},
+				},
 			},
 		}
 	}
@@ -521,6 +627,10 @@ func makeStatefulSetSpec(
 		})
 	}
 
+	if volume, ok := projectedTokensVolume(projectedTokens); ok {
+		volumes = append(volumes, volume)
+	}
+
 	volName := volumeName(p.Name)
 	if p.Spec.Storage != nil {
 		if p.Spec.Storage.VolumeClaimTemplate.Name != "" {
@@ -558,6 +668,14 @@ func makeStatefulSetSpec(
 		promVolumeMounts = append(promVolumeMounts, vmount)
 	}
 
+	if len(projectedTokens) > 0 {
+		promVolumeMounts = append(promVolumeMounts, v1.VolumeMount{
+			Name:      "projected-tokens",
+			ReadOnly:  true,
+			MountPath: projectedTokensDir,
+		})
+	}
+
 	// Mount web config and web TLS credentials as volumes.
 	// We always mount the web config file for versions greater than 2.24.0.
 	// With this we avoid redeploying prometheus when reconfiguring between
@@ -636,7 +754,7 @@ func makeStatefulSetSpec(
 		if p.Spec.ListenLocal {
 			probeURL := url.URL{
 				Scheme: "http",
-				Host:   "localhost:9090",
+				Host:   fmt.Sprintf("localhost:%d", p.Spec.EffectiveListenPort()),
 				Path:   probePath,
 			}
 			handler.Exec = &v1.ExecAction{
@@ -699,14 +817,7 @@ func makeStatefulSetSpec(
 	// We should try to avoid removing such immutable fields whenever possible since doing
 	// so forces us to enter the 'recreate cycle' and can potentially lead to downtime.
 	// The requirement to make a change here should be carefully evaluated.
-	podSelectorLabels := map[string]string{
-		"app.kubernetes.io/name":       "prometheus",
-		"app.kubernetes.io/managed-by": "prometheus-operator",
-		"app.kubernetes.io/instance":   p.Name,
-		"prometheus":                   p.Name,
-		shardLabelName:                 fmt.Sprintf("%d", shard),
-		prometheusNameLabelName:        p.Name,
-	}
+	podSelectorLabels := p.ShardSelectorLabels(shard)
 	if p.Spec.PodMetadata != nil {
 		if p.Spec.PodMetadata.Labels != nil {
 			for k, v := range p.Spec.PodMetadata.Labels {
@@ -758,7 +869,7 @@ func makeStatefulSetSpec(
 		}
 
 		thanosArgs := []monitoringv1.Argument{
-			{Name: "prometheus.url", Value: fmt.Sprintf("%s://%s:9090%s", prometheusURIScheme, c.LocalHost, path.Clean(webRoutePrefix))},
+			{Name: "prometheus.url", Value: fmt.Sprintf("%s://%s:%d%s", prometheusURIScheme, c.LocalHost, p.Spec.EffectiveListenPort(), path.Clean(webRoutePrefix))},
 			{Name: "prometheus.http-client", Value: `{"tls_config": {"insecure_skip_verify":true}}`},
 			{Name: "grpc-address", Value: fmt.Sprintf("%s:10901", grpcBindAddress)},
 			{Name: "http-address", Value: fmt.Sprintf("%s:10902", httpBindAddress)},
@@ -870,6 +981,19 @@ func makeStatefulSetSpec(
 			thanosArgs = append(thanosArgs, monitoringv1.Argument{Name: "prometheus.ready_timeout", Value: string(p.Spec.Thanos.ReadyTimeout)})
 		}
 
+		if p.Spec.Thanos.GetConfigInterval != nil {
+			thanosVersion, err := semver.ParseTolerant(operator.StringPtrValOrDefault(p.Spec.Thanos.Version, operator.DefaultThanosVersion))
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse Thanos version")
+			}
+
+			if thanosVersion.GTE(semver.MustParse("0.29.0")) {
+				thanosArgs = append(thanosArgs, monitoringv1.Argument{Name: "prometheus.get_config_interval", Value: string(*p.Spec.Thanos.GetConfigInterval)})
+			} else {
+				level.Warn(logger).Log("msg", "ignoring 'getConfigInterval' not supported by Thanos", "version", thanosVersion, "minimum_version", "0.29.0")
+			}
+		}
+
 		containerArgs, err := buildArgs(thanosArgs, p.Spec.Thanos.AdditionalArgs)
 		if err != nil {
 			return nil, err
@@ -939,6 +1063,16 @@ func makeStatefulSetSpec(
 
 	boolFalse := false
 	boolTrue := true
+
+	var (
+		shareProcessNamespace *bool
+		reloadProcessName     string
+	)
+	if p.Spec.ReloadStrategy != nil && *p.Spec.ReloadStrategy == monitoringv1.ProcessSignalReloadStrategyType {
+		shareProcessNamespace = &boolTrue
+		reloadProcessName = "prometheus"
+	}
+
 	operatorContainers := append([]v1.Container{
 		{
 			Name:                     "prometheus",
@@ -949,7 +1083,10 @@ func makeStatefulSetSpec(
 			StartupProbe:             startupProbe,
 			LivenessProbe:            livenessProbe,
 			ReadinessProbe:           readinessProbe,
+			Lifecycle:                lifecycle,
 			Resources:                p.Spec.Resources,
+			Env:                      p.Spec.Env,
+			EnvFrom:                  p.Spec.EnvFrom,
 			TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
 			SecurityContext: &v1.SecurityContext{
 				ReadOnlyRootFilesystem:   &boolTrue,
@@ -964,9 +1101,11 @@ func makeStatefulSetSpec(
 			operator.ReloaderResources(c.ReloaderConfig),
 			operator.ReloaderURL(url.URL{
 				Scheme: prometheusURIScheme,
-				Host:   c.LocalHost + ":9090",
+				Host:   fmt.Sprintf("%s:%d", c.LocalHost, p.Spec.EffectiveListenPort()),
 				Path:   path.Clean(webRoutePrefix + "/-/reload"),
 			}),
+			operator.ReloaderProcessSignal(reloadProcessName),
+			operator.ReloaderSkipInitialReload(p.Spec.SkipInitConfigReload != nil && *p.Spec.SkipInitConfigReload),
 			operator.ListenLocal(p.Spec.ListenLocal),
 			operator.LocalHost(c.LocalHost),
 			operator.LogFormat(p.Spec.LogFormat),
@@ -974,6 +1113,7 @@ func makeStatefulSetSpec(
 			operator.ConfigFile(path.Join(confDir, configFilename)),
 			operator.ConfigEnvsubstFile(path.Join(confOutDir, configEnvsubstFilename)),
 			operator.WatchedDirectories(watchedDirectories), operator.VolumeMounts(configReloaderVolumeMounts),
+			operator.WatchInterval(p.Spec.ConfigReloaderWatchInterval),
 			operator.Shard(shard),
 		),
 	}, additionalContainers...)
@@ -1016,11 +1156,47 @@ func makeStatefulSetSpec(
 				TopologySpreadConstraints:     p.Spec.TopologySpreadConstraints,
 				HostAliases:                   operator.MakeHostAliases(p.Spec.HostAliases),
 				HostNetwork:                   p.Spec.HostNetwork,
+				ShareProcessNamespace:         shareProcessNamespace,
 			},
 		},
 	}, nil
 }
 
+// projectedTokensVolume returns a projected volume holding the service
+// account tokens, sorted by file name to keep the statefulset spec stable.
+func projectedTokensVolume(projectedTokens map[string]monitoringv1.ProjectedToken) (v1.Volume, bool) {
+	if len(projectedTokens) == 0 {
+		return v1.Volume{}, false
+	}
+
+	keys := make([]string, 0, len(projectedTokens))
+	for k := range projectedTokens {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sources := make([]v1.VolumeProjection, 0, len(keys))
+	for _, k := range keys {
+		pt := projectedTokens[k]
+		sources = append(sources, v1.VolumeProjection{
+			ServiceAccountToken: &v1.ServiceAccountTokenProjection{
+				Audience:          pt.Audience,
+				ExpirationSeconds: pt.ExpirationSeconds,
+				Path:              k,
+			},
+		})
+	}
+
+	return v1.Volume{
+		Name: "projected-tokens",
+		VolumeSource: v1.VolumeSource{
+			Projected: &v1.ProjectedVolumeSource{
+				Sources: sources,
+			},
+		},
+	}, true
+}
+
 func configSecretName(name string) string {
 	return prefixedName(name)
 }
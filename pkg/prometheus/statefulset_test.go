@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
 )
 
 var (
@@ -91,7 +92,7 @@ func TestStatefulSetLabelingAndAnnotations(t *testing.T) {
 			Labels:      labels,
 			Annotations: annotations,
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 
@@ -111,6 +112,73 @@ func TestStatefulSetLabelingAndAnnotations(t *testing.T) {
 	}
 }
 
+func TestStatefulSetMetadata(t *testing.T) {
+	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				StatefulSetMetadata: &monitoringv1.EmbeddedObjectMetadata{
+					Labels: map[string]string{
+						"testlabel":                    "testvalue",
+						"operator.prometheus.io/shard": "42",
+					},
+					Annotations: map[string]string{
+						"testannotation": "testvalue",
+					},
+				},
+			},
+		},
+	}, defaultTestConfig, nil, "", 0, nil, nil)
+	require.NoError(t, err)
+
+	expectedLabels := map[string]string{
+		"testlabel":                    "testvalue",
+		"operator.prometheus.io/name":  "test",
+		"operator.prometheus.io/shard": "0",
+	}
+	if !reflect.DeepEqual(expectedLabels, sset.Labels) {
+		t.Log(pretty.Compare(expectedLabels, sset.Labels))
+		t.Fatal("StatefulSet labels are not properly propagated")
+	}
+
+	if val, ok := sset.Annotations["testannotation"]; !ok || val != "testvalue" {
+		t.Fatal("StatefulSet annotations are not properly propagated")
+	}
+}
+
+func TestServiceMetadata(t *testing.T) {
+	svc := makeStatefulSetService(&monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				ServiceMetadata: &monitoringv1.EmbeddedObjectMetadata{
+					Labels: map[string]string{
+						"testlabel":           "testvalue",
+						"operated-prometheus": "false",
+					},
+					Annotations: map[string]string{
+						"testannotation": "testvalue",
+					},
+				},
+			},
+		},
+	}, *defaultTestConfig)
+
+	expectedLabels := map[string]string{
+		"testlabel":           "testvalue",
+		"operated-prometheus": "true",
+	}
+	if !reflect.DeepEqual(expectedLabels, svc.Labels) {
+		t.Log(pretty.Compare(expectedLabels, svc.Labels))
+		t.Fatal("Service labels are not properly propagated")
+	}
+
+	if val, ok := svc.Annotations["testannotation"]; !ok || val != "testvalue" {
+		t.Fatal("Service annotations are not properly propagated")
+	}
+}
+
 func TestPodLabelsAnnotations(t *testing.T) {
 	annotations := map[string]string{
 		"testannotation": "testvalue",
@@ -128,7 +196,7 @@ func TestPodLabelsAnnotations(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	require.NoError(t, err)
 	if val, ok := sset.Spec.Template.ObjectMeta.Labels["testlabel"]; !ok || val != "testvalue" {
 		t.Fatal("Pod labels are not properly propagated")
@@ -150,7 +218,7 @@ func TestPodLabelsShouldNotBeSelectorLabels(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 
@@ -191,7 +259,7 @@ func TestStatefulSetPVC(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 	ssetPvc := sset.Spec.VolumeClaimTemplates[0]
@@ -225,7 +293,7 @@ func TestStatefulSetEmptyDir(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 	ssetVolumes := sset.Spec.Template.Spec.Volumes
@@ -265,7 +333,7 @@ func TestStatefulSetEphemeral(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 	ssetVolumes := sset.Spec.Template.Spec.Volumes
@@ -404,7 +472,7 @@ func TestStatefulSetVolumeInitial(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, []string{"rules-configmap-one"}, "", 0, []string{tlsAssetsSecretName("volume-init-test") + "-0"})
+	}, defaultTestConfig, []string{"rules-configmap-one"}, "", 0, []string{tlsAssetsSecretName("volume-init-test") + "-0"}, nil)
 
 	require.NoError(t, err)
 
@@ -426,7 +494,7 @@ func TestAdditionalConfigMap(t *testing.T) {
 				ConfigMaps: []string{"test-cm1"},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -459,7 +527,7 @@ func TestListenLocal(t *testing.T) {
 				ListenLocal: true,
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -550,7 +618,7 @@ func TestListenTLS(t *testing.T) {
 			},
 			Thanos: &monitoringv1.ThanosSpec{},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -632,7 +700,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 				},
 				Tag: "my-unrelated-tag",
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -652,7 +720,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 				SHA: "7384a79f4b4991bf8269e7452390249b7c70bcdd10509c8c1c6c6e30e32fb324",
 				Tag: "my-unrelated-tag",
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -675,7 +743,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 				SHA: "7384a79f4b4991bf8269e7452390249b7c70bcdd10509c8c1c6c6e30e32fb324",
 				Tag: "my-unrelated-tag",
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -697,7 +765,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 				SHA: "7384a79f4b4991bf8269e7452390249b7c70bcdd10509c8c1c6c6e30e32fb324",
 				Tag: "my-unrelated-tag",
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -717,7 +785,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 					Image:   &image,
 				},
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -738,7 +806,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 				},
 				SHA: "7384a79f4b4991bf8269e7452390249b7c70bcdd10509c8c1c6c6e30e32fb324",
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -757,7 +825,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 					Image: &image,
 				},
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -777,7 +845,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 				},
 				SHA: "7384a79f4b4991bf8269e7452390249b7c70bcdd10509c8c1c6c6e30e32fb324",
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -797,7 +865,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 				},
 				Tag: "my-unrelated-tag",
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -818,7 +886,7 @@ func TestTagAndShaAndVersion(t *testing.T) {
 				SHA: "7384a79f4b4991bf8269e7452390249b7c70bcdd10509c8c1c6c6e30e32fb324",
 				Tag: "my-unrelated-tag",
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -855,7 +923,7 @@ func TestPrometheusDefaultBaseImageFlag(t *testing.T) {
 			Labels:      labels,
 			Annotations: annotations,
 		},
-	}, prometheusBaseImageConfig, nil, "", 0, nil)
+	}, prometheusBaseImageConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 
@@ -893,7 +961,7 @@ func TestThanosDefaultBaseImageFlag(t *testing.T) {
 		Spec: monitoringv1.PrometheusSpec{
 			Thanos: &monitoringv1.ThanosSpec{},
 		},
-	}, thanosBaseImageConfig, nil, "", 0, nil)
+	}, thanosBaseImageConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 
@@ -915,7 +983,7 @@ func TestThanosTagAndShaAndVersion(t *testing.T) {
 					Tag:     &thanosTag,
 				},
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -938,7 +1006,7 @@ func TestThanosTagAndShaAndVersion(t *testing.T) {
 					Tag:     &thanosTag,
 				},
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -963,7 +1031,7 @@ func TestThanosTagAndShaAndVersion(t *testing.T) {
 					Image:   &thanosImage,
 				},
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 		}
@@ -981,7 +1049,7 @@ func TestThanosResourcesNotSet(t *testing.T) {
 		Spec: monitoringv1.PrometheusSpec{
 			Thanos: &monitoringv1.ThanosSpec{},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1009,7 +1077,7 @@ func TestThanosResourcesSet(t *testing.T) {
 				Resources: expected,
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1025,7 +1093,7 @@ func TestThanosNoObjectStorage(t *testing.T) {
 		Spec: monitoringv1.PrometheusSpec{
 			Thanos: &monitoringv1.ThanosSpec{},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1062,7 +1130,7 @@ func TestThanosObjectStorage(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1150,7 +1218,7 @@ func TestThanosObjectStorageFile(t *testing.T) {
 				ObjectStorageConfigFile: &testPath,
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1238,7 +1306,7 @@ func TestThanosTracing(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1303,7 +1371,7 @@ func TestThanosSideCarVolumes(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
@@ -1366,7 +1434,7 @@ func TestRetentionAndRetentionSize(t *testing.T) {
 				Retention:     test.specRetention,
 				RetentionSize: test.specRetentionSize,
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -1421,7 +1489,7 @@ func TestReplicasConfigurationWithSharding(t *testing.T) {
 				Shards:   &shards,
 			},
 		},
-	}, testConfig, nil, "", 1, nil)
+	}, testConfig, nil, "", 1, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1459,7 +1527,7 @@ func TestSidecarsNoResources(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1489,7 +1557,7 @@ func TestSidecarsNoRequests(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1522,7 +1590,7 @@ func TestSidecarsNoLimits(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1555,7 +1623,7 @@ func TestSidecarsNoCPUResources(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1589,7 +1657,7 @@ func TestSidecarsNoCPURequests(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1624,7 +1692,7 @@ func TestSidecarsNoCPULimits(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1659,7 +1727,7 @@ func TestSidecarsNoMemoryResources(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1693,7 +1761,7 @@ func TestSidecarsNoMemoryRequests(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1728,7 +1796,7 @@ func TestSidecarsNoMemoryLimits(t *testing.T) {
 	}
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, testConfig, nil, "", 0, nil)
+	}, testConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1751,7 +1819,7 @@ func TestSidecarsNoMemoryLimits(t *testing.T) {
 
 func TestAdditionalContainers(t *testing.T) {
 	// The base to compare everything against
-	baseSet, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{}, defaultTestConfig, nil, "", 0, nil)
+	baseSet, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{}, defaultTestConfig, nil, "", 0, nil, nil)
 	require.NoError(t, err)
 
 	// Add an extra container
@@ -1765,7 +1833,7 @@ func TestAdditionalContainers(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	require.NoError(t, err)
 
 	if len(baseSet.Spec.Template.Spec.Containers)+1 != len(addSset.Spec.Template.Spec.Containers) {
@@ -1786,7 +1854,7 @@ func TestAdditionalContainers(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	require.NoError(t, err)
 
 	if len(baseSet.Spec.Template.Spec.Containers) != len(modSset.Spec.Template.Spec.Containers) {
@@ -1831,7 +1899,7 @@ func TestWALCompression(t *testing.T) {
 					WALCompression: test.enabled,
 				},
 			},
-		}, defaultTestConfig, nil, "", 0, nil)
+		}, defaultTestConfig, nil, "", 0, nil, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -1900,7 +1968,7 @@ func TestThanosListenLocal(t *testing.T) {
 				Spec: monitoringv1.PrometheusSpec{
 					Thanos: &tc.spec,
 				},
-			}, defaultTestConfig, nil, "", 0, nil)
+			}, defaultTestConfig, nil, "", 0, nil, nil)
 			if err != nil {
 				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 			}
@@ -1923,7 +1991,7 @@ func TestThanosListenLocal(t *testing.T) {
 }
 
 func TestTerminationPolicy(t *testing.T) {
-	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{Spec: monitoringv1.PrometheusSpec{}}, defaultTestConfig, nil, "", 0, nil)
+	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{Spec: monitoringv1.PrometheusSpec{}}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -1942,7 +2010,7 @@ func TestEnableFeaturesWithOneFeature(t *testing.T) {
 				EnableFeatures: []string{"exemplar-storage"},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
@@ -1967,7 +2035,7 @@ func TestEnableFeaturesWithMultipleFeature(t *testing.T) {
 				EnableFeatures: []string{"exemplar-storage1", "exemplar-storage2"},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
@@ -1995,7 +2063,7 @@ func TestWebPageTitle(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
@@ -2044,7 +2112,7 @@ func TestExpectedStatefulSetShardNames(t *testing.T) {
 func TestExpectStatefulSetMinReadySeconds(t *testing.T) {
 	statefulSet, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err != nil {
 		t.Fatal(err)
@@ -2061,7 +2129,7 @@ func TestExpectStatefulSetMinReadySeconds(t *testing.T) {
 				MinReadySeconds: &expect,
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err != nil {
 		t.Fatal(err)
@@ -2074,7 +2142,7 @@ func TestExpectStatefulSetMinReadySeconds(t *testing.T) {
 
 func TestConfigReloader(t *testing.T) {
 	expectedShardNum := 0
-	baseSet, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{}, defaultTestConfig, nil, "", int32(expectedShardNum), nil)
+	baseSet, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{}, defaultTestConfig, nil, "", int32(expectedShardNum), nil, nil)
 	require.NoError(t, err)
 
 	expectedArgsConfigReloader := []string{
@@ -2126,7 +2194,7 @@ func TestThanosReadyTimeout(t *testing.T) {
 				ReadyTimeout: "20m",
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -2147,12 +2215,73 @@ func TestThanosReadyTimeout(t *testing.T) {
 	}
 }
 
+func TestRulesAlertArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected bool
+	}{
+		{
+			name:    "v2.3.0",
+			version: "v2.3.0",
+		},
+		{
+			name:     "v2.4.0",
+			version:  "v2.4.0",
+			expected: true,
+		},
+		{
+			name:     "v3.0.0",
+			version:  "v3.0.0",
+			expected: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ss, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+					},
+					Rules: monitoringv1.Rules{
+						Alert: monitoringv1.RulesAlert{
+							ForOutageTolerance: "1h",
+							ForGracePeriod:     "10m",
+							ResendDelay:        "1m",
+						},
+					},
+				},
+			}, defaultTestConfig, nil, "", 0, nil, nil)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, arg := range []string{
+				"--rules.alert.for-outage-tolerance=1h",
+				"--rules.alert.for-grace-period=10m",
+				"--rules.alert.resend-delay=1m",
+			} {
+				found := false
+				for _, a := range ss.Spec.Template.Spec.Containers[0].Args {
+					if a == arg {
+						found = true
+						break
+					}
+				}
+
+				if found != tc.expected {
+					t.Fatalf("expected %q to be present: %v, got %v", arg, tc.expected, found)
+				}
+			}
+		})
+	}
+}
+
 func TestQueryLogFileVolumeMountPresent(t *testing.T) {
 	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{
 			QueryLogFile: "test.log",
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -2191,7 +2320,7 @@ func TestQueryLogFileVolumeMountNotPresent(t *testing.T) {
 		Spec: monitoringv1.PrometheusSpec{
 			QueryLogFile: "/tmp/test.log",
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -2260,7 +2389,7 @@ func TestEnableRemoteWriteReceiver(t *testing.T) {
 						EnableRemoteWriteReceiver: tc.enableRemoteWriteReceiver,
 					},
 				},
-			}, defaultTestConfig, nil, "", 0, nil)
+			}, defaultTestConfig, nil, "", 0, nil, nil)
 
 			if err != nil {
 				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
@@ -2281,6 +2410,114 @@ func TestEnableRemoteWriteReceiver(t *testing.T) {
 	}
 }
 
+func TestOTLPReceiverFeature(t *testing.T) {
+	for _, tc := range []struct {
+		name               string
+		version            string
+		enableFeatures     []string
+		otlp               *monitoringv1.OTLPConfig
+		enableOTLPReceiver *bool
+		expectedFeatures   string
+		expectedFlag       bool
+	}{
+		{
+			name:    "unsupported version",
+			version: "2.46.0",
+			otlp:    &monitoringv1.OTLPConfig{},
+		},
+		{
+			name:             "otlp config",
+			version:          "2.47.0",
+			otlp:             &monitoringv1.OTLPConfig{},
+			expectedFeatures: "otlp-write-receiver",
+		},
+		{
+			name:             "otlp config with other features",
+			version:          "2.55.0",
+			enableFeatures:   []string{"exemplar-storage"},
+			otlp:             &monitoringv1.OTLPConfig{},
+			expectedFeatures: "exemplar-storage,otlp-write-receiver",
+		},
+		{
+			name:             "feature not enabled twice",
+			version:          "2.55.0",
+			enableFeatures:   []string{"otlp-write-receiver"},
+			otlp:             &monitoringv1.OTLPConfig{},
+			expectedFeatures: "otlp-write-receiver",
+		},
+		{
+			name:               "enableOTLPReceiver true",
+			version:            "2.47.0",
+			enableOTLPReceiver: pointer.BoolPtr(true),
+			expectedFeatures:   "otlp-write-receiver",
+		},
+		{
+			name:               "enableOTLPReceiver false overrides otlp config",
+			version:            "2.55.0",
+			otlp:               &monitoringv1.OTLPConfig{},
+			enableOTLPReceiver: pointer.BoolPtr(false),
+		},
+		{
+			name:         "otlp config with Prometheus v3",
+			version:      "3.0.0",
+			otlp:         &monitoringv1.OTLPConfig{},
+			expectedFlag: true,
+		},
+		{
+			name:               "enableOTLPReceiver true with Prometheus v3",
+			version:            "3.1.0",
+			enableFeatures:     []string{"exemplar-storage"},
+			enableOTLPReceiver: pointer.BoolPtr(true),
+			expectedFeatures:   "exemplar-storage",
+			expectedFlag:       true,
+		},
+		{
+			name:               "enableOTLPReceiver false with Prometheus v3",
+			version:            "3.0.0",
+			otlp:               &monitoringv1.OTLPConfig{},
+			enableOTLPReceiver: pointer.BoolPtr(false),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version:            tc.version,
+						EnableFeatures:     tc.enableFeatures,
+						EnableOTLPReceiver: tc.enableOTLPReceiver,
+					},
+					OTLP: tc.otlp,
+				},
+			}, defaultTestConfig, nil, "", 0, nil, nil)
+
+			if err != nil {
+				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+			}
+
+			var (
+				features string
+				flag     bool
+			)
+			for _, arg := range sset.Spec.Template.Spec.Containers[0].Args {
+				if strings.HasPrefix(arg, "--enable-feature=") {
+					features = strings.TrimPrefix(arg, "--enable-feature=")
+				}
+				if arg == "--web.enable-otlp-receiver" {
+					flag = true
+				}
+			}
+
+			if features != tc.expectedFeatures {
+				t.Fatalf("Expecting enabled features to be %q, got %q", tc.expectedFeatures, features)
+			}
+
+			if flag != tc.expectedFlag {
+				t.Fatalf("Expecting --web.enable-otlp-receiver to be %t, got %t", tc.expectedFlag, flag)
+			}
+		})
+	}
+}
+
 func TestPodTemplateConfig(t *testing.T) {
 	nodeSelector := map[string]string{
 		"foo": "bar",
@@ -2340,7 +2577,7 @@ func TestPodTemplateConfig(t *testing.T) {
 				HostNetwork:        hostNetwork,
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -2413,7 +2650,7 @@ func TestPrometheusAdditionalArgsNoError(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 	ssetContainerArgs := sset.Spec.Template.Spec.Containers[0].Args
@@ -2447,7 +2684,7 @@ func TestPrometheusAdditionalArgsDuplicate(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err == nil {
 		t.Fatal("expected error for Prometheus additionalArgs configuration")
@@ -2482,7 +2719,7 @@ func TestPrometheusAdditionalBinaryArgsDuplicate(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err == nil {
 		t.Fatal("expected error for Prometheus additionalArgs configuration")
@@ -2520,7 +2757,7 @@ func TestPrometheusAdditionalNoPrefixArgsDuplicate(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err == nil {
 		t.Fatal("expected error for Prometheus additionalArgs configuration")
@@ -2565,7 +2802,7 @@ func TestThanosAdditionalArgsNoError(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	require.NoError(t, err)
 	ssetContainerArgs := sset.Spec.Template.Spec.Containers[2].Args
@@ -2600,7 +2837,7 @@ func TestThanosAdditionalArgsDuplicate(t *testing.T) {
 				},
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 
 	if err == nil {
 		t.Fatal("expected error for Thanos additionalArgs configuration")
@@ -2679,6 +2916,21 @@ func TestPrometheusQuerySpec(t *testing.T) {
 			timeout:        durationPtr("1m"),
 			version:        "v2.5.0",
 
+			expected: []string{
+				"--query.lookback-delta=2m",
+				"--query.max-concurrency=10",
+				"--query.max-samples=10000",
+				"--query.timeout=1m",
+			},
+		},
+		{
+			name:           "max samples not skipped with Prometheus v3",
+			lookbackDelta:  stringPtr("2m"),
+			maxConcurrency: int32Ptr(10),
+			maxSamples:     int32Ptr(10000),
+			timeout:        durationPtr("1m"),
+			version:        "v3.0.0",
+
 			expected: []string{
 				"--query.lookback-delta=2m",
 				"--query.max-concurrency=10",
@@ -2700,7 +2952,7 @@ func TestPrometheusQuerySpec(t *testing.T) {
 						Timeout:        tc.timeout,
 					},
 				},
-			}, defaultTestConfig, nil, "", 0, nil)
+			}, defaultTestConfig, nil, "", 0, nil, nil)
 
 			if err != nil {
 				t.Fatalf("expected no error, got %v", err)
@@ -2768,7 +3020,7 @@ func TestSecurityContextCapabilities(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{Spec: tc.spec}, defaultTestConfig, nil, "", 0, nil)
+			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{Spec: tc.spec}, defaultTestConfig, nil, "", 0, nil, nil)
 			if err != nil {
 				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 			}
@@ -2809,7 +3061,7 @@ func TestPodHostNetworkConfig(t *testing.T) {
 				HostNetwork: hostNetwork,
 			},
 		},
-	}, defaultTestConfig, nil, "", 0, nil)
+	}, defaultTestConfig, nil, "", 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
 	}
@@ -2822,3 +3074,386 @@ func TestPodHostNetworkConfig(t *testing.T) {
 		t.Fatalf("expected DNSPolicy configuration to match due to hostNetwork but failed")
 	}
 }
+
+func TestImageReferencesMatchStatefulSet(t *testing.T) {
+	p := monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Version: "v2.39.1",
+			},
+			Thanos: &monitoringv1.ThanosSpec{},
+		},
+	}
+
+	images, err := p.ImageReferences(monitoringv1.ImageDefaults{
+		PrometheusBaseImage: defaultTestConfig.PrometheusDefaultBaseImage,
+		ThanosBaseImage:     defaultTestConfig.ThanosDefaultBaseImage,
+		ThanosVersion:       operator.DefaultThanosVersion,
+		ConfigReloaderImage: defaultTestConfig.ReloaderConfig.Image,
+	})
+	require.NoError(t, err)
+
+	sset, err := makeStatefulSet(newLogger(), "test", p, defaultTestConfig, nil, "", 0, nil, nil)
+	require.NoError(t, err)
+
+	containers := append(sset.Spec.Template.Spec.InitContainers, sset.Spec.Template.Spec.Containers...)
+	for _, c := range containers {
+		require.Contains(t, images, c.Image, "image of container %q", c.Name)
+	}
+}
+
+func TestPrometheusEnv(t *testing.T) {
+	env := []v1.EnvVar{{Name: "AWS_REGION", Value: "eu-west-1"}}
+	envFrom := []v1.EnvFromSource{
+		{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "aws-credentials"}}},
+	}
+
+	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Env:     env,
+				EnvFrom: envFrom,
+			},
+		},
+	}, defaultTestConfig, nil, "", 0, nil, nil)
+	require.NoError(t, err)
+
+	for _, c := range sset.Spec.Template.Spec.Containers {
+		if c.Name == "prometheus" {
+			require.Equal(t, env, c.Env)
+			require.Equal(t, envFrom, c.EnvFrom)
+			return
+		}
+	}
+
+	t.Fatal("prometheus container not found")
+}
+
+func TestThanosGetConfigInterval(t *testing.T) {
+	interval := monitoringv1.Duration("1m")
+
+	for _, tc := range []struct {
+		version  string
+		expected bool
+	}{
+		{version: "v0.28.0", expected: false},
+		{version: "v0.29.0", expected: true},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					Thanos: &monitoringv1.ThanosSpec{
+						Version:           pointer.StringPtr(tc.version),
+						GetConfigInterval: &interval,
+					},
+				},
+			}, defaultTestConfig, nil, "", 0, nil, nil)
+			require.NoError(t, err)
+
+			var found bool
+			for _, c := range sset.Spec.Template.Spec.Containers {
+				if c.Name != "thanos-sidecar" {
+					continue
+				}
+				for _, arg := range c.Args {
+					if arg == "--prometheus.get_config_interval=1m" {
+						found = true
+					}
+				}
+			}
+
+			require.Equal(t, tc.expected, found)
+		})
+	}
+}
+
+func TestReloadStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		name                  string
+		reloadStrategy        *monitoringv1.ReloadStrategyType
+		shareProcessNamespace *bool
+		expectedArgs          []string
+	}{
+		{
+			name:         "default",
+			expectedArgs: []string{"--reload-url=http://localhost:9090/-/reload"},
+		},
+		{
+			name:           "http",
+			reloadStrategy: func(s monitoringv1.ReloadStrategyType) *monitoringv1.ReloadStrategyType { return &s }(monitoringv1.HTTPReloadStrategyType),
+			expectedArgs:   []string{"--reload-url=http://localhost:9090/-/reload"},
+		},
+		{
+			name:                  "process signal",
+			reloadStrategy:        func(s monitoringv1.ReloadStrategyType) *monitoringv1.ReloadStrategyType { return &s }(monitoringv1.ProcessSignalReloadStrategyType),
+			shareProcessNamespace: pointer.Bool(true),
+			expectedArgs:          []string{"--reload-method=signal", "--process-executable-name=prometheus"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						ReloadStrategy: tc.reloadStrategy,
+					},
+				},
+			}, defaultTestConfig, nil, "", 0, nil, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.shareProcessNamespace, sset.Spec.Template.Spec.ShareProcessNamespace)
+
+			for _, c := range sset.Spec.Template.Spec.Containers {
+				if c.Name == "config-reloader" {
+					for _, arg := range tc.expectedArgs {
+						require.Contains(t, c.Args, arg)
+					}
+					return
+				}
+			}
+
+			t.Fatal("config-reloader container not found")
+		})
+	}
+}
+
+func TestWebListenPort(t *testing.T) {
+	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Web: &monitoringv1.PrometheusWebSpec{
+					ListenPort: pointer.Int32(8080),
+				},
+			},
+			Thanos: &monitoringv1.ThanosSpec{},
+		},
+	}, defaultTestConfig, nil, "", 0, nil, nil)
+	require.NoError(t, err)
+
+	for _, c := range sset.Spec.Template.Spec.Containers {
+		switch c.Name {
+		case "prometheus":
+			require.Contains(t, c.Args, "--web.listen-address=0.0.0.0:8080")
+			require.Equal(t, int32(8080), c.Ports[0].ContainerPort)
+		case "config-reloader":
+			require.Contains(t, c.Args, "--reload-url=http://localhost:8080/-/reload")
+		case "thanos-sidecar":
+			require.Contains(t, c.Args, "--prometheus.url=http://localhost:8080/")
+		}
+	}
+}
+
+func TestWALReplayConcurrency(t *testing.T) {
+	for _, tc := range []struct {
+		version     string
+		concurrency int32
+		expected    bool
+		err         bool
+	}{
+		{version: "v2.44.0", concurrency: 4, expected: false},
+		{version: "v2.45.0", concurrency: 4, expected: true},
+		{version: "v2.45.0", concurrency: 0, err: true},
+	} {
+		t.Run(fmt.Sprintf("%s-%d", tc.version, tc.concurrency), func(t *testing.T) {
+			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+					},
+					WALReplayConcurrency: pointer.Int32(tc.concurrency),
+				},
+			}, defaultTestConfig, nil, "", 0, nil, nil)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			arg := fmt.Sprintf("--storage.tsdb.wal-replay-concurrency=%d", tc.concurrency)
+			for _, c := range sset.Spec.Template.Spec.Containers {
+				if c.Name == "prometheus" {
+					if tc.expected {
+						require.Contains(t, c.Args, arg)
+					} else {
+						require.NotContains(t, c.Args, arg)
+					}
+					return
+				}
+			}
+
+			t.Fatal("prometheus container not found")
+		})
+	}
+}
+
+func TestTerminationGracePeriodSeconds(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		period   *int64
+		expected int64
+	}{
+		{
+			name:     "default",
+			expected: 600,
+		},
+		{
+			name:     "custom",
+			period:   pointer.Int64(900),
+			expected: 900,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						TerminationGracePeriodSeconds: tc.period,
+					},
+				},
+			}, defaultTestConfig, nil, "", 0, nil, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *sset.Spec.Template.Spec.TerminationGracePeriodSeconds)
+		})
+	}
+}
+
+func TestProjectedTokensVolume(t *testing.T) {
+	projectedTokens := map[string]monitoringv1.ProjectedToken{
+		"token-b": {Audience: "b", ExpirationSeconds: pointer.Int64(600)},
+		"token-a": {Audience: "a"},
+	}
+
+	sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{}, defaultTestConfig, nil, "", 0, nil, projectedTokens)
+	require.NoError(t, err)
+
+	var found bool
+	for _, vol := range sset.Spec.Template.Spec.Volumes {
+		if vol.Name != "projected-tokens" {
+			continue
+		}
+		found = true
+
+		require.NotNil(t, vol.Projected)
+		require.Len(t, vol.Projected.Sources, 2)
+		require.Equal(t, "token-a", vol.Projected.Sources[0].ServiceAccountToken.Path)
+		require.Equal(t, "a", vol.Projected.Sources[0].ServiceAccountToken.Audience)
+		require.Equal(t, "token-b", vol.Projected.Sources[1].ServiceAccountToken.Path)
+		require.Equal(t, pointer.Int64(600), vol.Projected.Sources[1].ServiceAccountToken.ExpirationSeconds)
+	}
+	require.True(t, found, "projected-tokens volume not found")
+
+	for _, c := range sset.Spec.Template.Spec.Containers {
+		if c.Name == "prometheus" {
+			require.Contains(t, c.VolumeMounts, v1.VolumeMount{Name: "projected-tokens", ReadOnly: true, MountPath: "/etc/prometheus/projected-tokens"})
+		}
+	}
+}
+
+func TestRemoteWriteDrainOnShutdown(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		drain               *bool
+		remoteWrite         []monitoringv1.RemoteWriteSpec
+		gracePeriod         *int64
+		expectPreStop       bool
+		expectedGracePeriod int64
+		expectedArg         string
+	}{
+		{
+			name:                "default",
+			remoteWrite:         []monitoringv1.RemoteWriteSpec{{URL: "http://example.com"}},
+			expectedGracePeriod: 600,
+		},
+		{
+			name:                "enabled without remote write",
+			drain:               pointer.Bool(true),
+			expectedGracePeriod: 600,
+		},
+		{
+			name:                "enabled with remote write",
+			drain:               pointer.Bool(true),
+			remoteWrite:         []monitoringv1.RemoteWriteSpec{{URL: "http://example.com"}},
+			expectPreStop:       true,
+			expectedGracePeriod: 900,
+			expectedArg:         "--storage.remote.flush-deadline=300s",
+		},
+		{
+			name:                "enabled with remote write and grace period",
+			drain:               pointer.Bool(true),
+			remoteWrite:         []monitoringv1.RemoteWriteSpec{{URL: "http://example.com"}},
+			gracePeriod:         pointer.Int64(1200),
+			expectPreStop:       true,
+			expectedGracePeriod: 1200,
+			expectedArg:         "--storage.remote.flush-deadline=300s",
+		},
+		{
+			name:                "enabled with remote write and short grace period",
+			drain:               pointer.Bool(true),
+			remoteWrite:         []monitoringv1.RemoteWriteSpec{{URL: "http://example.com"}},
+			gracePeriod:         pointer.Int64(120),
+			expectPreStop:       true,
+			expectedGracePeriod: 120,
+			expectedArg:         "--storage.remote.flush-deadline=120s",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						RemoteWriteDrainOnShutdown:    tc.drain,
+						RemoteWrite:                   tc.remoteWrite,
+						TerminationGracePeriodSeconds: tc.gracePeriod,
+					},
+				},
+			}, defaultTestConfig, nil, "", 0, nil, nil)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedGracePeriod, *sset.Spec.Template.Spec.TerminationGracePeriodSeconds)
+
+			prom := sset.Spec.Template.Spec.Containers[0]
+			require.Equal(t, "prometheus", prom.Name)
+
+			if !tc.expectPreStop {
+				require.Nil(t, prom.Lifecycle)
+				for _, arg := range prom.Args {
+					require.False(t, strings.HasPrefix(arg, "--storage.remote.flush-deadline="), "unexpected argument %q", arg)
+				}
+				return
+			}
+
+			require.NotNil(t, prom.Lifecycle)
+			require.NotNil(t, prom.Lifecycle.PreStop)
+			require.NotNil(t, prom.Lifecycle.PreStop.Exec)
+			require.Contains(t, prom.Lifecycle.PreStop.Exec.Command[2], "http://localhost:9090/-/quit")
+			require.Contains(t, prom.Args, tc.expectedArg)
+		})
+	}
+}
+
+func TestAllowOverlappingBlocks(t *testing.T) {
+	for _, tc := range []struct {
+		version  string
+		expected bool
+	}{
+		{version: "v2.7.0"},
+		{version: "v2.8.0", expected: true},
+		{version: "v2.38.0", expected: true},
+		{version: "v2.39.0"},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			sset, err := makeStatefulSet(newLogger(), "test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: tc.version,
+					},
+					AllowOverlappingBlocks: true,
+				},
+			}, defaultTestConfig, nil, "", 0, nil, nil)
+			require.NoError(t, err)
+
+			args := sset.Spec.Template.Spec.Containers[0].Args
+			if tc.expected {
+				require.Contains(t, args, "--storage.tsdb.allow-overlapping-blocks")
+				return
+			}
+			require.NotContains(t, args, "--storage.tsdb.allow-overlapping-blocks")
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/expfmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+const (
+	thanosSidecarHTTPPort = 10902
+	// thanosSidecarRequestTimeout bounds the time spent fetching the metrics
+	// of a single Thanos sidecar.
+	thanosSidecarRequestTimeout = 10 * time.Second
+
+	thanosLastSuccessfulUploadMetric = "thanos_objstore_bucket_last_successful_upload_time"
+	thanosUploadFailuresMetric       = "thanos_shipper_upload_failures_total"
+)
+
+// thanosSidecarMetrics holds the upload metrics of a single Thanos sidecar.
+type thanosSidecarMetrics struct {
+	lastSuccessfulUpload time.Time
+	uploadFailures       float64
+	// lastUploadFailure is the time at which the operator observed an
+	// increase of the upload failures. It is zero if unknown.
+	lastUploadFailure time.Time
+}
+
+// thanosUploadFailureTracker records when the upload failures of the Thanos
+// sidecars were seen increasing since the sidecars don't expose the time of
+// the last failure.
+// The zero thanosUploadFailureTracker is ready to use.
+type thanosUploadFailureTracker struct {
+	once sync.Once
+	// mtx protects all fields below.
+	mtx       sync.Mutex
+	failures  map[string]float64
+	lastTimes map[string]time.Time
+}
+
+// Observe records the upload failures reported by the sidecar identified by
+// k and returns the last time at which the failures increased.
+func (t *thanosUploadFailureTracker) Observe(k string, failures float64, now time.Time) time.Time {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.once.Do(func() {
+		t.failures = map[string]float64{}
+		t.lastTimes = map[string]time.Time{}
+	})
+
+	prev, found := t.failures[k]
+	t.failures[k] = failures
+
+	switch {
+	case !found:
+		// The time of the failures which happened before the first
+		// observation is unknown.
+	case failures > prev:
+		t.lastTimes[k] = now
+	case failures < prev:
+		// The counter has been reset (e.g. the sidecar restarted): any
+		// failure happened after the restart.
+		if failures > 0 {
+			t.lastTimes[k] = now
+		} else {
+			delete(t.lastTimes, k)
+		}
+	}
+
+	return t.lastTimes[k]
+}
+
+// reportsThanosSidecarStatus returns true if the operator can report the
+// status of the Thanos sidecars for the given Prometheus object.
+func reportsThanosSidecarStatus(p *monitoringv1.Prometheus) bool {
+	t := p.Spec.Thanos
+	if t == nil || (t.ObjectStorageConfig == nil && t.ObjectStorageConfigFile == nil) {
+		return false
+	}
+
+	// The API server can't proxy requests to the loopback interface.
+	return !t.ListenLocal && !t.HTTPListenLocal
+}
+
+// thanosSidecarStatus fetches the metrics of the Thanos sidecar containers
+// through the API server proxy and summarizes the object storage uploads.
+func thanosSidecarStatus(ctx context.Context, kclient kubernetes.Interface, tracker *thanosUploadFailureTracker, pods []*pod) (*monitoringv1.ThanosSidecarStatus, error) {
+	metrics := make([]thanosSidecarMetrics, 0, len(pods))
+	for _, p := range pods {
+		m, err := getThanosSidecarMetrics(ctx, kclient, p)
+		if err != nil {
+			return nil, err
+		}
+
+		m.lastUploadFailure = tracker.Observe(p.Namespace+"/"+p.Name, m.uploadFailures, time.Now().UTC())
+		metrics = append(metrics, m)
+	}
+
+	return makeThanosSidecarStatus(metrics), nil
+}
+
+// getThanosSidecarMetrics fetches and parses the metrics of the Thanos
+// sidecar running in the given pod.
+func getThanosSidecarMetrics(ctx context.Context, kclient kubernetes.Interface, p *pod) (thanosSidecarMetrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, thanosSidecarRequestTimeout)
+	defer cancel()
+
+	rc, err := kclient.CoreV1().Pods(p.Namespace).ProxyGet("http", p.Name, strconv.Itoa(thanosSidecarHTTPPort), "/metrics", nil).Stream(ctx)
+	if err != nil {
+		return thanosSidecarMetrics{}, errors.Wrapf(err, "failed to get metrics of Thanos sidecar in pod %s", p.Name)
+	}
+	defer rc.Close()
+
+	m, err := parseThanosSidecarMetrics(rc)
+	if err != nil {
+		return thanosSidecarMetrics{}, errors.Wrapf(err, "failed to parse metrics of Thanos sidecar in pod %s", p.Name)
+	}
+
+	return m, nil
+}
+
+// parseThanosSidecarMetrics extracts the upload metrics from the Thanos
+// sidecar metrics in text exposition format.
+func parseThanosSidecarMetrics(r io.Reader) (thanosSidecarMetrics, error) {
+	var (
+		parser expfmt.TextParser
+		m      thanosSidecarMetrics
+	)
+
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return m, err
+	}
+
+	if mf, ok := families[thanosLastSuccessfulUploadMetric]; ok {
+		var last float64
+		for _, metric := range mf.GetMetric() {
+			last = math.Max(last, metric.GetGauge().GetValue())
+		}
+
+		if last > 0 {
+			sec, dec := math.Modf(last)
+			m.lastSuccessfulUpload = time.Unix(int64(sec), int64(dec*1e9)).UTC()
+		}
+	}
+
+	if mf, ok := families[thanosUploadFailuresMetric]; ok {
+		for _, metric := range mf.GetMetric() {
+			m.uploadFailures += metric.GetCounter().GetValue()
+		}
+	}
+
+	return m, nil
+}
+
+// makeThanosSidecarStatus aggregates the metrics of all sidecars. It returns
+// nil if no sidecar attempted an upload yet.
+// A sidecar is considered disconnected from the object storage if it never
+// uploaded successfully or if its last failure is more recent than its last
+// successful upload.
+func makeThanosSidecarStatus(metrics []thanosSidecarMetrics) *monitoringv1.ThanosSidecarStatus {
+	var (
+		status    monitoringv1.ThanosSidecarStatus
+		attempted bool
+		connected = true
+	)
+
+	for _, m := range metrics {
+		if !m.lastSuccessfulUpload.IsZero() {
+			attempted = true
+			if status.LastUploadTime == nil || m.lastSuccessfulUpload.After(status.LastUploadTime.Time) {
+				status.LastUploadTime = &metav1.Time{Time: m.lastSuccessfulUpload}
+			}
+		}
+
+		if m.uploadFailures == 0 {
+			continue
+		}
+
+		attempted = true
+		if m.lastSuccessfulUpload.IsZero() || m.lastUploadFailure.After(m.lastSuccessfulUpload) {
+			connected = false
+		}
+	}
+
+	if !attempted {
+		return nil
+	}
+
+	status.ObjectStorageConnected = &connected
+	return &status
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestParseThanosSidecarMetrics(t *testing.T) {
+	m, err := parseThanosSidecarMetrics(strings.NewReader(`# HELP thanos_objstore_bucket_last_successful_upload_time Second timestamp of the last successful upload to the bucket.
+# TYPE thanos_objstore_bucket_last_successful_upload_time gauge
+thanos_objstore_bucket_last_successful_upload_time{bucket="metrics"} 1.6656e+09
+# HELP thanos_shipper_upload_failures_total Total number of block upload failures
+# TYPE thanos_shipper_upload_failures_total counter
+thanos_shipper_upload_failures_total 2
+# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 42
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !m.lastSuccessfulUpload.Equal(time.Unix(1665600000, 0)) {
+		t.Fatalf("expected last successful upload at %v, got %v", time.Unix(1665600000, 0).UTC(), m.lastSuccessfulUpload)
+	}
+
+	if m.uploadFailures != 2 {
+		t.Fatalf("expected 2 upload failures, got %v", m.uploadFailures)
+	}
+
+	if _, err := parseThanosSidecarMetrics(strings.NewReader("invalid metrics{")); err == nil {
+		t.Fatal("expected error, got none")
+	}
+}
+
+func TestMakeThanosSidecarStatus(t *testing.T) {
+	older := time.Unix(1665600000, 0).UTC()
+	newer := older.Add(2 * time.Hour)
+
+	for _, tc := range []struct {
+		name      string
+		metrics   []thanosSidecarMetrics
+		connected *bool
+		last      *time.Time
+	}{
+		{
+			name: "no pods",
+		},
+		{
+			name:    "no upload attempted",
+			metrics: []thanosSidecarMetrics{{}, {}},
+		},
+		{
+			name: "all sidecars uploaded",
+			metrics: []thanosSidecarMetrics{
+				{lastSuccessfulUpload: older},
+				{lastSuccessfulUpload: newer},
+			},
+			connected: pointer.BoolPtr(true),
+			last:      &newer,
+		},
+		{
+			name: "failure before the last upload",
+			metrics: []thanosSidecarMetrics{
+				{lastSuccessfulUpload: newer, uploadFailures: 1, lastUploadFailure: older},
+			},
+			connected: pointer.BoolPtr(true),
+			last:      &newer,
+		},
+		{
+			name: "failure at unknown time before the last upload",
+			metrics: []thanosSidecarMetrics{
+				{lastSuccessfulUpload: newer, uploadFailures: 1},
+			},
+			connected: pointer.BoolPtr(true),
+			last:      &newer,
+		},
+		{
+			name: "failure after the last upload",
+			metrics: []thanosSidecarMetrics{
+				{lastSuccessfulUpload: older},
+				{lastSuccessfulUpload: older, uploadFailures: 1, lastUploadFailure: newer},
+			},
+			connected: pointer.BoolPtr(false),
+			last:      &older,
+		},
+		{
+			name: "one sidecar failing",
+			metrics: []thanosSidecarMetrics{
+				{lastSuccessfulUpload: older},
+				{uploadFailures: 3},
+			},
+			connected: pointer.BoolPtr(false),
+			last:      &older,
+		},
+		{
+			name: "all sidecars failing",
+			metrics: []thanosSidecarMetrics{
+				{uploadFailures: 1},
+			},
+			connected: pointer.BoolPtr(false),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status := makeThanosSidecarStatus(tc.metrics)
+			if tc.connected == nil {
+				if status != nil {
+					t.Fatalf("expected no status, got %+v", status)
+				}
+				return
+			}
+
+			if status == nil || status.ObjectStorageConnected == nil {
+				t.Fatalf("expected objectStorageConnected to be %v, got %+v", *tc.connected, status)
+			}
+			if *status.ObjectStorageConnected != *tc.connected {
+				t.Fatalf("expected objectStorageConnected to be %v, got %v", *tc.connected, *status.ObjectStorageConnected)
+			}
+
+			switch {
+			case tc.last == nil && status.LastUploadTime != nil:
+				t.Fatalf("expected no last upload time, got %v", status.LastUploadTime)
+			case tc.last != nil && (status.LastUploadTime == nil || !status.LastUploadTime.Time.Equal(*tc.last)):
+				t.Fatalf("expected last upload time %v, got %v", *tc.last, status.LastUploadTime)
+			}
+		})
+	}
+}
+
+func TestThanosUploadFailureTracker(t *testing.T) {
+	var (
+		tracker thanosUploadFailureTracker
+		t0      = time.Unix(1665600000, 0).UTC()
+	)
+
+	for i, tc := range []struct {
+		failures float64
+		now      time.Time
+		expected time.Time
+	}{
+		// First observation: the time of the failures is unknown.
+		{failures: 2, now: t0},
+		// No new failure.
+		{failures: 2, now: t0.Add(time.Minute)},
+		// New failure.
+		{failures: 3, now: t0.Add(2 * time.Minute), expected: t0.Add(2 * time.Minute)},
+		{failures: 3, now: t0.Add(3 * time.Minute), expected: t0.Add(2 * time.Minute)},
+		// Counter reset with new failures.
+		{failures: 1, now: t0.Add(4 * time.Minute), expected: t0.Add(4 * time.Minute)},
+		// Counter reset without failure.
+		{failures: 0, now: t0.Add(5 * time.Minute)},
+	} {
+		if got := tracker.Observe("ns/pod", tc.failures, tc.now); !got.Equal(tc.expected) {
+			t.Fatalf("observation %d: expected last failure at %v, got %v", i, tc.expected, got)
+		}
+	}
+
+	if got := tracker.Observe("ns/other", 1, t0); !got.IsZero() {
+		t.Fatalf("expected no last failure time for a new sidecar, got %v", got)
+	}
+}
@@ -26,11 +26,14 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
 	namespacelabeler "github.com/prometheus-operator/prometheus-operator/pkg/namespace-labeler"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/blang/semver/v4"
+	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/pkg/errors"
 )
@@ -164,6 +167,25 @@ func (o *Operator) selectRuleNamespaces(p *monitoringv1.ThanosRuler) ([]string,
 	return namespaces, nil
 }
 
+// thanosRulerVersion returns the Thanos version deduced from the tag of the
+// ThanosRuler image. It returns the default Thanos version if the image has
+// no tag or if the tag isn't a valid version.
+func thanosRulerVersion(t *monitoringv1.ThanosRuler) semver.Version {
+	tag := operator.DefaultThanosVersion
+	if image := strings.SplitN(t.Spec.Image, "@", 2)[0]; image != "" {
+		if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+			tag = image[i+1:]
+		}
+	}
+
+	version, err := semver.ParseTolerant(tag)
+	if err != nil {
+		return semver.MustParse(strings.TrimPrefix(operator.DefaultThanosVersion, "v"))
+	}
+
+	return version
+}
+
 func (o *Operator) selectRules(t *monitoringv1.ThanosRuler, namespaces []string) (map[string]string, error) {
 	rules := map[string]string{}
 
@@ -189,6 +211,8 @@ func (o *Operator) selectRules(t *monitoringv1.ThanosRuler, namespaces []string)
 		false,
 	)
 
+	version := thanosRulerVersion(t)
+
 	for _, ns := range namespaces {
 		var marshalErr error
 		err := o.ruleInfs.ListAllByNamespace(ns, ruleSelector, func(obj interface{}) {
@@ -203,7 +227,10 @@ func (o *Operator) selectRules(t *monitoringv1.ThanosRuler, namespaces []string)
 				return
 			}
 
-			content, err := prometheus.GenerateContent(promRule.Spec, o.logger)
+			logger := log.With(o.logger, "namespace", promRule.Namespace, "prometheusrule", promRule.Name)
+			prometheus.DropUnsupportedThanosRuleFields(&promRule.Spec, version, logger)
+
+			content, err := prometheus.GenerateContent(promRule.Spec, logger)
 			if err != nil {
 				marshalErr = err
 				return
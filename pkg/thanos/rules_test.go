@@ -0,0 +1,46 @@
+// Copyright 2020 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestThanosRulerVersion(t *testing.T) {
+	for _, tc := range []struct {
+		image    string
+		expected string
+	}{
+		{image: "", expected: "0.28.0"},
+		{image: "quay.io/thanos/thanos", expected: "0.28.0"},
+		{image: "quay.io/thanos/thanos:v0.34.1", expected: "0.34.1"},
+		{image: "quay.io/thanos/thanos:v0.34.1@sha256:7c5e1d4d9e1b6f1c5d4e3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e", expected: "0.34.1"},
+		{image: "localhost:5000/thanos", expected: "0.28.0"},
+		{image: "localhost:5000/thanos:v0.38.0", expected: "0.38.0"},
+		{image: "quay.io/thanos/thanos:main-2023-01-01", expected: "0.28.0"},
+	} {
+		t.Run(tc.image, func(t *testing.T) {
+			v := thanosRulerVersion(&monitoringv1.ThanosRuler{
+				Spec: monitoringv1.ThanosRulerSpec{Image: tc.image},
+			})
+
+			if v.String() != tc.expected {
+				t.Fatalf("expected version %s, got %s", tc.expected, v)
+			}
+		})
+	}
+}
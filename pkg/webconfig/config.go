@@ -47,12 +47,12 @@ type Config struct {
 
 // New creates a new Config.
 func New(mountingDir string, secretName string, configFileFields monitoringv1.WebConfigFileFields) (*Config, error) {
-	tlsConfig := configFileFields.TLSConfig
-
-	if err := tlsConfig.Validate(); err != nil {
+	if err := configFileFields.Validate(); err != nil {
 		return nil, err
 	}
 
+	tlsConfig := configFileFields.TLSConfig
+
 	var tlsCreds *tlsCredentials
 	if tlsConfig != nil {
 		tlsCreds = newTLSCredentials(mountingDir, tlsConfig.KeySecret, tlsConfig.Cert, tlsConfig.ClientCA)
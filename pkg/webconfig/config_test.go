@@ -153,9 +153,9 @@ func TestCreateOrUpdateWebConfigSecret(t *testing.T) {
 					ClientAuthType:           "RequireAnyClientCert",
 					MinVersion:               "TLS11",
 					MaxVersion:               "TLS13",
-					CipherSuites:             []string{"cipher-1", "cipher-2"},
+					CipherSuites:             []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
 					PreferServerCipherSuites: &falseVal,
-					CurvePreferences:         []string{"curve-1", "curve-2"},
+					CurvePreferences:         []string{"X25519", "CurveP256"},
 				},
 			},
 			expectedData: `tls_server_config:
@@ -166,12 +166,12 @@ func TestCreateOrUpdateWebConfigSecret(t *testing.T) {
   min_version: TLS11
   max_version: TLS13
   cipher_suites:
-  - cipher-1
-  - cipher-2
+  - TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+  - TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
   prefer_server_cipher_suites: false
   curve_preferences:
-  - curve-1
-  - curve-2
+  - X25519
+  - CurveP256
 `,
 		},
 		{